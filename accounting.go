@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region CPU time accounting
+
+// systemTime returns how much of the part [from, to) of the burst of p is
+// system time.
+func (p Process) systemTime(from, to int64) int64 {
+	var sys int64
+	for _, s := range p.System {
+		if lo, hi := max(s.From, from), min(s.To, to); lo < hi {
+			sys += hi - lo
+		}
+	}
+
+	return sys
+}
+
+// cpuTime splits the time from the first arrival until the last slice ends
+// into user time, system time and idle time. Time stolen by interrupts is
+// none of these.
+func cpuTime(r Result) (user, system, idle int64) {
+	byID := make(map[int64]Process, len(r.processes))
+	start := int64(-1)
+	for _, p := range r.processes {
+		byID[p.ProcessID] = p
+		if start < 0 || p.ArrivalTime < start {
+			start = p.ArrivalTime
+		}
+	}
+
+	ran := make(map[int64]int64)
+	var end int64
+	for s := range r.Slices() {
+		d := s.Stop - s.Start
+		sys := byID[s.PID].systemTime(ran[s.PID], ran[s.PID]+d)
+		ran[s.PID] += d
+		system += sys
+		user += d - sys
+		end = max(end, s.Stop)
+	}
+	if start >= 0 && end > start {
+		idle = end - start - user - system - r.Stolen
+	}
+
+	return user, system, idle
+}
+
+// outputAccounting shows how every algorithm's CPU time splits into user,
+// system and idle time.
+func outputAccounting(w io.Writer, processes []Process, opts options) {
+	outputTitle(w, "CPU time accounting")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "User", "System", "Idle", "User %", "System %", "Idle %"})
+	for _, alg := range algorithms {
+		user, system, idle := cpuTime(alg.run(append([]Process(nil), processes...), opts))
+		total := float64(max(user+system+idle, 1))
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(user),
+			fmt.Sprint(system),
+			fmt.Sprint(idle),
+			fmt.Sprintf("%.1f%%", 100*float64(user)/total),
+			fmt.Sprintf("%.1f%%", 100*float64(system)/total),
+			fmt.Sprintf("%.1f%%", 100*float64(idle)/total),
+		})
+	}
+	table.Render()
+}
+
+//endregion
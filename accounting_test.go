@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_cpuTime(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,4,0,0,sys=1-3\n2,2,6,0,sys=0-1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, system, idle := cpuTime(fcfs(processes, options{}))
+	if got, want := [3]int64{user, system, idle}, [3]int64{3, 3, 2}; got != want {
+		t.Errorf("cpuTime() = %v, want %v", got, want)
+	}
+
+	var out bytes.Buffer
+	outputAccounting(&out, processes, options{})
+	if !strings.Contains(out.String(), "|    3 |      3 |    2 | 37.5%  | 37.5%    | 25.0%  |") {
+		t.Errorf("outputAccounting() =\n%s", out.String())
+	}
+}
+
+func Test_systemNonPreemptible(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,8,0,0,sys=4-7\n2,3,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts options
+		want []TimeSlice
+	}{
+		{"preemptible", options{}, []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 8}, {PID: 1, Start: 8, Stop: 11}}},
+		{"non-preemptible", options{systemNonPreemptible: true}, []TimeSlice{{PID: 1, Start: 0, Stop: 7}, {PID: 2, Start: 7, Stop: 10}, {PID: 1, Start: 10, Stop: 11}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := rr(processes, tt.opts).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rr() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
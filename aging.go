@@ -0,0 +1,206 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Aging
+
+type (
+	// agingConfig raises the priority of a ready process by step for every
+	// every units it has waited since it last ran, so that processes of low
+	// priority do not starve.
+	agingConfig struct {
+		every, step int64
+	}
+
+	// ager keeps track of how long ready tasks have waited, for policies
+	// that age them, and traces their effective priorities.
+	ager struct {
+		agingConfig
+		now int64
+		// since is the wait of every task when it last ran or was last
+		// promoted, from which it ages.
+		since map[int64]int64
+		trace []agingDecision
+	}
+
+	// agingDecision is a pick: when, the effective priority of every ready
+	// task, by ID, and the task dispatched.
+	agingDecision struct {
+		at         int64
+		priorities []effectivePriority
+		chosen     int64
+	}
+	effectivePriority struct {
+		pid, priority int64
+	}
+)
+
+// parseAging parses every[:step], the units a process must wait for its
+// priority to rise by step (1 unless given).
+func parseAging(s string) (*agingConfig, error) {
+	every, step, hasStep := strings.Cut(s, ":")
+	c := &agingConfig{step: 1}
+	var err error
+	if c.every, err = parseInt(every); err != nil {
+		return nil, fmt.Errorf("%w: aging %q: %w", ErrInvalidArgs, s, err)
+	}
+	if hasStep {
+		if c.step, err = parseInt(step); err != nil {
+			return nil, fmt.Errorf("%w: aging %q: %w", ErrInvalidArgs, s, err)
+		}
+	}
+	if c.every <= 0 || c.step <= 0 {
+		return nil, fmt.Errorf("%w: aging %q must wait and raise priority by positive amounts", ErrInvalidArgs, s)
+	}
+
+	return c, nil
+}
+
+func newAger(c agingConfig) *ager {
+	return &ager{agingConfig: c, since: make(map[int64]int64)}
+}
+
+func (a *ager) reset() {
+	a.now = 0
+	a.since = make(map[int64]int64)
+	a.trace = nil
+}
+
+func (a *ager) setNow(now int64) { a.now = now }
+
+// boost is how much the priority of t has risen since it last ran.
+func (a *ager) boost(t *task) int64 {
+	return a.step * ((t.wait(a.now) - a.since[t.ProcessID]) / a.every)
+}
+
+// restart ages t from now on, after it ran or was promoted.
+func (a *ager) restart(t *task) {
+	a.since[t.ProcessID] = t.wait(a.now)
+}
+
+// record adds the pick of ready[chosen] to the trace, unless the same tasks
+// had the same effective priorities and the same one was chosen at the last
+// pick.
+func (a *ager) record(ready []*task, chosen int, priority func(*task) int64) {
+	d := agingDecision{at: a.now, chosen: ready[chosen].ProcessID}
+	for _, t := range ready {
+		d.priorities = append(d.priorities, effectivePriority{pid: t.ProcessID, priority: priority(t)})
+	}
+	slices.SortFunc(d.priorities, func(a, b effectivePriority) int { return cmp.Compare(a.pid, b.pid) })
+	if n := len(a.trace); n > 0 && a.trace[n-1].chosen == d.chosen && slices.Equal(a.trace[n-1].priorities, d.priorities) {
+		return
+	}
+	a.trace = append(a.trace, d)
+}
+
+// agingPriority dispatches the ready task of the highest effective priority,
+// its rank less the boost it earned waiting. Non-preemptive, it
+// breaks ties in the order tasks became ready, like highestPriority;
+// preemptive, it reconsiders every unit and breaks ties by arrival and then
+// ID, like preemptivePriority.
+type agingPriority struct {
+	*ager
+	preemptive bool
+	// highFirst is set when higher priorities come first, so that boosts
+	// raise the priority shown rather than lower it.
+	highFirst bool
+}
+
+func (p agingPriority) priority(t *task) int64 { return t.rank - p.boost(t) }
+
+// shown is the effective priority of t as a priority rather than a rank.
+func (p agingPriority) shown(t *task) int64 {
+	if p.highFirst {
+		return -p.priority(t)
+	}
+
+	return p.priority(t)
+}
+
+func (p agingPriority) pick(ready []*task) int {
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		switch tp, bp := p.priority(t), p.priority(b); {
+		case tp != bp:
+			if tp < bp {
+				best = i
+			}
+		case !p.preemptive:
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+	p.record(ready, best, p.shown)
+	p.restart(ready[best])
+
+	return best
+}
+
+func (p agingPriority) quantum(*task) int64 {
+	if p.preemptive {
+		return 1
+	}
+
+	return 0
+}
+
+// priorityAging runs priority scheduling with the aging in opts and also
+// returns the ager, with the trace of effective priorities.
+func priorityAging(processes []Process, opts options, preemptive bool) (Result, *ager) {
+	a := newAger(*opts.aging)
+
+	return simulate(processes, agingPriority{a, preemptive, opts.highPriorityFirst}, opts), a
+}
+
+// outputAging shows the effective priority of every ready task at each
+// decision of the schedulers that age, priority and the multilevel feedback
+// queue (where it is the queue), where any of them or the choice changed.
+func outputAging(w io.Writer, processes []Process, opts options, d display) {
+	_, np := priorityAging(append([]Process(nil), processes...), opts, false)
+	_, pp := priorityAging(append([]Process(nil), processes...), opts, true)
+	_, mlfq := mlfqLevels(append([]Process(nil), processes...), opts)
+	for _, s := range []struct {
+		title string
+		trace []agingDecision
+	}{
+		{"Priority", np.trace},
+		{"Preemptive priority", pp.trace},
+		{"Multilevel feedback queue", mlfq.aging.trace},
+	} {
+		rows := make([][]string, len(s.trace))
+		for i, dec := range s.trace {
+			priorities := make([]string, len(dec.priorities))
+			for j, p := range dec.priorities {
+				priorities[j] = fmt.Sprintf("P%d=%d", p.pid, p.priority)
+			}
+			rows[i] = []string{fmt.Sprint(dec.at), strings.Join(priorities, " "), fmt.Sprint(dec.chosen)}
+		}
+		if head, tail, omitted := d.truncated(len(rows)); omitted > 0 {
+			marker := []string{"...", fmt.Sprintf("%d more", omitted), "..."}
+			rows = append(append(append([][]string(nil), rows[:head]...), marker), rows[len(rows)-tail:]...)
+		}
+
+		outputTitle(w, s.title+" with aging")
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Time", "Effective Priorities", "Dispatched"})
+		table.SetAutoWrapText(false)
+		table.AppendBulk(rows)
+		table.Render()
+	}
+	_, _ = fmt.Fprintf(w, "Ready processes rose %d in priority for every %d units waited since they last ran.\n", opts.aging.step, opts.aging.every)
+}
+
+//endregion
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseAging(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s       string
+		want    *agingConfig
+		wantErr error
+	}{
+		{"10", &agingConfig{every: 10, step: 1}, nil},
+		{"5:2", &agingConfig{every: 5, step: 2}, nil},
+		{"0", nil, ErrInvalidArgs},
+		{"5:0", nil, ErrInvalidArgs},
+		{"five", nil, ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseAging(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseAging() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAging() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_priorityAging(t *testing.T) {
+	t.Parallel()
+	// P1 has the lowest priority and a process of higher priority arrives
+	// whenever the last one finishes
+	processes, err := loadProcesses(strings.NewReader("1,2,0,9\n2,10,0,1\n3,10,10,1\n4,10,20,1\n5,10,30,1\n6,10,40,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		run   func([]Process, options) Result
+		aging *agingConfig
+		// want is when P1 first runs.
+		want int64
+	}{
+		{"starves", sjfPriority, nil, 50},
+		// By 20, P1 has waited enough to rise from 9 to -1, ahead of P4
+		{"aged", sjfPriority, &agingConfig{every: 2, step: 1}, 20},
+		// By 16, P1 has risen to 1, tied with the running P3, and wins the
+		// tie by arrival
+		{"aged preemptive", priorityPreemptive, &agingConfig{every: 2, step: 1}, 16},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, s := range tt.run(processes, options{aging: tt.aging}).Gantt {
+				if s.PID == 1 {
+					if s.Start != tt.want {
+						t.Errorf("P1 first ran at %d, want %d", s.Start, tt.want)
+					}
+					return
+				}
+			}
+		})
+	}
+}
+
+func Test_mlfqAging(t *testing.T) {
+	t.Parallel()
+	// P1 is demoted after one unit, and a new process arrives every unit
+	// to run ahead of it in the top queue
+	csv := "1,3,0\n"
+	for i := int64(2); i <= 30; i++ {
+		csv += fmt.Sprintf("%d,1,%d\n", i, i-1)
+	}
+	processes, err := loadProcesses(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	completion := func(opts options) int64 {
+		var stop int64
+		for _, s := range mlfq(processes, opts).Gantt {
+			if s.PID == 1 {
+				stop = s.Stop
+			}
+		}
+		return stop
+	}
+	config := &mlfqConfig{quanta: []int64{1, 4}}
+	starved := completion(options{mlfq: config})
+	aged := completion(options{mlfq: config, aging: &agingConfig{every: 5, step: 1}})
+	if aged >= starved {
+		t.Errorf("P1 completed at %d with aging, want before %d without", aged, starved)
+	}
+}
+
+func Test_outputAging(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,4,0,5\n2,4,0,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outputAging(&out, processes, options{aging: &agingConfig{every: 2, step: 1}}, display{})
+	for _, want := range []string{
+		"Priority with aging", "Preemptive priority with aging", "Multilevel feedback queue with aging",
+		"|    0 | P1=5 P2=1            |          2 |",
+		"|    4 | P1=3                 |          1 |",
+		"rose 1 in priority for every 2 units waited",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputAging() missing %q:\n%s", want, out.String())
+		}
+	}
+}
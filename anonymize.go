@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//region Workload anonymization
+
+// anonymizeCommand writes an anonymized copy of a CSV or SWF workload to w
+// as CSV, so problematic real workloads can be shared in bug reports.
+func anonymizeCommand(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("anonymize", flag.ContinueOnError)
+	jitter := fs.Float64("jitter", 0, "perturb bursts and gaps between arrivals by up to this fraction, e.g. 0.05")
+	seed := fs.Int64("seed", 1, "random seed for -jitter")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: anonymize takes one workload file", ErrInvalidArgs)
+	}
+	if *jitter < 0 || *jitter >= 1 {
+		return fmt.Errorf("%w: jitter %v is not in [0, 1)", ErrInvalidArgs, *jitter)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%w: error opening the file", err)
+	}
+	defer func() { _ = f.Close() }()
+	load := loadProcesses
+	if strings.EqualFold(filepath.Ext(f.Name()), ".swf") {
+		load = loadSWF
+	}
+	processes, err := load(f)
+	if err != nil {
+		return err
+	}
+
+	return writeProcesses(w, anonymize(processes, rand.New(rand.NewSource(*seed)), *jitter))
+}
+
+// anonymize renumbers processes from 1 in order of arrival, keeping parents
+// and signals pointing at the same processes, and renames tasks t1, t2 and
+// so on, classes c1, c2 and so on and groups g1, g2 and so on in order of
+// first appearance. With a positive jitter, every
+// burst and every gap between consecutive arrivals is scaled by a random
+// factor within 1±jitter, and offsets into a burst are scaled along with it.
+// Priorities, deadlines, tolerances, periods, warps and time-of-day windows are kept
+// as they are.
+func anonymize(processes []Process, r *rand.Rand, jitter float64) []Process {
+	perturb := func(v int64) int64 {
+		if jitter == 0 {
+			return v
+		}
+		return int64(math.Round(float64(v) * (1 + jitter*(2*r.Float64()-1))))
+	}
+
+	order := make([]int, len(processes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return processes[order[i]].ArrivalTime < processes[order[j]].ArrivalTime
+	})
+	ids := make(map[int64]int64, len(processes))
+	bursts := make(map[int64][2]int64, len(processes))
+	for n, i := range order {
+		p := processes[i]
+		ids[p.ProcessID] = int64(n + 1)
+		bursts[p.ProcessID] = [2]int64{p.BurstDuration, max(perturb(p.BurstDuration), 1)}
+	}
+
+	tasks := make(map[string]string)
+	classes := make(map[string]string)
+	groups := make(map[string]string)
+	out := make([]Process, 0, len(processes))
+	var prevArrival, arrival int64
+	for n, i := range order {
+		p := processes[i]
+		if n == 0 {
+			arrival = perturb(p.ArrivalTime)
+		} else {
+			arrival += perturb(p.ArrivalTime - prevArrival)
+		}
+		prevArrival = p.ArrivalTime
+
+		b := bursts[p.ProcessID]
+		burst := b[1]
+		scale := func(offset, lo, hi int64) int64 {
+			return min(max(offset*b[1]/b[0], lo), hi)
+		}
+		q := Process{
+			ProcessID:     int64(n + 1),
+			ArrivalTime:   max(arrival, 0),
+			BurstDuration: burst,
+			Priority:      p.Priority,
+			Window:        p.Window,
+			Deadline:      p.Deadline,
+			Tolerance:     p.Tolerance,
+			Period:        p.Period,
+			Jobs:          p.Jobs,
+			Warp:          p.Warp,
+			WarpLimit:     p.WarpLimit,
+			Tickets:       p.Tickets,
+			Background:    p.Background,
+		}
+		if p.Task != "" {
+			if _, ok := tasks[p.Task]; !ok {
+				tasks[p.Task] = fmt.Sprintf("t%d", len(tasks)+1)
+			}
+			q.Task = tasks[p.Task]
+		}
+		if p.Class != "" {
+			if _, ok := classes[p.Class]; !ok {
+				classes[p.Class] = fmt.Sprintf("c%d", len(classes)+1)
+			}
+			q.Class = classes[p.Class]
+		}
+		if p.Group != "" {
+			if _, ok := groups[p.Group]; !ok {
+				groups[p.Group] = fmt.Sprintf("g%d", len(groups)+1)
+			}
+			q.Group = groups[p.Group]
+		}
+		spans := func(spans []Span) []Span {
+			var scaled []Span
+			for _, s := range spans {
+				from := scale(s.From, 0, burst-1)
+				scaled = append(scaled, Span{From: from, To: scale(s.To, from+1, burst)})
+			}
+			return scaled
+		}
+		q.NonPreemptible = spans(p.NonPreemptible)
+		q.System = spans(p.System)
+		for _, y := range p.Yields {
+			if burst < 2 {
+				break
+			}
+			if y = scale(y, 1, burst-1); len(q.Yields) == 0 || q.Yields[len(q.Yields)-1] != y {
+				q.Yields = append(q.Yields, y)
+			}
+		}
+		// Replayed bursts keep their boundaries, scaled, and still add up
+		if len(p.Bursts) > 0 {
+			var end, last int64
+			for _, b := range p.Bursts[:len(p.Bursts)-1] {
+				end += b
+				if at := scale(end, 1, burst-1); at > last && burst > 1 {
+					q.Bursts = append(q.Bursts, at-last)
+					last = at
+				}
+			}
+			q.Bursts = append(q.Bursts, burst-last)
+		}
+		if p.Spawn != nil {
+			parent := bursts[p.Spawn.Parent]
+			q.Spawn = &Spawn{Parent: ids[p.Spawn.Parent], Offset: min(p.Spawn.Offset*parent[1]/parent[0], parent[1])}
+		}
+		if p.WaitOffset > 0 {
+			q.WaitOffset = scale(p.WaitOffset, 1, burst)
+		}
+		for _, a := range p.Awaits {
+			q.Awaits = append(q.Awaits, scale(a, 1, burst))
+		}
+		for _, s := range p.Signals {
+			q.Signals = append(q.Signals, Signal{PID: ids[s.PID], Offset: scale(s.Offset, 1, burst)})
+		}
+		out = append(out, q)
+	}
+
+	return out
+}
+
+// writeProcesses writes processes in the CSV format read by loadProcesses,
+// attributes included.
+func writeProcesses(w io.Writer, processes []Process) error {
+	join := func(values []int64) string {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = fmt.Sprint(v)
+		}
+		return strings.Join(parts, ";")
+	}
+
+	cw := csv.NewWriter(w)
+	for _, p := range processes {
+		row := []string{
+			fmt.Sprint(p.ProcessID),
+			fmt.Sprint(p.BurstDuration),
+			fmt.Sprint(p.ArrivalTime),
+			fmt.Sprint(p.Priority),
+		}
+		if len(p.NonPreemptible) > 0 {
+			row = append(row, "np="+joinSpans(p.NonPreemptible))
+		}
+		if len(p.System) > 0 {
+			row = append(row, "sys="+joinSpans(p.System))
+		}
+		if len(p.Yields) > 0 {
+			row = append(row, "yield="+join(p.Yields))
+		}
+		if len(p.Bursts) > 0 {
+			row = append(row, "bursts="+join(p.Bursts))
+		}
+		if p.Spawn != nil {
+			row = append(row, fmt.Sprintf("parent=%d", p.Spawn.Parent), fmt.Sprintf("spawn=%d", p.Spawn.Offset))
+		}
+		if p.WaitOffset > 0 {
+			row = append(row, fmt.Sprintf("wait=%d", p.WaitOffset))
+		}
+		if len(p.Awaits) > 0 {
+			row = append(row, "await="+join(p.Awaits))
+		}
+		if len(p.Signals) > 0 {
+			signals := make([]string, len(p.Signals))
+			for i, s := range p.Signals {
+				signals[i] = fmt.Sprint(s.PID)
+				if s.Offset != p.BurstDuration {
+					signals[i] += fmt.Sprintf("@%d", s.Offset)
+				}
+			}
+			row = append(row, "signal="+strings.Join(signals, ";"))
+		}
+		if p.Window != nil {
+			row = append(row, fmt.Sprintf("window=%d-%d", p.Window.From, p.Window.To))
+		}
+		if p.Deadline > 0 {
+			row = append(row, fmt.Sprintf("deadline=%d", p.Deadline))
+		}
+		if p.Tolerance != nil {
+			row = append(row, fmt.Sprintf("tolerance=%d", *p.Tolerance))
+		}
+		if p.Period > 0 {
+			row = append(row, fmt.Sprintf("period=%d", p.Period))
+		}
+		if p.Jobs > 0 {
+			row = append(row, fmt.Sprintf("jobs=%d", p.Jobs))
+		}
+		if p.Task != "" {
+			row = append(row, "task="+p.Task)
+		}
+		if p.Warp > 0 {
+			row = append(row, fmt.Sprintf("warp=%d", p.Warp))
+		}
+		if p.WarpLimit > 0 {
+			row = append(row, fmt.Sprintf("warp-limit=%d", p.WarpLimit))
+		}
+		if p.Class != "" {
+			row = append(row, "class="+p.Class)
+		}
+		if p.Group != "" {
+			row = append(row, "group="+p.Group)
+		}
+		if p.Tickets > 0 {
+			row = append(row, fmt.Sprintf("tickets=%d", p.Tickets))
+		}
+		if p.Background {
+			row = append(row, "background=true")
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// joinSpans formats spans as semicolon separated from-to ranges.
+func joinSpans(spans []Span) string {
+	parts := make([]string, len(spans))
+	for i, s := range spans {
+		parts[i] = fmt.Sprintf("%d-%d", s.From, s.To)
+	}
+
+	return strings.Join(parts, ";")
+}
+
+//endregion
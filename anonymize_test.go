@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_writeProcesses(t *testing.T) {
+	t.Parallel()
+	in := "7,10,0,2,np=3-6,sys=0-2;7-8,yield=2;8,wait=9,await=4,signal=9@5;9\n" +
+		"9,6,3,1,parent=7,spawn=2,window=2200-200,deadline=12,tolerance=0,task=video,warp=4,warp-limit=2,class=video,group=alice,background=true\n"
+	processes, err := loadProcesses(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := writeProcesses(&out, processes); err != nil {
+		t.Fatalf("writeProcesses() error = %v", err)
+	}
+	if out.String() != in {
+		t.Errorf("writeProcesses() = %q, want %q", out.String(), in)
+	}
+}
+
+func Test_anonymize(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader(
+		"42,10,5,2,np=3-6,yield=2;8,wait=9,await=4,signal=17@5\n" +
+			"17,6,0,1,window=1000-1200,task=internal-batch,bursts=2;4\n" +
+			"99,4,8,3,parent=42,spawn=7,class=secret-backup,group=mallory\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		jitter float64
+		want   string
+	}{
+		{
+			name: "renumbered",
+			want: "1,6,0,1,bursts=2;4,window=1000-1200,task=t1\n" +
+				"2,10,5,2,np=3-6,yield=2;8,wait=9,await=4,signal=1@5\n" +
+				"3,4,8,3,parent=2,spawn=7,class=c1,group=g1\n",
+		},
+		{name: "jittered", jitter: 0.5},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := writeProcesses(&out, anonymize(processes, rand.New(rand.NewSource(1)), tt.jitter)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != "" && out.String() != tt.want {
+				t.Errorf("anonymize() = %q, want %q", out.String(), tt.want)
+			}
+			// Whatever the jitter, the result must still be a valid workload
+			got, err := loadProcesses(strings.NewReader(out.String()))
+			if err != nil {
+				t.Fatalf("anonymized workload %q does not load: %v", out.String(), err)
+			}
+			if len(got) != len(processes) {
+				t.Errorf("anonymize() kept %d processes, want %d", len(got), len(processes))
+			}
+		})
+	}
+
+	// Without jitter only the IDs change, so the results do not
+	for _, alg := range algorithms {
+		want := alg.run(processes, options{})
+		got := alg.run(anonymize(processes, rand.New(rand.NewSource(1)), 0), options{})
+		if got.AveWait != want.AveWait || got.AveTurnaround != want.AveTurnaround {
+			t.Errorf("%s averages after anonymize() = %v, %v, want %v, %v",
+				alg.key, got.AveWait, got.AveTurnaround, want.AveWait, want.AveTurnaround)
+		}
+	}
+}
+
+func Test_anonymizeCommand(t *testing.T) {
+	t.Parallel()
+	input := filepath.Join(t.TempDir(), "w.csv")
+	if err := os.WriteFile(input, []byte("5,3,2\n8,4,0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := anonymizeCommand(&out, []string{input}); err != nil {
+		t.Fatalf("anonymizeCommand() error = %v", err)
+	}
+	if want := "1,4,0,0\n2,3,2,0\n"; !reflect.DeepEqual(out.String(), want) {
+		t.Errorf("anonymizeCommand() = %q, want %q", out.String(), want)
+	}
+
+	for _, args := range [][]string{nil, {"-jitter", "1", input}} {
+		if err := anonymizeCommand(&out, args); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("anonymizeCommand(%q) error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
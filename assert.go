@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//region Assertions
+
+// ErrAssertionFailed is returned when results do not satisfy an assertion.
+var ErrAssertionFailed = errors.New("assertion failed")
+
+type (
+	// assertion compares two operands, e.g. avg_wait[rr] < avg_wait[fcfs].
+	assertion struct {
+		line        int
+		text        string
+		left, right operand
+		op          string
+	}
+	// operand is either a metric of an algorithm's result or a number.
+	operand struct {
+		metric, key string
+		value       float64
+	}
+)
+
+var (
+	assertionOps = []string{"<=", ">=", "==", "!=", "<", ">"}
+	operandRE    = regexp.MustCompile(`^([a-z0-9_]+)\[([a-z0-9_-]+)\]$`)
+	percentileRE = regexp.MustCompile(`^p([0-9]+(?:\.[0-9]+)?)_(response|turnaround)$`)
+)
+
+// parseAssertions reads one assertion per line. Blank lines and lines
+// starting with # are ignored.
+func parseAssertions(r io.Reader) ([]assertion, error) {
+	var assertions []assertion
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		a := assertion{line: line, text: text}
+		for _, op := range assertionOps {
+			if left, right, ok := strings.Cut(text, op); ok {
+				a.op = op
+				var err error
+				if a.left, err = parseOperand(left); err != nil {
+					return nil, fmt.Errorf("%w: line %d", err, line)
+				}
+				if a.right, err = parseOperand(right); err != nil {
+					return nil, fmt.Errorf("%w: line %d", err, line)
+				}
+				break
+			}
+		}
+		if a.op == "" {
+			return nil, fmt.Errorf("%w: line %d has no comparison", ErrParse, line)
+		}
+		assertions = append(assertions, a)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading assertions: %w", ErrParse, err)
+	}
+
+	return assertions, nil
+}
+
+func parseOperand(s string) (operand, error) {
+	s = strings.TrimSpace(s)
+	if m := operandRE.FindStringSubmatch(s); m != nil {
+		o := operand{metric: m[1], key: m[2]}
+		if algorithmByKey(o.key) == nil {
+			return operand{}, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, o.key)
+		}
+		if _, err := metricValue(o.metric, Result{}); err != nil {
+			return operand{}, err
+		}
+		return o, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return operand{}, fmt.Errorf("%w: %q is neither metric[algorithm] nor a number", ErrParse, s)
+	}
+
+	return operand{value: v}, nil
+}
+
+// metricValue looks up a metric of r: avg_wait, avg_turnaround, throughput,
+// deferred_wait, yields, switches, missed_deadlines, tardiness, or
+// pNN_response, pNN_turnaround, max_response and max_turnaround.
+func metricValue(metric string, r Result) (float64, error) {
+	switch metric {
+	case "avg_wait":
+		return r.AveWait, nil
+	case "avg_turnaround":
+		return r.AveTurnaround, nil
+	case "throughput":
+		return r.AveThroughput, nil
+	case "deferred_wait":
+		return float64(r.DeferredWait), nil
+	case "yields":
+		return float64(r.Yields), nil
+	case "switches":
+		return float64(switches(r.Gantt)), nil
+	case "missed_deadlines", "tardiness":
+		dl := deadlines(r)
+		if dl == nil {
+			return 0, nil
+		}
+		if metric == "tardiness" {
+			return float64(dl.tardiness), nil
+		}
+		return float64(dl.missed), nil
+	case "events":
+		return float64(simulatorEvents(r)), nil
+	case "max_response":
+		return float64(percentile(latencies(r.processes, r.Gantt).response, 100)), nil
+	case "max_turnaround":
+		return float64(percentile(latencies(r.processes, r.Gantt).turnaround, 100)), nil
+	}
+	if m := percentileRE.FindStringSubmatch(metric); m != nil {
+		p, _ := strconv.ParseFloat(m[1], 64)
+		if p <= 0 || p > 100 {
+			return 0, fmt.Errorf("%w: percentile %s is not in (0, 100]", ErrInvalidArgs, m[1])
+		}
+		l := latencies(r.processes, r.Gantt)
+		if m[2] == "response" {
+			return float64(percentile(l.response, p)), nil
+		}
+		return float64(percentile(l.turnaround, p)), nil
+	}
+
+	return 0, fmt.Errorf("%w: unknown metric %q", ErrInvalidArgs, metric)
+}
+
+// checkAssertions runs the algorithms the assertions refer to and prints
+// whether each holds. It returns ErrAssertionFailed if any does not.
+func checkAssertions(w io.Writer, assertions []assertion, processes []Process, opts options) error {
+	results := make(map[string]Result)
+	value := func(o operand) float64 {
+		if o.metric == "" {
+			return o.value
+		}
+		r, ok := results[o.key]
+		if !ok {
+			r = algorithmByKey(o.key)(processes, opts)
+			results[o.key] = r
+		}
+		v, _ := metricValue(o.metric, r)
+		return v
+	}
+
+	failed := 0
+	for _, a := range assertions {
+		l, r := value(a.left), value(a.right)
+		var holds bool
+		switch a.op {
+		case "<":
+			holds = l < r
+		case "<=":
+			holds = l <= r
+		case ">":
+			holds = l > r
+		case ">=":
+			holds = l >= r
+		case "==":
+			holds = l == r
+		case "!=":
+			holds = l != r
+		}
+		if holds {
+			_, _ = fmt.Fprintf(w, "ok   %s\n", a.text)
+			continue
+		}
+		failed++
+		_, _ = fmt.Fprintf(w, "FAIL %s (line %d: %g %s %g)\n", a.text, a.line, l, a.op, r)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrAssertionFailed, failed, len(assertions))
+	}
+
+	return nil
+}
+
+func checkAssertionsFile(w io.Writer, name string, processes []Process, opts options) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: error opening assertions", err)
+	}
+	defer func() { _ = f.Close() }()
+	assertions, err := parseAssertions(f)
+	if err != nil {
+		return err
+	}
+
+	return checkAssertions(w, assertions, processes, opts)
+}
+
+//endregion
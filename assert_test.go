@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_parseAssertions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		text    string
+		want    int
+		wantErr error
+	}{
+		{"comments and blanks", "# expected\n\navg_wait[rr] < avg_wait[fcfs]\np95_turnaround[sjf] <= 40\n", 2, nil},
+		{"no comparison", "avg_wait[rr]\n", 0, ErrParse},
+		{"unknown algorithm", "avg_wait[unknown] < 3\n", 0, ErrInvalidArgs},
+		{"unknown metric", "fairness[rr] < 3\n", 0, ErrInvalidArgs},
+		{"bad percentile", "p0_response[rr] < 3\n", 0, ErrInvalidArgs},
+		{"not a number", "avg_wait[rr] < three\n", 0, ErrParse},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseAssertions(strings.NewReader(tt.text))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseAssertions() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("parseAssertions() = %v, want %d assertions", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkAssertions(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 6, Priority: 3},
+	}
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+		wantOut string
+	}{
+		{"holds", "avg_wait[rr] > avg_wait[fcfs]\nmax_turnaround[fcfs] == 17\nswitches[rr] != 2\n", nil, "ok   max_turnaround[fcfs] == 17\n"},
+		{"events", "events[fcfs] == 6\n", nil, "ok   events[fcfs] == 6\n"},
+		{"fails", "p50_response[fcfs] >= 5\nthroughput[sjf] > 0\n", ErrAssertionFailed, "FAIL p50_response[fcfs] >= 5 (line 1: 4 >= 5)\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertions, err := parseAssertions(strings.NewReader(tt.text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b bytes.Buffer
+			if err := checkAssertions(&b, assertions, processes, options{}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkAssertions() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(b.String(), tt.wantOut) {
+				t.Errorf("checkAssertions() output %q, want %q in it", b.String(), tt.wantOut)
+			}
+		})
+	}
+}
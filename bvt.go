@@ -0,0 +1,63 @@
+package main
+
+//region Borrowed virtual time
+
+// bvtAllowance is the context switch allowance: how long a task runs before
+// borrowed virtual time reconsiders which task to dispatch.
+const bvtAllowance = 2
+
+// borrowedVirtualTime is Duda and Cheriton's proportional-share scheduler,
+// with every task given an equal share. It dispatches the ready task with the
+// least effective virtual time, its virtual time less its warp, so
+// latency-sensitive tasks can borrow against their future share and run
+// ahead of the rest for a while without getting more CPU in the long run.
+// Ties go to the task that arrived first, then the lower ID.
+type borrowedVirtualTime struct{}
+
+// effectiveVirtualTime is t's virtual time, less its warp while it may still
+// run warped.
+func (t *task) effectiveVirtualTime() int64 {
+	if t.Warp > 0 && (t.WarpLimit == 0 || t.sinceReady < t.WarpLimit) {
+		return t.vtime - t.Warp
+	}
+
+	return t.vtime
+}
+
+func (borrowedVirtualTime) pick(ready []*task) int {
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		switch e, be := t.effectiveVirtualTime(), b.effectiveVirtualTime(); {
+		case e != be:
+			if e < be {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	return best
+}
+
+// quantum is the context switch allowance, cut short when the warp limit
+// runs out so the task is reconsidered unwarped.
+func (borrowedVirtualTime) quantum(t *task) int64 {
+	if t.Warp > 0 && t.WarpLimit > 0 && t.sinceReady < t.WarpLimit {
+		return min(bvtAllowance, t.WarpLimit-t.sinceReady)
+	}
+
+	return bvtAllowance
+}
+
+// Borrowed virtual time
+func bvt(processes []Process, opts options) Result {
+	return simulate(processes, borrowedVirtualTime{}, opts)
+}
+
+//endregion
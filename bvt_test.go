@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_bvt(t *testing.T) {
+	t.Parallel()
+	const hogs = "1,10,0\n2,10,0\n"
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			name: "equal shares",
+			csv:  hogs + "3,2,9\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4},
+				{PID: 1, Start: 4, Stop: 6}, {PID: 2, Start: 6, Stop: 8},
+				{PID: 1, Start: 8, Stop: 10}, {PID: 2, Start: 10, Stop: 12},
+				{PID: 3, Start: 12, Stop: 14},
+				{PID: 1, Start: 14, Stop: 16}, {PID: 2, Start: 16, Stop: 18},
+				{PID: 1, Start: 18, Stop: 20}, {PID: 2, Start: 20, Stop: 22},
+			},
+		},
+		{
+			name: "warp runs ahead",
+			csv:  hogs + "3,2,9,0,warp=10\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4},
+				{PID: 1, Start: 4, Stop: 6}, {PID: 2, Start: 6, Stop: 8},
+				{PID: 1, Start: 8, Stop: 10},
+				{PID: 3, Start: 10, Stop: 12},
+				{PID: 2, Start: 12, Stop: 14}, {PID: 1, Start: 14, Stop: 16},
+				{PID: 2, Start: 16, Stop: 18}, {PID: 1, Start: 18, Stop: 20},
+				{PID: 2, Start: 20, Stop: 22},
+			},
+		},
+		{
+			name: "warp limit",
+			csv:  "1,6,0\n2,4,0,0,warp=10,warp-limit=1\n",
+			want: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 1},
+				{PID: 1, Start: 1, Stop: 3}, {PID: 2, Start: 3, Stop: 5},
+				{PID: 1, Start: 5, Stop: 7}, {PID: 2, Start: 7, Stop: 8},
+				{PID: 1, Start: 8, Stop: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bvt(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bvt() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
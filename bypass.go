@@ -0,0 +1,55 @@
+package main
+
+//region FCFS with limited bypass
+
+// defaultBypass is how many shorter jobs may overtake the head of the queue
+// unless -bypass says otherwise.
+const defaultBypass = 2
+
+// limitedBypass is first-come first-served with limited bypass (FB-k): the
+// shortest ready task may overtake the one at the head of the queue if it is
+// shorter, but the head can be overtaken at most k times before it runs.
+// Tasks run to completion. It bounds how long a long job waits behind short
+// ones, unlike shortest-job-first, while sparing short jobs most of the
+// convoy effect of first-come first-served.
+type limitedBypass struct {
+	k int
+	// bypassed counts how often each task at the head has been overtaken.
+	bypassed map[int64]int
+}
+
+func (p *limitedBypass) reset() {
+	p.bypassed = make(map[int64]int)
+}
+
+func (p *limitedBypass) pick(ready []*task) int {
+	head := ready[0]
+	if p.bypassed[head.ProcessID] >= p.k {
+		return 0
+	}
+	best := 0
+	for i := range ready {
+		if ready[i].remaining < ready[best].remaining {
+			best = i
+		}
+	}
+	if best != 0 {
+		p.bypassed[head.ProcessID]++
+	}
+
+	return best
+}
+
+func (*limitedBypass) quantum(*task) int64 { return 0 }
+
+// FCFS with limited bypass, letting opts.bypass jobs overtake the head
+func fbk(processes []Process, opts options) Result {
+	k := opts.bypass
+	if k == 0 {
+		k = defaultBypass
+	}
+
+	return simulate(processes, &limitedBypass{k: k}, opts)
+}
+
+//endregion
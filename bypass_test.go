@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_fbk(t *testing.T) {
+	t.Parallel()
+	// A long job at the head of the queue with three short ones behind it
+	const workload = "1,1,0\n2,10,0\n3,3,0\n4,2,0\n5,1,0\n"
+	tests := []struct {
+		name string
+		k    int
+		want []int64
+	}{
+		{"one bypass", 1, []int64{1, 5, 2, 4, 3}},
+		{"default", 0, []int64{1, 5, 4, 2, 3}},
+		{"more bypasses than jobs", 10, []int64{1, 5, 4, 3, 2}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(workload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, s := range fbk(processes, options{bypass: tt.k}).Gantt {
+				got = append(got, s.PID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fbk() order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
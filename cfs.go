@@ -0,0 +1,115 @@
+package main
+
+//region Completely fair
+
+const (
+	// cfsLatency is the target latency of the completely fair scheduler:
+	// the period in which every ready task should run once, split between
+	// them by weight.
+	cfsLatency = 24
+	// cfsMinSlice is the least a task runs once dispatched, however many
+	// share the target latency.
+	cfsMinSlice = 3
+	// cfsNice0Weight is the weight of a task of nice 0.
+	cfsNice0Weight = 1024
+)
+
+// cfsWeights is Linux's sched_prio_to_weight: the weight of each nice value
+// from -20 to 19, every step about 1.25 times the next, so a task gets about
+// 10% more of the CPU than one a nice value lower.
+var cfsWeights = [40]int64{
+	88761, 71755, 56483, 46273, 36291,
+	29154, 23254, 18705, 14949, 11916,
+	9548, 7620, 6100, 4904, 3906,
+	3121, 2501, 1991, 1586, 1277,
+	1024, 820, 655, 526, 423,
+	335, 272, 215, 172, 137,
+	110, 87, 70, 56, 45,
+	36, 29, 23, 18, 15,
+}
+
+// completelyFair is a simplified Linux CFS. Each task accrues virtual
+// runtime as it runs, more slowly the more weight its nice value gives it,
+// and the ready task with the least runs for its weighted share of the
+// target latency. Tasks that arrive join at the least virtual runtime of
+// the tasks already ready and tasks that wake up no more than half the
+// target latency behind it, so that neither can monopolize the CPU to catch
+// up. Ties go to the task that arrived first, then the lower ID.
+type completelyFair struct {
+	vruntime map[int64]int64
+	// ready are the IDs of the tasks ready at the last pick.
+	ready []int64
+	// slice is the time slice of the task dispatched by the last pick, last,
+	// which had run lastRan then.
+	slice   int64
+	last    *task
+	lastRan int64
+}
+
+// weight returns t's CFS weight for its nice value.
+func (t *task) weight() int64 {
+	return cfsWeights[t.nice()+20]
+}
+
+func (p *completelyFair) reset() {
+	*p = completelyFair{vruntime: make(map[int64]int64)}
+}
+
+func (p *completelyFair) pick(ready []*task) int {
+	// Virtual runtime is kept in 1/cfsNice0Weight units of a nice 0 task's
+	if t := p.last; t != nil {
+		p.vruntime[t.ProcessID] += (t.ran - p.lastRan) * cfsNice0Weight * cfsNice0Weight / t.weight()
+	}
+
+	stayed := make(map[int64]bool, len(p.ready))
+	for _, pid := range p.ready {
+		stayed[pid] = true
+	}
+	low, known := int64(0), false
+	for _, t := range ready {
+		if vr := p.vruntime[t.ProcessID]; stayed[t.ProcessID] && (!known || vr < low) {
+			low, known = vr, true
+		}
+	}
+	best := 0
+	var total int64
+	p.ready = p.ready[:0]
+	for i, t := range ready {
+		p.ready = append(p.ready, t.ProcessID)
+		total += t.weight()
+		if vr, ok := p.vruntime[t.ProcessID]; known && !stayed[t.ProcessID] {
+			if !ok {
+				p.vruntime[t.ProcessID] = low
+			} else {
+				p.vruntime[t.ProcessID] = max(vr, low-cfsLatency/2*cfsNice0Weight)
+			}
+		}
+		b := ready[best]
+		switch vr, bvr := p.vruntime[t.ProcessID], p.vruntime[b.ProcessID]; {
+		case vr != bvr:
+			if vr < bvr {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+	p.last, p.lastRan = ready[best], ready[best].ran
+	p.slice = max(cfsLatency*p.last.weight()/total, cfsMinSlice)
+
+	return best
+}
+
+// quantum is the slice worked out by the last pick.
+func (p *completelyFair) quantum(*task) int64 { return p.slice }
+
+// Completely fair
+func cfs(processes []Process, opts options) Result {
+	return simulate(processes, &completelyFair{}, opts)
+}
+
+//endregion
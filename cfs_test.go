@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_cfs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			// Nice 0 weighs about three times nice 5, so it gets 18 of the 24
+			// units to P2's 5 and its virtual runtime grows a third as fast
+			name: "weights",
+			csv:  "1,20,0,0\n2,20,0,5\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 18}, {PID: 2, Start: 18, Stop: 28}, {PID: 1, Start: 28, Stop: 30}, {PID: 2, Start: 30, Stop: 40}},
+		},
+		{
+			// P2 arrives at P1's virtual runtime rather than none, so it does
+			// not preempt P1 until P1 has had its share
+			name: "arrival",
+			csv:  "1,30,0\n2,10,20\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 30}, {PID: 2, Start: 30, Stop: 40}},
+		},
+		{
+			// Nice 19's share of the target latency rounds to nothing, so P1
+			// runs the minimum slice
+			name: "min slice",
+			csv:  "1,10,0,19\n2,10,0,-20\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 13}, {PID: 1, Start: 13, Stop: 20}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cfs(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cfs() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
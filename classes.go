@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//region Process classes
+
+// processClass holds the defaults of a named class of processes, such as
+// short interactive ones or long batch jobs, for processes of that class to
+// leave out.
+type processClass struct {
+	// burst and priority are the columns' defaults, empty if the class has none.
+	burst, priority string
+	// attributes are key=value attributes, as in the workload.
+	attributes []string
+}
+
+// loadClasses reads process classes, one name,burst,priority[,key=value...]
+// row per class in the workload's format. The burst and priority may be
+// empty when the class has no default for them.
+func loadClasses(r io.Reader) (map[string]processClass, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading classes: %w", ErrParse, err)
+	}
+
+	classes := make(map[string]processClass, len(rows))
+	for i, row := range rows {
+		if len(row) < 3 || row[0] == "" {
+			return nil, fmt.Errorf("%w: class row %d must be name,burst,priority[,key=value...]", ErrParse, i+1)
+		}
+		if _, ok := classes[row[0]]; ok {
+			return nil, fmt.Errorf("%w: class %q is defined twice", ErrParse, row[0])
+		}
+		c := processClass{burst: row[1], priority: row[2]}
+		for _, a := range row[3:] {
+			if !strings.Contains(a, "=") {
+				return nil, fmt.Errorf("%w: class %q: %q is not key=value", ErrInvalidAttribute, row[0], a)
+			}
+			c.attributes = append(c.attributes, strings.TrimSpace(a))
+		}
+		classes[row[0]] = c
+	}
+
+	return classes, nil
+}
+
+func loadClassesFile(name string) (map[string]processClass, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening classes file", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return loadClasses(f)
+}
+
+// expandClasses fills in the workload rows of processes with a class= from
+// the defaults of their class: an empty burst or priority column, and any
+// attribute the row does not set itself.
+func expandClasses(r io.Reader, classes map[string]processClass) (io.Reader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV: %w", ErrParse, err)
+	}
+
+	var b bytes.Buffer
+	cw := csv.NewWriter(&b)
+	for i, row := range rows {
+		set := make(map[string]bool)
+		name := ""
+		for j := 3; j < len(row); j++ {
+			if key, value, ok := strings.Cut(strings.TrimSpace(row[j]), "="); ok {
+				set[key] = true
+				if key == "class" {
+					name = value
+				}
+			}
+		}
+		if name != "" {
+			c, ok := classes[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: row %d: unknown class %q", ErrInvalidAttribute, i+1, name)
+			}
+			row = expandClass(row, c, set)
+		}
+		if err := cw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	cw.Flush()
+
+	return &b, cw.Error()
+}
+
+// expandClass fills in one row from its class c, given the attributes the
+// row already sets.
+func expandClass(row []string, c processClass, set map[string]bool) []string {
+	row = append([]string(nil), row...)
+	if len(row) > 1 && row[1] == "" {
+		row[1] = c.burst
+	}
+	// The priority column is the fourth one, unless it holds an attribute
+	switch {
+	case c.priority == "":
+	case len(row) == 3:
+		row = append(row, c.priority)
+	case row[3] == "":
+		row[3] = c.priority
+	case strings.Contains(row[3], "="):
+		row = append(row[:3], append([]string{c.priority}, row[3:]...)...)
+	}
+	for _, a := range c.attributes {
+		if key, _, _ := strings.Cut(a, "="); !set[key] {
+			row = append(row, a)
+		}
+	}
+
+	return row
+}
+
+//endregion
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_loadClasses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]processClass
+		wantErr error
+	}{
+		{
+			"classes",
+			"# name,burst,priority\ninteractive,2,1,yield=1\nbatch,40,,background=true\n",
+			map[string]processClass{
+				"interactive": {burst: "2", priority: "1", attributes: []string{"yield=1"}},
+				"batch":       {burst: "40", attributes: []string{"background=true"}},
+			},
+			nil,
+		},
+		{"too short", "batch,40\n", nil, ErrParse},
+		{"twice", "batch,40,5\nbatch,50,5\n", nil, ErrParse},
+		{"not an attribute", "batch,40,5,background\n", nil, ErrInvalidAttribute},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := loadClasses(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadClasses() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadClasses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_expandClasses(t *testing.T) {
+	t.Parallel()
+	classes := map[string]processClass{
+		"interactive": {burst: "2", priority: "1", attributes: []string{"yield=1"}},
+		"batch":       {burst: "40", priority: "5", attributes: []string{"background=true", "class=batch"}},
+	}
+	tests := []struct {
+		name    string
+		input   string
+		want    []Process
+		wantErr error
+	}{
+		{
+			"defaults",
+			"1,,0,class=batch\n2,,1,class=interactive\n",
+			[]Process{
+				{ProcessID: 1, BurstDuration: 40, Priority: 5, Class: "batch", Background: true},
+				{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1, Priority: 1, Class: "interactive", Yields: []int64{1}},
+			},
+			nil,
+		},
+		{
+			"overridden",
+			"1,4,0,9,class=interactive,yield=3\n2,3,0\n",
+			[]Process{
+				{ProcessID: 1, BurstDuration: 4, Priority: 9, Class: "interactive", Yields: []int64{3}},
+				{ProcessID: 2, BurstDuration: 3},
+			},
+			nil,
+		},
+		{"unknown class", "1,,0,class=daemon\n", nil, ErrInvalidAttribute},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := expandClasses(strings.NewReader(tt.input), classes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expandClasses() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := loadProcesses(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandClasses() loads %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
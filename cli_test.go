@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "record the CLI sessions in testdata/sessions again")
+
+// TestCLI runs the built binary on example workloads and compares all it
+// writes to the session recorded in testdata/sessions, so that changes to
+// the tables, Gantt charts and summaries of any output mode show. Run
+// go test -run TestCLI -update to record the sessions again after an
+// intended change.
+func TestCLI(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := filepath.Join(t.TempDir(), "scheduler")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"schedules", []string{"examples/silberschatz-convoy.csv"}},
+		{"max-rows", []string{"-max-rows", "4", "examples/stallings-arrivals.csv"}},
+		{"ascii", []string{"-ascii", "example_processes.csv"}},
+		{"diff", []string{"-diff", "fcfs,rr", "examples/stallings-arrivals.csv"}},
+		{"tail", []string{"-tail", "examples/stallings-arrivals.csv"}},
+		{"srpt", []string{"-srpt", "examples/silberschatz-priority.csv"}},
+		{"stream", []string{"-stream", "examples/silberschatz-sjf.csv"}},
+		{"verify-examples", []string{"verify-examples"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got bytes.Buffer
+			got.WriteString("$ scheduler " + strings.Join(tt.args, " ") + "\n")
+			cmd := exec.Command(bin, tt.args...)
+			cmd.Stdout = &got
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("scheduler %v: %v", tt.args, err)
+			}
+
+			name := filepath.Join("testdata", "sessions", tt.name+".txt")
+			if *update {
+				if err := os.WriteFile(name, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatalf("%v; record the session with -update", err)
+			}
+			gotLines, wantLines := strings.Split(got.String(), "\n"), strings.Split(string(want), "\n")
+			for i := range max(len(gotLines), len(wantLines)) {
+				var g, w string
+				if i < len(gotLines) {
+					g = gotLines[i]
+				}
+				if i < len(wantLines) {
+					w = wantLines[i]
+				}
+				if g != w {
+					t.Fatalf("%s differs from the recorded session at line %d:\ngot:  %q\nwant: %q", tt.name, i+1, g, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+//region Determinism
+
+// referenceHashes is the file in the examples directory holding the hash of
+// every algorithm's results on every example, as computed on any platform.
+const referenceHashes = "hashes.txt"
+
+// ErrNondeterministic is returned when results on this platform differ from
+// the reference hashes.
+var ErrNondeterministic = errors.New("results differ from reference hashes")
+
+// resultDigest hashes everything an algorithm computed, with floats in full
+// precision, so that a change in the order of floating-point operations or
+// of map iteration shows even where the printed results would round it away.
+func resultDigest(r Result) string {
+	b, _ := json.Marshal(struct {
+		Schedule      [][]string
+		Gantt         []TimeSlice
+		AveWait       float64
+		AveTurnaround float64
+		AveThroughput float64
+		DeferredWait  int64
+	}{r.Schedule, r.Gantt, r.AveWait, r.AveTurnaround, r.AveThroughput, r.DeferredWait})
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// exampleHashes runs every algorithm on every example workload in dir and
+// returns the digests keyed by "example algorithm".
+func exampleHashes(dir string) (map[string]string, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no examples in %s", ErrInvalidArgs, dir)
+	}
+
+	hashes := make(map[string]string)
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		processes, err := loadProcesses(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: example %s", err, filepath.Base(name))
+		}
+		base := strings.TrimSuffix(filepath.Base(name), ".csv")
+		for _, alg := range algorithms {
+			hashes[base+" "+alg.key] = resultDigest(alg.run(processes, options{}))
+		}
+	}
+
+	return hashes, nil
+}
+
+// writeHashes writes one "example algorithm hash" line per result, sorted.
+func writeHashes(w io.Writer, hashes map[string]string) error {
+	keys := make([]string, 0, len(hashes))
+	for key := range hashes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s %s\n", key, hashes[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readHashes(r io.Reader) (map[string]string, error) {
+	hashes := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%w: line %d is not an example, an algorithm and a hash", ErrParse, line)
+		}
+		hashes[fields[0]+" "+fields[1]] = fields[2]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading hashes: %w", ErrParse, err)
+	}
+
+	return hashes, nil
+}
+
+// verifyHashes compares the results on this platform with the reference
+// hashes in dir, printing one line per example, or rewrites the reference
+// hashes if update is set.
+func verifyHashes(w io.Writer, dir string, update bool) error {
+	got, err := exampleHashes(dir)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(dir, referenceHashes)
+	if update {
+		f, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("%w: error creating reference hashes", err)
+		}
+		if err := writeHashes(f, got); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: error opening reference hashes", err)
+	}
+	defer func() { _ = f.Close() }()
+	want, err := readHashes(f)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(got))
+	for key := range got {
+		keys = append(keys, key)
+	}
+	for key := range want {
+		if _, ok := got[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	_, _ = fmt.Fprintf(w, "Platform %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	failed := 0
+	for _, key := range keys {
+		switch g, ok := got[key]; {
+		case !ok:
+			_, _ = fmt.Fprintf(w, "FAIL %s: no such example or algorithm\n", key)
+		case want[key] == "":
+			_, _ = fmt.Fprintf(w, "FAIL %s: no reference hash\n", key)
+		case g != want[key]:
+			_, _ = fmt.Fprintf(w, "FAIL %s: hash %s, want %s\n", key, g, want[key])
+		default:
+			_, _ = fmt.Fprintf(w, "ok   %s\n", key)
+			continue
+		}
+		failed++
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d results", ErrNondeterministic, failed, len(keys))
+	}
+
+	return nil
+}
+
+//endregion
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_verifyHashes(t *testing.T) {
+	t.Parallel()
+	if err := verifyHashes(io.Discard, "examples", false); err != nil {
+		t.Errorf("verifyHashes() error = %v; if the change to results is intended, run go run . verify-examples -update-hashes", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "one.csv"), []byte("1,5,0\n2,5,0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyHashes(io.Discard, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyHashes(io.Discard, dir, false); err != nil {
+		t.Errorf("verifyHashes() error = %v after updating", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "one.csv"), []byte("1,5,0\n2,6,0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyHashes(io.Discard, dir, false); !errors.Is(err, ErrNondeterministic) {
+		t.Errorf("verifyHashes() error = %v, want %v", err, ErrNondeterministic)
+	}
+}
+
+func Test_readHashes(t *testing.T) {
+	t.Parallel()
+	hashes, err := readHashes(strings.NewReader("a fcfs 01\n\nb rr 02\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashes["a fcfs"] != "01" || hashes["b rr"] != "02" || len(hashes) != 2 {
+		t.Errorf("readHashes() = %v", hashes)
+	}
+	if _, err := readHashes(strings.NewReader("a fcfs\n")); !errors.Is(err, ErrParse) {
+		t.Errorf("readHashes() error = %v, want %v", err, ErrParse)
+	}
+}
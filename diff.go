@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Gantt diff
+
+// diffWidth is the most columns a Gantt diff timeline takes up.
+const diffWidth = 72
+
+// pidSymbols are the characters standing for processes 1 to 61 in a Gantt
+// diff; other processes are shown as '#', idle time as '.' and context
+// switches as '~'.
+const pidSymbols = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// divergence is a stretch of time in which two schedules run different
+// processes. A PID of idlePID is an idle CPU.
+type divergence struct {
+	from, to int64
+	a, b     int64
+}
+
+// diffCommand parses the two algorithm keys given to -diff as "a,b".
+func diffCommand(w io.Writer, keys string, processes []Process, opts options, d display) error {
+	ka, kb, ok := strings.Cut(keys, ",")
+	if !ok {
+		return fmt.Errorf("%w: -diff takes two algorithms as a,b", ErrInvalidArgs)
+	}
+	var names [2]string
+	var results [2]Result
+	for i, key := range []string{ka, kb} {
+		for _, alg := range algorithms {
+			if alg.key == key {
+				names[i], results[i] = alg.name, alg.run(processes, opts)
+			}
+		}
+		if names[i] == "" {
+			return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, key)
+		}
+	}
+	outputGanttDiff(w, names, results[0].Gantt, results[1].Gantt, d)
+
+	return nil
+}
+
+// outputGanttDiff draws two Gantt charts for the same workload one above the
+// other on a shared time axis, marking with ^ where they run different
+// processes, then lists every stretch in which they diverge.
+func outputGanttDiff(w io.Writer, names [2]string, a, b []TimeSlice, d display) {
+	end := int64(0)
+	for _, gantt := range [][]TimeSlice{a, b} {
+		if n := len(gantt); n > 0 && gantt[n-1].Stop > end {
+			end = gantt[n-1].Stop
+		}
+	}
+	scale := (end + diffWidth - 1) / diffWidth
+	if scale < 1 {
+		scale = 1
+	}
+	cols := int((end + scale - 1) / scale)
+
+	label := len(names[0])
+	if len(names[1]) > label {
+		label = len(names[1])
+	}
+	var rows [2]strings.Builder
+	var marks strings.Builder
+	for col := 0; col < cols; col++ {
+		t := int64(col) * scale
+		pa, pb := runningAt(a, t), runningAt(b, t)
+		rows[0].WriteByte(pidSymbol(pa))
+		rows[1].WriteByte(pidSymbol(pb))
+		if pa != pb {
+			marks.WriteByte('^')
+		} else {
+			marks.WriteByte(' ')
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Gantt diff (one column per %d time units)\n", scale)
+	_, _ = fmt.Fprintf(w, "%-*s |%s|\n", label, names[0], rows[0].String())
+	_, _ = fmt.Fprintf(w, "%-*s |%s|\n", label, names[1], rows[1].String())
+	_, _ = fmt.Fprintf(w, "%-*s   %s\n", label, "", strings.TrimRight(marks.String(), " "))
+	axis, ticks := timeAxis(cols, scale)
+	_, _ = fmt.Fprintf(w, "%-*s   %s\n", label, "", axis)
+	_, _ = fmt.Fprintf(w, "%-*s   %s\n\n", label, "", ticks)
+
+	diverged := divergences(a, b)
+	if len(diverged) == 0 {
+		_, _ = fmt.Fprintf(w, "The schedules are identical\n\n")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Dispatch decisions first diverge at %d\n", diverged[0].from)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"From", "To", names[0], names[1]})
+	head, tail, omitted := d.truncated(len(diverged))
+	for i, div := range diverged {
+		if i >= head && i < len(diverged)-tail {
+			if i == head {
+				table.Append([]string{"...", "", fmt.Sprintf("%d more", omitted), "..."})
+			}
+			continue
+		}
+		table.Append([]string{fmt.Sprint(div.from), fmt.Sprint(div.to), pidLabel(div.a), pidLabel(div.b)})
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+}
+
+// timeAxis returns a ruler with a tick every ten columns and the times at
+// those ticks.
+func timeAxis(cols int, scale int64) (axis, ticks string) {
+	var a, t strings.Builder
+	for col := 0; col < cols; col++ {
+		if col%10 == 0 {
+			a.WriteByte('|')
+			if label := fmt.Sprint(int64(col) * scale); t.Len() <= col {
+				t.WriteString(strings.Repeat(" ", col-t.Len()))
+				t.WriteString(label)
+			}
+			continue
+		}
+		a.WriteByte('-')
+	}
+
+	return a.String(), t.String()
+}
+
+// divergences returns the stretches of time in which a and b run different
+// processes, merging adjacent stretches with the same pair.
+func divergences(a, b []TimeSlice) []divergence {
+	var bounds []int64
+	for _, gantt := range [][]TimeSlice{a, b} {
+		for _, s := range gantt {
+			bounds = append(bounds, s.Start, s.Stop)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	var diverged []divergence
+	for i := 1; i < len(bounds); i++ {
+		from, to := bounds[i-1], bounds[i]
+		if from == to {
+			continue
+		}
+		pa, pb := runningAt(a, from), runningAt(b, from)
+		if pa == pb {
+			continue
+		}
+		if n := len(diverged); n > 0 && diverged[n-1].to == from && diverged[n-1].a == pa && diverged[n-1].b == pb {
+			diverged[n-1].to = to
+			continue
+		}
+		diverged = append(diverged, divergence{from: from, to: to, a: pa, b: pb})
+	}
+
+	return diverged
+}
+
+// runningAt returns the process running at time t, or idlePID if the CPU is
+// idle.
+func runningAt(gantt []TimeSlice, t int64) int64 {
+	i := sort.Search(len(gantt), func(i int) bool { return gantt[i].Stop > t })
+	if i < len(gantt) && gantt[i].Start <= t {
+		return gantt[i].PID
+	}
+
+	return idlePID
+}
+
+func pidSymbol(pid int64) byte {
+	switch {
+	case pid == idlePID:
+		return '.'
+	case pid == switchPID:
+		return '~'
+	case pid > 0 && pid < int64(len(pidSymbols)):
+		return pidSymbols[pid]
+	default:
+		return '#'
+	}
+}
+
+//endregion
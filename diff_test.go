@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_divergences(t *testing.T) {
+	t.Parallel()
+	a := []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 14}, {PID: 3, Start: 14, Stop: 20}}
+	tests := []struct {
+		name string
+		b    []TimeSlice
+		want []divergence
+	}{
+		{"identical", a, nil},
+		{
+			name: "round-robin",
+			b: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10}, {PID: 3, Start: 10, Stop: 15},
+				{PID: 2, Start: 15, Stop: 19}, {PID: 3, Start: 19, Stop: 20}},
+			want: []divergence{{from: 10, to: 14, a: 2, b: 3}, {from: 15, to: 19, a: 3, b: 2}},
+		},
+		{
+			name: "idle",
+			b:    []TimeSlice{{PID: 1, Start: 2, Stop: 7}},
+			want: []divergence{{from: 0, to: 2, a: 1, b: idlePID}, {from: 5, to: 7, a: 2, b: 1}, {from: 7, to: 14, a: 2, b: idlePID}, {from: 14, to: 20, a: 3, b: idlePID}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := divergences(a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("divergences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputGanttDiff(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 6, Priority: 3},
+	}
+	var b bytes.Buffer
+	if err := diffCommand(&b, "fcfs,rr", processes, options{}, display{}); err != nil {
+		t.Fatalf("diffCommand() error = %v", err)
+	}
+	for _, want := range []string{
+		"First-come, first-serve |11111222222222333333|",
+		"Round-robin             |11111222223333322223|",
+		"                                    ^^^^ ^^^^\n",
+		"first diverge at 10",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("diffCommand() missing %q in\n%s", want, b.String())
+		}
+	}
+
+	if err := diffCommand(&b, "fcfs", processes, options{}, display{}); err == nil {
+		t.Errorf("diffCommand() with one algorithm, want an error")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import "io"
+
+//region Deficit round-robin
+
+// defaultDRRQuantum is the credit deficit round-robin gives a process per
+// visit unless set, the same as round-robin's time slice.
+const defaultDRRQuantum = 5
+
+type (
+	// deficitRoundRobin visits ready tasks in turn and adds credit to the
+	// deficit of each at its visit. A task runs its bursts one whole chunk
+	// at a time, up to the next point where it yields, blocks or ends a
+	// replayed burst, for as long as the next chunk fits in its deficit,
+	// which pays for it. The rest of the deficit carries over to the next
+	// visit, so a task whose chunks are longer than the credit runs once
+	// it has saved up for one, and over time tasks get equal CPU time
+	// however coarse or fine their chunks are. Plain round-robin instead
+	// favours tasks that use up their slice over ones that give it up early.
+	deficitRoundRobin struct {
+		credit int64
+		tasks  map[int64]*drrTask
+		seq    int
+		// visiting is the task whose visit the last pick began or went on
+		// with, which had run lastRan then.
+		visiting *task
+		lastRan  int64
+	}
+
+	drrTask struct {
+		deficit int64
+		// seq orders tasks by their turn.
+		seq int
+		// blocked is how long the task had been blocked when last seen; one
+		// that woke starts over with no deficit, as it had no work waiting.
+		blocked int64
+	}
+)
+
+// chunk returns how long t runs before it next yields, blocks, ends a
+// replayed burst or completes.
+func (t *task) chunk() int64 {
+	end := t.ran + t.remaining
+	end = min(t.blockPoint(t.ran, end), t.nextYield(t.ran, end), t.burstEnd(t.ran))
+
+	return end - t.ran
+}
+
+func (p *deficitRoundRobin) reset() {
+	*p = deficitRoundRobin{credit: p.credit, tasks: make(map[int64]*drrTask)}
+}
+
+func (p *deficitRoundRobin) pick(ready []*task) int {
+	visiting := -1
+	for i, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		switch {
+		case !ok:
+			p.seq++
+			p.tasks[t.ProcessID] = &drrTask{seq: p.seq, blocked: t.blockedFor}
+		case t.blockedFor > s.blocked:
+			p.seq++
+			s.deficit, s.seq, s.blocked = 0, p.seq, t.blockedFor
+		}
+		if t == p.visiting {
+			visiting = i
+		}
+	}
+
+	// Pay for what the task visited ran, and go on with its visit while its
+	// next chunk fits in its deficit
+	if v := p.visiting; v != nil {
+		s := p.tasks[v.ProcessID]
+		s.deficit -= v.ran - p.lastRan
+		if visiting >= 0 && v.chunk() <= s.deficit {
+			p.lastRan = v.ran
+			return visiting
+		}
+		p.seq++
+		s.seq = p.seq
+	}
+
+	// Visit tasks in turn until one can pay for its next chunk
+	for {
+		next := 0
+		for i, t := range ready {
+			if p.tasks[t.ProcessID].seq < p.tasks[ready[next].ProcessID].seq {
+				next = i
+			}
+		}
+		t := ready[next]
+		s := p.tasks[t.ProcessID]
+		if s.deficit += p.credit; t.chunk() <= s.deficit {
+			p.visiting, p.lastRan = t, t.ran
+			return next
+		}
+		p.seq++
+		s.seq = p.seq
+	}
+}
+
+// quantum is the task's whole remaining burst, so it runs on until its
+// chunk ends as it yields, blocks, ends a replayed burst or completes.
+func (*deficitRoundRobin) quantum(t *task) int64 { return t.remaining }
+
+// Deficit round-robin
+func DRRSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, drr(processes, options{}), display{})
+}
+
+// drr runs deficit round-robin with a quantum of opts.drrQuantum, or
+// defaultDRRQuantum.
+func drr(processes []Process, opts options) Result {
+	credit := opts.drrQuantum
+	if credit == 0 {
+		credit = defaultDRRQuantum
+	}
+
+	return simulate(processes, &deficitRoundRobin{credit: credit}, opts)
+}
+
+//endregion
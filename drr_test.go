@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_drr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			// P1 yields every unit and P2 every 6. P2 saves up its credit of 5
+			// for a visit, then the two take turns at about 5 units each
+			name: "unequal chunks",
+			csv: "1,30,0,0,yield=1;2;3;4;5;6;7;8;9;10;11;12;13;14;15;16;17;18;19;20;21;22;23;24;25;26;27;28;29\n" +
+				"2,30,0,0,yield=6;12;18;24\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 10}, {PID: 2, Start: 10, Stop: 16},
+				{PID: 1, Start: 16, Stop: 21}, {PID: 2, Start: 21, Stop: 27},
+				{PID: 1, Start: 27, Stop: 32}, {PID: 2, Start: 32, Stop: 38},
+				{PID: 1, Start: 38, Stop: 43}, {PID: 2, Start: 43, Stop: 49},
+				{PID: 1, Start: 49, Stop: 54}, {PID: 2, Start: 54, Stop: 60},
+			},
+		},
+		{
+			// P2's chunk of 6 does not fit in its first credit, so P3 goes
+			// ahead; P1 blocks at 2 until P2 signals it
+			name: "blocking",
+			csv:  "1,4,0,0,await=2\n2,6,0,0,signal=1@1\n3,3,1\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 3, Start: 2, Stop: 5}, {PID: 2, Start: 5, Stop: 11}, {PID: 1, Start: 11, Stop: 13}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := drr(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("drr() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_drrFairness(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,30,0,0,yield=1;2;3;4;5;6;7;8;9;10;11;12;13;14;15;16;17;18;19;20;21;22;23;24;25;26;27;28;29\n" +
+		"2,30,0,0,yield=6;12;18;24\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ranBy returns how long each process ran by 38
+	ranBy := func(gantt []TimeSlice) map[int64]int64 {
+		ran := make(map[int64]int64)
+		for _, s := range gantt {
+			ran[s.PID] += max(min(s.Stop, 38)-s.Start, 0)
+		}
+		return ran
+	}
+	if ran := ranBy(drr(processes, options{}).Gantt); ran[1] != 20 || ran[2] != 18 {
+		t.Errorf("drr() ran %v by 38, want about the same", ran)
+	}
+	// Round-robin favours P2, which uses up its slices
+	if ran := ranBy(rr(processes, options{}).Gantt); ran[2] < 3*ran[1] {
+		t.Errorf("rr() ran %v by 38, want P2 far ahead", ran)
+	}
+	// A larger credit makes for longer turns
+	if got := drr(processes, options{drrQuantum: 12}).Gantt[1]; got != (TimeSlice{PID: 2, Start: 12, Stop: 24}) {
+		t.Errorf("drr() second slice = %v with a credit of 12", got)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Earliest deadline first
+
+// earliestDeadline dispatches the ready task whose absolute deadline comes
+// first, tasks without a deadline last, breaking ties by arrival and then
+// ID. It reconsiders its choice every unit so that a newly arrived task with
+// an earlier deadline preempts the running one. Tasks with a tardiness
+// tolerance are dropped once they are later than that, as in soft real-time
+// systems where a late frame is worthless.
+type earliestDeadline struct{}
+
+// absoluteDeadline returns when t is due, or the end of time if it has no
+// deadline.
+func (t *task) absoluteDeadline() int64 {
+	if t.Deadline == 0 {
+		return math.MaxInt64
+	}
+
+	return t.ArrivalTime + t.Deadline
+}
+
+func (earliestDeadline) pick(ready []*task) int {
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		switch d, bd := t.absoluteDeadline(), b.absoluteDeadline(); {
+		case d != bd:
+			if d < bd {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (earliestDeadline) quantum(*task) int64 { return 1 }
+
+func (earliestDeadline) dropAt(t *task) (int64, bool) {
+	if t.Tolerance == nil {
+		return 0, false
+	}
+
+	return t.absoluteDeadline() + *t.Tolerance, true
+}
+
+// Earliest deadline first
+func edf(processes []Process, opts options) Result {
+	return simulate(processes, earliestDeadline{}, opts)
+}
+
+// deadlineReport says which processes of a schedule met their deadline.
+type deadlineReport struct {
+	// cells describe how each process fared against its deadline, for the
+	// schedule table: "met", "late by N" or "missed" if it did not finish.
+	cells       []string
+	met, missed int
+	// tardiness is how late the processes that finished late were in all.
+	tardiness int64
+}
+
+// deadlines reports which processes of r met their deadline, or returns nil
+// if none has one.
+func deadlines(r Result) *deadlineReport {
+	if r.completions == nil {
+		return nil
+	}
+	dl := &deadlineReport{cells: make([]string, len(r.processes))}
+	for i, p := range r.processes {
+		if p.Deadline == 0 {
+			continue
+		}
+		switch late := r.completions[i] - (p.ArrivalTime + p.Deadline); {
+		case r.completions[i] < 0:
+			dl.cells[i] = "missed"
+			dl.missed++
+		case late > 0:
+			dl.cells[i] = fmt.Sprintf("late by %d", late)
+			dl.missed++
+			dl.tardiness += late
+		default:
+			dl.cells[i] = "met"
+			dl.met++
+		}
+	}
+
+	return dl
+}
+
+// outputDrops shows, for every task, how many of its jobs earliest deadline
+// first dropped for being later than their tolerance. Processes without a
+// task name are tasks of their own.
+func outputDrops(w io.Writer, processes []Process, opts options) {
+	r := edf(append([]Process(nil), processes...), opts)
+	var order []string
+	jobs := make(map[string]int)
+	drops := make(map[string]int)
+	for _, p := range r.processes {
+		name := p.Task
+		if name == "" {
+			name = fmt.Sprint(p.ProcessID)
+		}
+		if _, ok := jobs[name]; !ok {
+			order = append(order, name)
+		}
+		jobs[name]++
+		if r.dropped[p.ProcessID] {
+			drops[name]++
+		}
+	}
+
+	outputTitle(w, "Earliest-deadline-first drops")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Task", "Jobs", "Dropped", "Drop Rate"})
+	for _, name := range order {
+		table.Append([]string{
+			name,
+			fmt.Sprint(jobs[name]),
+			fmt.Sprint(drops[name]),
+			fmt.Sprintf("%.2f%%", 100*float64(drops[name])/float64(jobs[name])),
+		})
+	}
+	table.Render()
+}
+
+//endregion
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_edf(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		csv       string
+		wantGantt []TimeSlice
+		wantDrops map[int64]bool
+	}{
+		{
+			name: "earlier deadline preempts",
+			csv:  "1,6,0,0,deadline=20\n2,2,1,0,deadline=4\n",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 3},
+				{PID: 1, Start: 3, Stop: 8},
+			},
+		},
+		{
+			name: "no deadline runs last",
+			csv:  "1,3,0\n2,2,1,0,deadline=10\n",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 3},
+				{PID: 1, Start: 3, Stop: 5},
+			},
+		},
+		{
+			name: "late job dropped past its tolerance",
+			csv:  "1,5,0,0,deadline=5\n2,4,0,0,deadline=6,tolerance=1\n3,2,6,0,deadline=4,tolerance=0\n",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 7},
+				{PID: 3, Start: 7, Stop: 9},
+			},
+			wantDrops: map[int64]bool{2: true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := edf(processes, options{})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("edf() gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(got.dropped, tt.wantDrops) {
+				t.Errorf("edf() dropped = %v, want %v", got.dropped, tt.wantDrops)
+			}
+		})
+	}
+}
+
+func Test_outputDrops(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader(
+		"1,3,0,0,deadline=3,tolerance=0,task=video\n" +
+			"2,3,0,0,deadline=3,tolerance=0,task=video\n" +
+			"3,1,0,0,deadline=10\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outputDrops(&out, processes, options{})
+	for _, want := range []string{"| video |    2 |       1 | 50.00%    |", "|     3 |    1 |       0 | 0.00%     |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputDrops() missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func Test_deadlines(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		run  func([]Process, options) Result
+		want *deadlineReport
+	}{
+		{
+			name: "late",
+			csv:  "1,6,0,0,deadline=20\n2,2,1,0,deadline=5\n3,1,2\n",
+			run:  fcfs,
+			want: &deadlineReport{cells: []string{"met", "late by 2", ""}, met: 1, missed: 1, tardiness: 2},
+		},
+		{
+			name: "dropped",
+			csv:  "1,5,0,0,deadline=5\n2,4,0,0,deadline=6,tolerance=1\n3,2,6,0,deadline=4,tolerance=0\n",
+			run:  edf,
+			want: &deadlineReport{cells: []string{"met", "missed", "met"}, met: 2, missed: 1},
+		},
+		{
+			name: "no deadlines",
+			csv:  "1,6,0\n",
+			run:  fcfs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := deadlines(tt.run(processes, options{})); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("deadlines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	processes, err := loadProcesses(strings.NewReader(tests[0].csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outputResult(&out, "First-come, first-serve", fcfs(processes, options{}), display{})
+	for _, want := range []string{"DEADLINE", "| late by 2 ", "MISSED 1 OF 2 |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputResult() is missing %q in\n%s", want, out.String())
+		}
+	}
+	r := fcfs(processes, options{})
+	if v, _ := metricValue("tardiness", r); v != 2 {
+		t.Errorf("metricValue(tardiness) = %v, want 2", v)
+	}
+	if v, _ := metricValue("missed_deadlines", r); v != 1 {
+		t.Errorf("metricValue(missed_deadlines) = %v, want 1", v)
+	}
+}
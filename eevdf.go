@@ -0,0 +1,172 @@
+package main
+
+//region Earliest eligible virtual deadline first
+
+// eevdfSlice is the request of every task under EEVDF: how long it asks to
+// run each time, like Linux's base slice.
+const eevdfSlice = 3
+
+type (
+	// earliestEligible is a simplified EEVDF, the successor to CFS in Linux.
+	// Tasks accrue virtual runtime by weight as under the completely fair
+	// scheduler. A task is eligible while its virtual runtime, its eligible
+	// time, is no later than the weighted average of the ready tasks', so it
+	// has had no more than its share, and each request of eevdfSlice units
+	// has a virtual deadline: where its virtual runtime will be once the
+	// request is served. The eligible task with the earliest deadline runs
+	// until its request is served, unless a task that arrived or woke is
+	// eligible with an earlier deadline still. Ties go to the task that
+	// arrived first, then the lower ID.
+	//
+	// A task that leaves keeps its lag, how far behind or ahead of the
+	// average it was, up to two requests' worth, and rejoins that far from
+	// the average then; a new task joins at the average.
+	earliestEligible struct {
+		tasks map[int64]*eevdfTask
+		// ready are the IDs of the tasks ready at the last pick, and avg was
+		// their average virtual runtime.
+		ready map[int64]bool
+		avg   int64
+		// last is the task dispatched by the last pick, which had run
+		// lastRan then.
+		last    *task
+		lastRan int64
+	}
+
+	eevdfTask struct {
+		// vruntime is in 1/cfsNice0Weight units of a nice 0 task's CPU time,
+		// as under the completely fair scheduler, and deadline is that of
+		// the current request, of which used units have been served.
+		vruntime, deadline, used int64
+		// lag is the average less vruntime at the last pick the task was
+		// ready for.
+		lag int64
+	}
+)
+
+// virtual returns d units of t's CPU time in virtual runtime.
+func (t *task) virtual(d int64) int64 {
+	return d * cfsNice0Weight * cfsNice0Weight / t.weight()
+}
+
+func (p *earliestEligible) reset() {
+	*p = earliestEligible{tasks: make(map[int64]*eevdfTask), ready: make(map[int64]bool)}
+}
+
+// average returns the weighted average virtual runtime of the tasks in
+// ready, for which it is computed from the least so that it does not
+// overflow, or avg if there are none.
+func (p *earliestEligible) average(ready []*task) int64 {
+	if len(ready) == 0 {
+		return p.avg
+	}
+	low := p.tasks[ready[0].ProcessID].vruntime
+	for _, t := range ready {
+		low = min(low, p.tasks[t.ProcessID].vruntime)
+	}
+	var sum, weight int64
+	for _, t := range ready {
+		sum += (p.tasks[t.ProcessID].vruntime - low) * t.weight()
+		weight += t.weight()
+	}
+
+	return low + sum/weight
+}
+
+func (p *earliestEligible) pick(ready []*task) int {
+	if t := p.last; t != nil {
+		s := p.tasks[t.ProcessID]
+		d := t.ran - p.lastRan
+		s.vruntime += t.virtual(d)
+		s.used += d
+	}
+
+	// Tasks that arrived or woke are placed by their lag from the average
+	// of those that stayed
+	var stayed, joined []*task
+	for _, t := range ready {
+		if p.ready[t.ProcessID] {
+			stayed = append(stayed, t)
+		} else {
+			joined = append(joined, t)
+		}
+	}
+	avg := p.average(stayed)
+	for _, t := range joined {
+		s, ok := p.tasks[t.ProcessID]
+		if !ok {
+			s = &eevdfTask{}
+			p.tasks[t.ProcessID] = s
+		}
+		limit := 2 * t.virtual(eevdfSlice)
+		s.vruntime = avg - min(max(s.lag, -limit), limit)
+		s.deadline, s.used = s.vruntime+t.virtual(eevdfSlice), 0
+	}
+	// Served requests are followed by the next
+	for _, t := range stayed {
+		if s := p.tasks[t.ProcessID]; s.used >= eevdfSlice {
+			s.deadline, s.used = s.vruntime+t.virtual(eevdfSlice), 0
+		}
+	}
+
+	p.avg = p.average(ready)
+	clear(p.ready)
+	for _, t := range ready {
+		p.ready[t.ProcessID] = true
+		p.tasks[t.ProcessID].lag = p.avg - p.tasks[t.ProcessID].vruntime
+	}
+
+	best := -1
+	for i, t := range ready {
+		s := p.tasks[t.ProcessID]
+		if s.vruntime > p.avg {
+			continue
+		}
+		if best < 0 {
+			best = i
+			continue
+		}
+		b := ready[best]
+		switch d, bd := s.deadline, p.tasks[b.ProcessID].deadline; {
+		case d != bd:
+			if d < bd {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	// The last task keeps the CPU until its request is served, unless one
+	// that joined wins
+	if last := p.last; last != nil && p.ready[last.ProcessID] && p.tasks[last.ProcessID].used > 0 {
+		joiner := false
+		for _, t := range joined {
+			joiner = joiner || t == ready[best]
+		}
+		if !joiner {
+			for i, t := range ready {
+				if t == last {
+					best = i
+				}
+			}
+		}
+	}
+	p.last, p.lastRan = ready[best], ready[best].ran
+
+	return best
+}
+
+// quantum is a unit, so that tasks that arrive or wake may preempt.
+func (*earliestEligible) quantum(*task) int64 { return 1 }
+
+// Earliest eligible virtual deadline first
+func eevdf(processes []Process, opts options) Result {
+	return simulate(processes, &earliestEligible{}, opts)
+}
+
+//endregion
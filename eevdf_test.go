@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_eevdf(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			// Equal tasks take turns a request at a time
+			name: "requests",
+			csv:  "1,6,0,0\n2,6,0,0\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 6}, {PID: 1, Start: 6, Stop: 9}, {PID: 2, Start: 9, Stop: 12}},
+		},
+		{
+			// Nice -5 weighs about three times nice 0, so P2's request ends
+			// sooner in virtual time than P1's and it preempts P1 on arrival.
+			// Ahead of the average then, it still finishes its request, and
+			// P1 runs the rest of its own before P2 is eligible again
+			name: "arrival",
+			csv:  "1,30,0,0\n2,6,10,-5\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 10}, {PID: 2, Start: 10, Stop: 13}, {PID: 1, Start: 13, Stop: 15}, {PID: 2, Start: 15, Stop: 18}, {PID: 1, Start: 18, Stop: 36}},
+		},
+		{
+			// Nice 0 weighs about three times nice 5, so P1 is eligible for
+			// about three requests to each of P2's
+			name: "weights",
+			csv:  "1,20,0,0\n2,20,0,5\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 6}, {PID: 1, Start: 6, Stop: 15}, {PID: 2, Start: 15, Stop: 18}, {PID: 1, Start: 18, Stop: 26}, {PID: 2, Start: 26, Stop: 40}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := eevdf(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("eevdf() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
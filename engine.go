@@ -0,0 +1,1006 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+//region Simulation engine
+
+type (
+	// task is the engine's view of a process while it is being scheduled.
+	task struct {
+		Process
+		// rank is the priority in the order schedulers compare, lowest
+		// first; see options.rank.
+		rank int64
+		// remaining is the part of the burst still to run and ran the part
+		// already run.
+		remaining int64
+		ran       int64
+		// readyAt is when the task may first join the ready queue: its
+		// arrival, or the next opening of its time-of-day window.
+		readyAt int64
+		// firstRun is the time the task was first dispatched, or -1.
+		firstRun   int64
+		completion int64
+		finished   bool
+		killed     bool
+		// dropped is set on killed tasks the policy dropped.
+		dropped bool
+		// stuck is set on tasks still blocked when nothing else can run.
+		stuck bool
+		// parent is the task that spawned this one and live counts the
+		// spawned children still running.
+		parent *task
+		live   int
+		// received counts the signals received and not yet awaited.
+		received int
+		// blocked says what the task is waiting for, if anything, and
+		// blockedFor is the total time it spent blocked.
+		blocked      blockReason
+		blockedSince int64
+		blockedFor   int64
+		// vtime is the task's virtual time, the CPU time it has received
+		// counted from when it first became ready, for policies that
+		// schedule by it. sinceReady is the CPU time received since the
+		// task last arrived or woke.
+		vtime      int64
+		sinceReady int64
+		// cpuBurst is the CPU time received since the task last gave up the
+		// CPU of its own accord, by blocking, yielding, ending a replayed
+		// burst or completing. lastBurst and bursts are the length and count
+		// of the CPU bursts so ended, for policies that predict them.
+		cpuBurst, lastBurst int64
+		bursts              int
+	}
+
+	// blockReason is why a task left the ready queue without finishing.
+	blockReason int
+
+	// policy is a scheduling algorithm driven by the engine. The engine asks
+	// the policy which of the tasks offered by the ready queue to dispatch,
+	// and for how long.
+	policy interface {
+		// pick returns the index in ready of the task to dispatch next.
+		pick(ready []*task) int
+		// quantum returns how long t may run before it is preempted, or 0 to
+		// let it run until its burst is done.
+		quantum(t *task) int64
+	}
+
+	// stateful is a policy that keeps state of its own between picks. The
+	// engine resets it before every run.
+	stateful interface {
+		reset()
+	}
+
+	// clocked is a policy that needs to know the time of each pick, which
+	// the engine tells it just before.
+	clocked interface {
+		setNow(now int64)
+	}
+
+	// dropper is a policy that abandons tasks it can no longer usefully
+	// finish, e.g. jobs too far past their deadline.
+	dropper interface {
+		// dropAt returns when t is dropped if it has not completed by then.
+		dropAt(t *task) (int64, bool)
+	}
+
+	// clock is the engine's simulated time. The engine only moves it forward,
+	// when the CPU idles until the next arrival or event and when a slice
+	// runs, so a clock may pace or display the run as it goes, or let a test
+	// step through it.
+	clock interface {
+		now() int64
+		// advance moves time forward to t.
+		advance(t int64)
+	}
+
+	// instantClock jumps straight to each new time.
+	instantClock struct{ t int64 }
+
+	// engine is a discrete-event simulation of a single CPU.
+	engine struct {
+		policy policy
+		opts   options
+		// tasks are ordered by readyAt and next indexes the first one that
+		// is not ready yet.
+		tasks []*task
+		next  int
+		ready readyQueue
+		// blocked are tasks waiting on their children or a signal.
+		blocked []*task
+		clock   clock
+		done    int
+		gantt   []TimeSlice
+		// reasons says why each slice in gantt ended.
+		reasons []string
+		// emit, when set, is given each Gantt slice instead of gantt. The
+		// last slice is held back until it can no longer be extended, and
+		// stopped is set once emit returns false.
+		emit    func(TimeSlice) bool
+		last    TimeSlice
+		held    bool
+		stopped bool
+		// byID holds every task, including children not spawned yet.
+		byID map[int64]*task
+		// children are the tasks each parent has yet to spawn, by parent ID.
+		children map[int64][]*task
+		// kills holds the kill time of each process, and events the timed
+		// events in time order with pending indexing the first not yet applied.
+		kills   map[int64]int64
+		events  []Event
+		pending int
+		// drops holds the time the policy drops each process, which also
+		// counts as a kill.
+		drops map[int64]int64
+
+		yields int
+		// dispatches counts the times a task was given the CPU.
+		dispatches int
+		// stolen is the time interrupts took from running tasks.
+		stolen int64
+		// err is why the run could not complete, if it could not.
+		err error
+		// logged counts the engine events, for the limit on them.
+		logged int
+		// keepAlive is alive as a func value, made once instead of on every
+		// filter of the ready queue.
+		keepAlive func(t *task) bool
+		// depth is the ready queue depth over time.
+		depth []depthSample
+		// svt is the scheduler virtual time, the virtual time of the last
+		// task dispatched. Tasks that arrive or wake catch up to it, so time
+		// spent away does not count as credit.
+		svt int64
+		// invariants, if set, checks the engine after every event.
+		invariants *invariants
+	}
+
+	// depthSample is the ready queue depth from a time until the next sample.
+	depthSample struct {
+		time  int64
+		depth int
+	}
+)
+
+// Reasons a Gantt slice ended.
+const (
+	sliceExit    = "exit"
+	sliceKilled  = "killed"
+	sliceDropped = "dropped"
+	sliceBlocked = "blocked"
+	sliceYield   = "yield"
+	// sliceBurst ends a slice at the end of one of the bursts a process
+	// replays from a trace.
+	sliceBurst = "burst"
+	// sliceInterrupted ends a slice when an interrupt steals the CPU; the
+	// task runs on once the handler is done.
+	sliceInterrupted = "interrupted"
+	slicePreempted   = "preempted"
+)
+
+const (
+	notBlocked blockReason = iota
+	// waitingChildren blocks until every spawned child has completed.
+	waitingChildren
+	// awaitingSignal blocks until another process or an event signals.
+	awaitingSignal
+)
+
+// simulate runs processes under p and returns the resulting schedule. The
+// caller's slice is not modified. With opts.lazy the Gantt chart is not kept;
+// the result streams it instead.
+func simulate(processes []Process, p policy, opts options) Result {
+	if opts.lazy {
+		return simulateLazy(processes, p, opts)
+	}
+	free, regions := regionFreeWait(processes, p, opts)
+	e := newEngine(processes, p, opts)
+	e.run()
+	r := e.result()
+	if regions {
+		r.DeferredWait = r.totalWait() - free
+	}
+
+	return r
+}
+
+// simulateLazy runs processes under p for the statistics only. The result's
+// Slices runs the simulation again to stream the Gantt chart, so no more than
+// one slice is held in memory at a time however long the schedule is.
+func simulateLazy(processes []Process, p policy, opts options) Result {
+	processes = append([]Process(nil), processes...)
+	free, regions := regionFreeWait(processes, p, opts)
+	e := newEngine(processes, p, opts)
+	e.emit = func(TimeSlice) bool { return true }
+	e.run()
+	r := e.result()
+	r.Gantt = nil
+	if regions {
+		r.DeferredWait = r.totalWait() - free
+	}
+	r.stream = func(yield func(TimeSlice) bool) {
+		e := newEngine(processes, p, opts)
+		e.emit = yield
+		e.run()
+	}
+
+	return r
+}
+
+// regionFreeWait runs processes under p with every non-preemptible region
+// removed, and returns their total wait, so that the wait the regions add is
+// that of the real run less this one, as the tick and interrupt tables
+// compare whole runs. Counting the time each deferred preemption held up the
+// ready processes would charge policies that would never have preempted. It
+// reports false, without running anything, when no process has a region.
+// The run comes first so that p is left with the state of the real one.
+func regionFreeWait(processes []Process, p policy, opts options) (int64, bool) {
+	var free []Process
+	for i := range processes {
+		if len(processes[i].NonPreemptible) > 0 || opts.systemNonPreemptible && len(processes[i].System) > 0 {
+			free = append([]Process(nil), processes...)
+			break
+		}
+	}
+	if free == nil {
+		return 0, false
+	}
+	for i := range free {
+		free[i].NonPreemptible = nil
+	}
+	opts.systemNonPreemptible = false
+	opts.onSlice, opts.onExit, opts.onEvent, opts.clock = nil, nil, nil, nil
+	e := newEngine(free, p, opts)
+	e.emit = func(TimeSlice) bool { return true }
+	e.run()
+	r := e.result()
+
+	return r.totalWait(), true
+}
+
+func newEngine(processes []Process, p policy, opts options) *engine {
+	e := &engine{
+		policy:   p,
+		opts:     opts,
+		clock:    &instantClock{},
+		ready:    &fifoQueue{},
+		tasks:    make([]*task, 0, len(processes)),
+		gantt:    make([]TimeSlice, 0),
+		byID:     make(map[int64]*task, len(processes)),
+		children: make(map[int64][]*task),
+		kills:    make(map[int64]int64),
+		drops:    make(map[int64]int64),
+		events:   append([]Event(nil), opts.events...),
+	}
+	e.keepAlive = e.alive
+	e.emit = opts.onSlice
+	if opts.debugInvariants {
+		e.invariants = newInvariants()
+	}
+	if s, ok := p.(stateful); ok {
+		s.reset()
+	}
+	if opts.clock != nil {
+		e.clock = opts.clock()
+	}
+	if opts.queue != nil {
+		e.ready = opts.queue()
+	}
+	// One slab holds every task, rather than an allocation each
+	slab := make([]task, len(processes))
+	for i := range processes {
+		t := &slab[i]
+		*t = task{
+			Process:   processes[i],
+			rank:      opts.rank(processes[i]),
+			remaining: processes[i].BurstDuration,
+			firstRun:  -1,
+		}
+		t.readyAt = e.opening(t.Window, t.ArrivalTime)
+		if opts.systemNonPreemptible && len(t.System) > 0 {
+			t.NonPreemptible = append(append([]Span(nil), t.NonPreemptible...), t.System...)
+		}
+		e.byID[t.ProcessID] = t
+		if s := processes[i].Spawn; s != nil {
+			e.children[s.Parent] = append(e.children[s.Parent], t)
+			continue
+		}
+		e.tasks = append(e.tasks, t)
+	}
+	sort.SliceStable(e.tasks, func(i, j int) bool {
+		return e.tasks[i].readyAt < e.tasks[j].readyAt
+	})
+	sort.SliceStable(e.events, func(i, j int) bool {
+		return e.events[i].Time < e.events[j].Time
+	})
+	for _, ev := range e.events {
+		if k, ok := e.kills[ev.PID]; ev.Kind == eventKill && (!ok || ev.Time < k) {
+			e.kills[ev.PID] = ev.Time
+		}
+	}
+	for _, t := range e.tasks {
+		e.scheduleDrop(t)
+	}
+
+	return e
+}
+
+// scheduleDrop makes the policy's drop of t, if any, a kill at that time,
+// unless t is killed earlier anyway.
+func (e *engine) scheduleDrop(t *task) {
+	d, ok := e.policy.(dropper)
+	if !ok {
+		return
+	}
+	at, ok := d.dropAt(t)
+	if !ok {
+		return
+	}
+	e.drops[t.ProcessID] = at
+	if k, ok := e.kills[t.ProcessID]; !ok || at < k {
+		e.kills[t.ProcessID] = at
+	}
+}
+
+// run simulates until every task has finished or is stuck, or the consumer
+// of streamed slices stops.
+func (e *engine) run() {
+	for e.done < len(e.tasks) && !e.stopped {
+		if ctx := e.opts.ctx; ctx != nil && ctx.Err() != nil {
+			e.err = fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+			return
+		}
+		if err := e.overLimit(); err != nil {
+			e.err = err
+			return
+		}
+		e.admit()
+		if e.ready.len() == 0 {
+			// Sleep until the next arrival or event, if there is one
+			next := int64(-1)
+			if e.next < len(e.tasks) {
+				next = e.tasks[e.next].readyAt
+			}
+			if e.pending < len(e.events) && (next < 0 || e.events[e.pending].Time < next) {
+				next = e.events[e.pending].Time
+			}
+			if next < 0 {
+				break
+			}
+			e.clock.advance(next)
+			continue
+		}
+		candidates := e.ready.candidates()
+		if c, ok := e.policy.(clocked); ok {
+			c.setNow(e.clock.now())
+		}
+		t := candidates[e.policy.pick(candidates)]
+		e.ready.remove(t)
+		e.svt = max(e.svt, t.vtime)
+		e.sampleDepth(e.clock.now())
+		e.dispatch(t)
+	}
+
+	// Whatever is still blocked can never wake up. Every such task is
+	// marked stuck before any is finished, so that a stuck child finishing
+	// does not wake its stuck parent
+	stuck := e.blocked
+	e.blocked = nil
+	for _, t := range stuck {
+		t.stuck = true
+		if e.err == nil {
+			e.err = fmt.Errorf("%w: process %d blocked for good", ErrUnschedulable, t.ProcessID)
+		}
+	}
+	for _, t := range stuck {
+		t.blockedFor += e.clock.now() - t.blockedSince
+		e.finish(t, e.clock.now(), false)
+	}
+	if e.emit != nil && e.held && !e.stopped {
+		e.emit(e.last)
+	}
+	// The last slice may also have run past the limits
+	if e.err == nil {
+		e.err = e.overLimit()
+	}
+}
+
+func (c *instantClock) now() int64      { return c.t }
+func (c *instantClock) advance(t int64) { c.t = t }
+
+// admit applies the events due by now, moves every task that has arrived by
+// now onto the ready queue and removes waiting tasks that have been killed.
+func (e *engine) admit() {
+	for ; e.pending < len(e.events) && e.events[e.pending].Time <= e.clock.now(); e.pending++ {
+		if ev := e.events[e.pending]; ev.Kind == eventSignal {
+			e.signal(ev.PID, ev.Time)
+		}
+	}
+
+	for e.next < len(e.tasks) && e.tasks[e.next].readyAt <= e.clock.now() {
+		t := e.tasks[e.next]
+		e.next++
+		if k, ok := e.kills[t.ProcessID]; ok && k <= t.readyAt {
+			if k < t.ArrivalTime {
+				k = t.ArrivalTime
+			}
+			e.log(engineEvent{Clock: k, Type: "arrive", PID: t.ProcessID})
+			e.finish(t, k, true)
+			continue
+		}
+		e.join(t, t.readyAt)
+		e.log(engineEvent{Clock: t.readyAt, Type: "arrive", PID: t.ProcessID, Details: eventDetails{Ready: e.ready.len()}})
+	}
+
+	e.ready.filter(e.keepAlive)
+	e.sampleDepth(e.clock.now())
+	kept := e.blocked[:0]
+	for _, t := range e.blocked {
+		if e.alive(t) {
+			kept = append(kept, t)
+		}
+	}
+	e.blocked = kept
+}
+
+// alive reports whether t has not been killed by now, finishing it if it has.
+func (e *engine) alive(t *task) bool {
+	k, ok := e.kills[t.ProcessID]
+	if !ok || k > e.clock.now() {
+		return true
+	}
+	if t.blocked != notBlocked {
+		t.blockedFor += k - t.blockedSince
+	}
+	e.finish(t, k, true)
+
+	return false
+}
+
+// dispatch runs t until its quantum expires, it yields, blocks or is killed,
+// or its burst is done.
+func (e *engine) dispatch(t *task) {
+	e.dispatches++
+	run := t.remaining
+	yielded := false
+	if q := e.policy.quantum(t); q > 0 {
+		if q < run {
+			run = q
+		}
+		// Never preempt a process before it has run the minimum granularity
+		if g := e.opts.minGranularity; g > run {
+			run = g
+			if run > t.remaining {
+				run = t.remaining
+			}
+		}
+		// The timer only notices an expired quantum on its next tick
+		if end := nextTick(e.clock.now()+run, e.opts.tick) - e.clock.now(); end > run {
+			run = min(end, t.remaining)
+		}
+		// Hold off the preemption until the process leaves any non-preemptible region
+		if end := t.preemptionPoint(t.ran + run); end > t.ran+run {
+			run = end - t.ran
+		}
+		// A voluntary yield ends the slice early
+		if y := t.nextYield(t.ran, t.ran+run); y < t.ran+run {
+			run = y - t.ran
+			yielded = true
+		}
+	}
+
+	// A replayed trace gives up the CPU at the end of each of its bursts
+	burstEnded := false
+	if end := t.burstEnd(t.ran); end <= t.ran+run {
+		run = end - t.ran
+		burstEnded, yielded = true, false
+	}
+
+	if t.firstRun < 0 {
+		t.firstRun = e.clock.now()
+	}
+	e.log(engineEvent{Clock: e.clock.now(), Type: "dispatch", PID: t.ProcessID, Details: eventDetails{Run: run, Remaining: t.remaining, Ready: e.ready.len()}})
+
+	// Run up to each point where the task may block on the way
+	for end := t.ran + run; t.ran < end; {
+		stop := t.blockPoint(t.ran, end)
+		interrupt, interrupted := e.nextInterrupt(e.clock.now() + stop - t.ran)
+		if interrupted {
+			stop = t.ran + interrupt - e.clock.now()
+		}
+		if k, ok := e.kills[t.ProcessID]; ok && k < e.clock.now()+stop-t.ran {
+			e.advance(t, max(k-e.clock.now(), 0))
+			if d, ok := e.drops[t.ProcessID]; ok && d == k {
+				e.ended(t, sliceDropped)
+			} else {
+				e.ended(t, sliceKilled)
+			}
+			e.finish(t, e.clock.now(), true)
+			return
+		}
+		e.advance(t, stop-t.ran)
+		if interrupted {
+			// The interrupt handler takes the CPU for a unit
+			e.ended(t, sliceInterrupted)
+			e.log(engineEvent{Clock: e.clock.now(), Type: "interrupt", PID: t.ProcessID})
+			e.clock.advance(e.clock.now() + 1)
+			e.stolen++
+			continue
+		}
+		if e.block(t) {
+			t.endBurst()
+			e.ended(t, sliceBlocked)
+			return
+		}
+	}
+
+	if t.remaining == 0 || burstEnded || yielded {
+		t.endBurst()
+	}
+	if t.remaining == 0 {
+		e.ended(t, sliceExit)
+		e.finish(t, e.clock.now(), false)
+		return
+	}
+	switch {
+	case burstEnded:
+		e.ended(t, sliceBurst)
+	case yielded:
+		e.yields++
+		e.ended(t, sliceYield)
+	default:
+		e.ended(t, slicePreempted)
+	}
+	// Arrivals during the slice queue ahead of the preempted task
+	e.admit()
+	e.enqueue(t, e.clock.now())
+}
+
+// advance runs t for d units from now, spawning children and raising signals
+// it reaches on the way.
+func (e *engine) advance(t *task, d int64) {
+	e.record(t.ProcessID, e.clock.now(), e.clock.now()+d)
+	e.spawn(t, d)
+	for _, s := range t.Signals {
+		if t.ran < s.Offset && s.Offset <= t.ran+d {
+			e.signal(s.PID, e.clock.now()+s.Offset-t.ran)
+		}
+	}
+	e.clock.advance(e.clock.now() + d)
+	t.remaining -= d
+	t.ran += d
+	t.vtime += d
+	t.sinceReady += d
+	t.cpuBurst += d
+}
+
+// endBurst ends t's current CPU burst as t gives up the CPU of its own accord.
+func (t *task) endBurst() {
+	t.lastBurst, t.bursts, t.cpuBurst = t.cpuBurst, t.bursts+1, 0
+}
+
+// nextInterrupt returns the first time in [now, limit) at which an interrupt
+// steals the CPU, if any does.
+func (e *engine) nextInterrupt(limit int64) (int64, bool) {
+	irq := e.opts.interrupts
+	if irq == nil {
+		return 0, false
+	}
+	for at := e.clock.now(); at < limit; at++ {
+		if irq.steals(at) {
+			return at, true
+		}
+	}
+
+	return 0, false
+}
+
+// blockPoint returns the first offset in (from, to] where the task may block
+// because it waits for its children or a signal, or to if there is none.
+func (t *task) blockPoint(from, to int64) int64 {
+	if w := t.WaitOffset; from < w && w < to {
+		to = w
+	}
+	for _, a := range t.Awaits {
+		if from < a && a < to {
+			to = a
+		}
+	}
+
+	return to
+}
+
+// block moves t to the blocked list if it has reached a point where it must
+// wait for its children or a signal, and reports whether it did.
+func (e *engine) block(t *task) bool {
+	reason := notBlocked
+	if t.ran == t.WaitOffset && t.live > 0 {
+		reason = waitingChildren
+	} else {
+		for _, a := range t.Awaits {
+			if t.ran != a {
+				continue
+			}
+			if t.received > 0 {
+				t.received--
+			} else {
+				reason = awaitingSignal
+			}
+		}
+	}
+	if reason == notBlocked {
+		return false
+	}
+
+	t.blocked = reason
+	t.blockedSince = e.clock.now()
+	e.blocked = append(e.blocked, t)
+	waitsFor := "children"
+	if reason == awaitingSignal {
+		waitsFor = "signal"
+	}
+	e.log(engineEvent{Clock: e.clock.now(), Type: "block", PID: t.ProcessID, Details: eventDetails{Reason: waitsFor}})
+
+	return true
+}
+
+// wake returns a blocked task to the ready queue at the given time, or
+// finishes it if its burst was already done.
+func (e *engine) wake(t *task, at int64) {
+	e.log(engineEvent{Clock: at, Type: "wake", PID: t.ProcessID})
+	t.blocked = notBlocked
+	t.blockedFor += at - t.blockedSince
+	for i := range e.blocked {
+		if e.blocked[i] == t {
+			e.blocked = append(e.blocked[:i], e.blocked[i+1:]...)
+			break
+		}
+	}
+	if t.remaining == 0 {
+		e.finish(t, at, false)
+		return
+	}
+	e.join(t, at)
+}
+
+// join puts t on the ready queue after it arrives or wakes at the given time.
+func (e *engine) join(t *task, at int64) {
+	t.vtime = max(t.vtime, e.svt)
+	t.sinceReady = 0
+	e.enqueue(t, at)
+}
+
+// enqueue puts t on the ready queue, which it joined at the given time.
+func (e *engine) enqueue(t *task, at int64) {
+	e.ready.push(t)
+	e.sampleDepth(at)
+}
+
+// sampleDepth records the ready queue depth from the given time on. Tasks
+// that arrive while another runs are admitted when it stops, so times can
+// come slightly out of order; they are clamped to keep the samples sorted.
+func (e *engine) sampleDepth(at int64) {
+	depth := e.ready.len()
+	n := len(e.depth)
+	if n > 0 && at < e.depth[n-1].time {
+		at = e.depth[n-1].time
+	}
+	switch {
+	case n > 0 && e.depth[n-1].time == at:
+		e.depth[n-1].depth = depth
+		if n > 1 && e.depth[n-2].depth == depth {
+			e.depth = e.depth[:n-1]
+		}
+	case n == 0 || e.depth[n-1].depth != depth:
+		e.depth = append(e.depth, depthSample{time: at, depth: depth})
+	}
+}
+
+// signal delivers a signal to process pid at the given time, waking it if it
+// is awaiting one and otherwise keeping it for its next await.
+func (e *engine) signal(pid, at int64) {
+	t, ok := e.byID[pid]
+	if !ok || t.finished {
+		return
+	}
+	e.log(engineEvent{Clock: at, Type: "signal", PID: pid})
+	if t.blocked == awaitingSignal {
+		e.wake(t, at)
+		return
+	}
+	t.received++
+}
+
+// spawn creates the children t reaches during a slice of length run starting
+// now. Each arrives at the moment its parent reaches the spawn offset.
+func (e *engine) spawn(t *task, run int64) {
+	pending := e.children[t.ProcessID][:0]
+	for _, c := range e.children[t.ProcessID] {
+		if c.Spawn.Offset > t.ran+run {
+			pending = append(pending, c)
+			continue
+		}
+		c.ArrivalTime = e.clock.now() + c.Spawn.Offset - t.ran
+		if c.ArrivalTime < e.clock.now() {
+			c.ArrivalTime = e.clock.now()
+		}
+		c.readyAt = e.opening(c.Window, c.ArrivalTime)
+		c.parent = t
+		t.live++
+		e.log(engineEvent{Clock: c.ArrivalTime, Type: "spawn", PID: c.ProcessID, Details: eventDetails{Parent: t.ProcessID}})
+		e.scheduleDrop(c)
+		// Keep the tasks yet to arrive ordered by arrival
+		i := e.next + sort.Search(len(e.tasks)-e.next, func(i int) bool {
+			return e.tasks[e.next+i].readyAt > c.readyAt
+		})
+		e.tasks = append(e.tasks, nil)
+		copy(e.tasks[i+1:], e.tasks[i:])
+		e.tasks[i] = c
+	}
+	e.children[t.ProcessID] = pending
+}
+
+// opening returns the first time at or after at that falls within the
+// time-of-day window w. Windows may wrap past midnight (From > To) and are
+// ignored unless a day length is set.
+func (e *engine) opening(w *Span, at int64) int64 {
+	day := e.opts.day
+	if w == nil || day <= 0 {
+		return at
+	}
+	tod := at % day
+	inside := tod >= w.From && tod < w.To
+	if w.From > w.To {
+		inside = tod >= w.From || tod < w.To
+	}
+	switch {
+	case inside:
+		return at
+	case tod < w.From:
+		return at - tod + w.From
+	default:
+		return at - tod + day + w.From
+	}
+}
+
+// record adds a slice to the Gantt chart, extending the previous slice when
+// the same process simply keeps the CPU.
+func (e *engine) record(pid, start, stop int64) {
+	if start == stop || e.stopped {
+		return
+	}
+	if e.emit != nil {
+		if e.held && e.last.PID == pid && e.last.Stop == start {
+			e.last.Stop = stop
+			return
+		}
+		if e.held && !e.emit(e.last) {
+			e.stopped = true
+			return
+		}
+		e.last, e.held = TimeSlice{PID: pid, Start: start, Stop: stop}, true
+		return
+	}
+	if n := len(e.gantt); n > 0 && e.gantt[n-1].PID == pid && e.gantt[n-1].Stop == start {
+		e.gantt[n-1].Stop = stop
+		return
+	}
+	e.gantt = append(e.gantt, TimeSlice{PID: pid, Start: start, Stop: stop})
+	e.reasons = append(e.reasons, "")
+}
+
+// ended records why the Gantt slice t has just run ended, if it ran at all.
+// When t runs on after being dispatched again at once, the slices merge and
+// the later reason stands.
+func (e *engine) ended(t *task, reason string) {
+	e.log(engineEvent{Clock: e.clock.now(), Type: "stop", PID: t.ProcessID, Details: eventDetails{Reason: reason, Remaining: t.remaining}})
+	n := len(e.gantt)
+	if n > 0 && e.gantt[n-1].PID == t.ProcessID && e.gantt[n-1].Stop == e.clock.now() {
+		e.reasons[n-1] = reason
+	}
+}
+
+func (e *engine) finish(t *task, at int64, killed bool) {
+	t.completion = at
+	t.finished = true
+	t.killed = killed
+	if d, ok := e.drops[t.ProcessID]; ok && killed && d == at {
+		t.dropped = true
+	}
+	e.done++
+	switch {
+	case t.dropped:
+		e.log(engineEvent{Clock: at, Type: "dropped", PID: t.ProcessID})
+	case killed:
+		e.log(engineEvent{Clock: at, Type: "killed", PID: t.ProcessID})
+	default:
+		e.log(engineEvent{Clock: at, Type: "exit", PID: t.ProcessID})
+	}
+	if e.opts.onExit != nil {
+		e.opts.onExit(t)
+	}
+
+	// The last child to finish wakes a parent waiting on its children,
+	// unless the parent is stuck or done
+	if p := t.parent; p != nil {
+		p.live--
+		if p.blocked == waitingChildren && p.live == 0 && !p.stuck && !p.finished {
+			e.wake(p, at)
+		}
+	}
+}
+
+// scheduleColumns is the number of columns of a schedule table row.
+const scheduleColumns = 7
+
+// result builds the schedule table, one row per process in arrival order.
+// Killed processes show how much of their burst ran and when they were killed,
+// and stuck ones how much ran before they blocked for good; children of a
+// parent killed before spawning them never existed.
+func (e *engine) result() Result {
+	var (
+		outcomes    = make([]processOutcome, len(e.tasks))
+		schedule    = make([][]string, len(e.tasks))
+		cells       = make([]string, scheduleColumns*len(e.tasks))
+		processes   = make([]Process, len(e.tasks))
+		dropped     map[int64]bool
+		completions []int64
+	)
+	for i, t := range e.tasks {
+		processes[i] = t.Process
+		if t.dropped {
+			if dropped == nil {
+				dropped = make(map[int64]bool)
+			}
+			dropped[t.ProcessID] = true
+		}
+		turnaround := t.completion - t.ArrivalTime
+		waitingTime := turnaround - t.ran - t.blockedFor
+		outcomes[i] = processOutcome{wait: waitingTime, turnaround: turnaround, completion: t.completion}
+
+		burst, exit := strconv.FormatInt(t.BurstDuration, 10), strconv.FormatInt(t.completion, 10)
+		switch {
+		case t.dropped:
+			burst = fmt.Sprintf("%d of %d", t.ran, t.BurstDuration)
+			exit = fmt.Sprintf("%d dropped", t.completion)
+		case t.killed:
+			burst = fmt.Sprintf("%d of %d", t.ran, t.BurstDuration)
+			exit = fmt.Sprintf("%d killed", t.completion)
+		case t.stuck:
+			burst = fmt.Sprintf("%d of %d", t.ran, t.BurstDuration)
+			exit = "blocked"
+		default:
+			outcomes[i].completed = true
+		}
+		if t.Deadline > 0 && completions == nil {
+			completions = make([]int64, len(e.tasks))
+		}
+		if completions != nil {
+			completions[i] = t.completion
+			if t.dropped || t.killed || t.stuck {
+				completions[i] = -1
+			}
+		}
+
+		// Rows share one slab of cells, capped so appending to a row copies it
+		row := cells[i*scheduleColumns : (i+1)*scheduleColumns : (i+1)*scheduleColumns]
+		row[0] = strconv.FormatInt(t.ProcessID, 10)
+		row[1] = strconv.FormatInt(t.Priority, 10)
+		row[2] = burst
+		row[3] = strconv.FormatInt(t.ArrivalTime, 10)
+		row[4] = strconv.FormatInt(waitingTime, 10)
+		row[5] = strconv.FormatInt(turnaround, 10)
+		row[6] = exit
+		schedule[i] = row
+	}
+
+	r := Result{
+		processes:   processes,
+		outcomes:    outcomes,
+		Schedule:    schedule,
+		Gantt:       e.gantt,
+		Yields:      e.yields,
+		Dispatches:  e.dispatches,
+		Stolen:      e.stolen,
+		err:         e.err,
+		depth:       e.depth,
+		reasons:     e.reasons,
+		dropped:     dropped,
+		completions: completions,
+	}
+	r.summarize()
+
+	return r
+}
+
+// totalWait returns the wait of every process in r added up.
+func (r *Result) totalWait() int64 {
+	var wait int64
+	for _, o := range r.outcomes {
+		wait += o.wait
+	}
+
+	return wait
+}
+
+// summarize computes the footer statistics of r from its outcomes, the same
+// way for every algorithm: the average wait and turnaround over every
+// process, finished or not, and the throughput as the processes that
+// completed per unit of time until the last one left. A result without
+// processes, or where none left after time 0, has zero statistics.
+func (r *Result) summarize() {
+	var wait, turnaround, completed, last int64
+	for _, o := range r.outcomes {
+		wait += o.wait
+		turnaround += o.turnaround
+		if o.completed {
+			completed++
+		}
+		last = max(last, o.completion)
+	}
+	r.AveWait, r.AveTurnaround, r.AveThroughput = 0, 0, 0
+	if n := len(r.outcomes); n > 0 {
+		r.AveWait = float64(wait) / float64(n)
+		r.AveTurnaround = float64(turnaround) / float64(n)
+	}
+	if last > 0 {
+		r.AveThroughput = float64(completed) / float64(last)
+	}
+}
+
+//endregion
+
+//region Policies
+
+type (
+	// firstCome dispatches the task that became ready first, to completion.
+	firstCome struct{}
+	// shortestJob dispatches the ready task with the shortest burst, to completion.
+	shortestJob struct{}
+	// highestPriority dispatches the ready task with the lowest rank, the
+	// highest priority, to completion.
+	highestPriority struct{}
+	// roundRobin dispatches ready tasks in turn for at most slice units each.
+	roundRobin struct {
+		slice int64
+	}
+)
+
+func (firstCome) pick([]*task) int    { return 0 }
+func (firstCome) quantum(*task) int64 { return 0 }
+
+func (shortestJob) pick(ready []*task) int {
+	best := 0
+	for i := range ready {
+		if ready[i].BurstDuration < ready[best].BurstDuration {
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (shortestJob) quantum(*task) int64 { return 0 }
+
+func (highestPriority) pick(ready []*task) int {
+	best := 0
+	for i := range ready {
+		if ready[i].rank < ready[best].rank {
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (highestPriority) quantum(*task) int64 { return 0 }
+
+func (roundRobin) pick([]*task) int      { return 0 }
+func (p roundRobin) quantum(*task) int64 { return p.slice }
+
+//endregion
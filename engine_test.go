@@ -0,0 +1,484 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_simulateKill(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	tests := []struct {
+		name      string
+		policy    policy
+		events    []Event
+		wantGantt []TimeSlice
+		wantRows  [][]string
+	}{
+		{
+			name:   "kill running process",
+			policy: firstCome{},
+			events: []Event{{Time: 8, Kind: eventKill, PID: 2}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 8},
+				{PID: 3, Start: 8, Stop: 14},
+			},
+			wantRows: [][]string{
+				{"1", "2", "5", "0", "0", "5", "5"},
+				{"2", "1", "3 of 9", "3", "2", "5", "8 killed"},
+				{"3", "3", "6", "6", "2", "8", "14"},
+			},
+		},
+		{
+			name:   "kill waiting process",
+			policy: roundRobin{slice: 5},
+			events: []Event{{Time: 7, Kind: eventKill, PID: 3}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 14},
+			},
+			wantRows: [][]string{
+				{"1", "2", "5", "0", "0", "5", "5"},
+				{"2", "1", "9", "3", "2", "11", "14"},
+				{"3", "3", "0 of 6", "6", "1", "1", "7 killed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := simulate(processes, tt.policy, options{events: tt.events})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("simulate() gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(got.Schedule, tt.wantRows) {
+				t.Errorf("simulate() schedule = %v, want %v", got.Schedule, tt.wantRows)
+			}
+		})
+	}
+}
+
+func Test_simulateSpawn(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 2, BurstDuration: 4, Spawn: &Spawn{Parent: 1, Offset: 3}},
+		{ProcessID: 3, BurstDuration: 2, Spawn: &Spawn{Parent: 2, Offset: 0}},
+	}
+	got := simulate(processes, roundRobin{slice: 5}, options{})
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 5},
+		{PID: 2, Start: 5, Stop: 9},
+		{PID: 1, Start: 9, Stop: 14},
+		{PID: 3, Start: 14, Stop: 16},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("simulate() gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	var arrivals []int64
+	for p := range got.Processes() {
+		arrivals = append(arrivals, p.ArrivalTime)
+	}
+	if want := []int64{0, 3, 5}; !reflect.DeepEqual(arrivals, want) {
+		t.Errorf("simulate() arrivals = %v, want %v", arrivals, want)
+	}
+}
+
+func Test_simulateWaitChildren(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, WaitOffset: 4},
+		{ProcessID: 2, BurstDuration: 6, Priority: 1, Spawn: &Spawn{Parent: 1, Offset: 2}},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 3},
+	}
+	got := simulate(processes, firstCome{}, options{})
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 4},
+		{PID: 3, Start: 4, Stop: 7},
+		{PID: 2, Start: 7, Stop: 13},
+		{PID: 1, Start: 13, Stop: 19},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("simulate() gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	// The parent's 9 units blocked on its child are not counted as waiting
+	if want := []string{"1", "0", "10", "0", "0", "19", "19"}; !reflect.DeepEqual(got.Schedule[0], want) {
+		t.Errorf("simulate() parent row = %v, want %v", got.Schedule[0], want)
+	}
+	if want := map[int64]int64{1: 19}; !reflect.DeepEqual(makespans(got), want) {
+		t.Errorf("makespans() = %v, want %v", makespans(got), want)
+	}
+}
+
+func Test_simulateStuckFamily(t *testing.T) {
+	t.Parallel()
+	// P2 awaits a signal that never comes, so P1, waiting on its child,
+	// is stuck too. Finishing P2 at the end must not wake P1
+	processes, err := loadProcesses(strings.NewReader("1,6,0,0,wait=6\n2,3,0,0,parent=1,spawn=0,await=1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, alg := range algorithms {
+		var events []engineEvent
+		r := alg.run(processes, options{
+			debugInvariants: true,
+			onEvent:         func(ev engineEvent) { events = append(events, ev) },
+		})
+		if !errors.Is(r.err, ErrUnschedulable) {
+			t.Errorf("%s: error = %v, want %v", alg.key, r.err, ErrUnschedulable)
+		}
+		ended := make(map[int64]int)
+		for _, ev := range events {
+			switch {
+			case ev.Type == "exit":
+				ended[ev.PID]++
+			case ended[ev.PID] > 0:
+				t.Errorf("%s: %s of P%d after it exited", alg.key, ev.Type, ev.PID)
+			}
+		}
+		if want := map[int64]int{1: 1, 2: 1}; !reflect.DeepEqual(ended, want) {
+			t.Errorf("%s: exits = %v, want %v", alg.key, ended, want)
+		}
+	}
+}
+
+func Test_simulateSignals(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		events    []Event
+		wantGantt []TimeSlice
+		wantExit  []string
+	}{
+		{
+			name: "signalled by another process",
+			processes: []Process{
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 8, Awaits: []int64{2, 5}},
+				{ProcessID: 1, ArrivalTime: 1, BurstDuration: 6, Signals: []Signal{{PID: 2, Offset: 3}, {PID: 2, Offset: 6}}},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 4},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 2},
+				{PID: 1, Start: 2, Stop: 8},
+				{PID: 3, Start: 8, Stop: 12},
+				{PID: 2, Start: 12, Stop: 18},
+			},
+			wantExit: []string{"18", "8", "12"},
+		},
+		{
+			name: "signalled by an event",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Awaits: []int64{2}},
+			},
+			events: []Event{{Time: 10, Kind: eventSignal, PID: 1}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 1, Start: 10, Stop: 12},
+			},
+			wantExit: []string{"12"},
+		},
+		{
+			name: "never signalled",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Awaits: []int64{2}},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+			},
+			wantExit: []string{"blocked"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := simulate(tt.processes, firstCome{}, options{events: tt.events})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("simulate() gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			var exits []string
+			for _, row := range got.Schedule {
+				exits = append(exits, row[6])
+			}
+			if !reflect.DeepEqual(exits, tt.wantExit) {
+				t.Errorf("simulate() exits = %v, want %v", exits, tt.wantExit)
+			}
+		})
+	}
+}
+
+func Test_simulateWindow(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 10, BurstDuration: 5, Window: &Span{From: 80, To: 90}},
+		{ProcessID: 2, ArrivalTime: 20, BurstDuration: 5},
+		{ProcessID: 3, ArrivalTime: 95, BurstDuration: 5, Window: &Span{From: 90, To: 10}},
+	}
+	got := simulate(processes, firstCome{}, options{day: 100})
+	want := []TimeSlice{
+		{PID: 2, Start: 20, Stop: 25},
+		{PID: 1, Start: 80, Stop: 85},
+		{PID: 3, Start: 95, Stop: 100},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("simulate() gantt = %v, want %v", got.Gantt, want)
+	}
+	// Turnaround counts from submission, including the time held back
+	if row := got.Schedule[1]; row[0] != "1" || row[5] != "75" {
+		t.Errorf("simulate() row = %v, want process 1 with turnaround 75", row)
+	}
+}
+
+// steppedClock hands each time the engine advances to over ticks and waits
+// for the test to let it continue.
+type steppedClock struct {
+	t     int64
+	ticks chan int64
+	next  chan struct{}
+}
+
+func (c *steppedClock) now() int64 { return c.t }
+
+func (c *steppedClock) advance(t int64) {
+	c.t = t
+	c.ticks <- t
+	<-c.next
+}
+
+func Test_simulateClock(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 2, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 10, BurstDuration: 4},
+	}
+	c := &steppedClock{ticks: make(chan int64), next: make(chan struct{})}
+	results := make(chan Result, 1)
+	go func() {
+		results <- fcfs(processes, options{clock: func() clock { return c }})
+		close(c.ticks)
+	}()
+
+	var ticks []int64
+	for tick := range c.ticks {
+		ticks = append(ticks, tick)
+		c.next <- struct{}{}
+	}
+	got := <-results
+
+	// Idle until 2, run 1 until 5, idle until 10, run 2 until 14
+	if want := []int64{2, 5, 10, 14}; !reflect.DeepEqual(ticks, want) {
+		t.Errorf("clock ticks = %v, want %v", ticks, want)
+	}
+	if want := []TimeSlice{{PID: 1, Start: 2, Stop: 5}, {PID: 2, Start: 10, Stop: 14}}; !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("fcfs() gantt = %v, want %v", got.Gantt, want)
+	}
+}
+
+func Test_bursts(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 6, Bursts: []int64{2, 4}},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1},
+	}
+	// Even run to completion, a process gives up the CPU after each burst
+	r := fcfs(processes, options{})
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 5}, {PID: 1, Start: 5, Stop: 9}}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("fcfs() gantt = %v, want %v", r.Gantt, want)
+	}
+	if want := []string{sliceBurst, sliceExit, sliceExit}; !reflect.DeepEqual(r.reasons, want) {
+		t.Errorf("fcfs() reasons = %v, want %v", r.reasons, want)
+	}
+}
+
+// Benchmark_simulate runs 100,000 processes. Allocating the tasks and the
+// schedule table cells in slabs, and the ready queue filter once, cut the
+// allocations and GC pressure (go test -bench simulate -benchtime 10x):
+//
+//	before: fcfs 187ms/op 135 MB/op 1118201 allocs/op, rr 240ms/op 168 MB/op 1425342 allocs/op
+//	after:  fcfs 131ms/op 128 MB/op  338423 allocs/op, rr 199ms/op 156 MB/op  333880 allocs/op
+func Benchmark_simulate(b *testing.B) {
+	processes := generateProcesses(rand.New(rand.NewSource(1)), 100000, 1, 1250000, 10, 5, nil)
+	for _, alg := range []struct {
+		name string
+		run  func([]Process, options) Result
+	}{{"fcfs", fcfs}, {"rr", rr}} {
+		b.Run(alg.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				alg.run(processes, options{})
+			}
+		})
+	}
+}
+
+// scriptedPolicy is a test double that makes the decisions it is given, in
+// order, so engine features can be tested apart from any real algorithm. It
+// records the ready tasks the engine offered at every pick. Once the script
+// runs out it dispatches the first ready task to completion.
+type scriptedPolicy struct {
+	script []scriptedDecision
+	step   int
+	// offered holds the IDs of the ready tasks at every pick.
+	offered [][]int64
+	// err is the first decision the engine did not allow.
+	err error
+}
+
+// scriptedDecision dispatches the ready task pid for quantum units, or to
+// completion if quantum is 0.
+type scriptedDecision struct {
+	pid, quantum int64
+}
+
+func (p *scriptedPolicy) reset() {
+	p.step, p.offered, p.err = 0, nil, nil
+}
+
+func (p *scriptedPolicy) pick(ready []*task) int {
+	ids := make([]int64, len(ready))
+	for i, t := range ready {
+		ids[i] = t.ProcessID
+	}
+	p.offered = append(p.offered, ids)
+	if p.step >= len(p.script) {
+		return 0
+	}
+	for i, id := range ids {
+		if id == p.script[p.step].pid {
+			return i
+		}
+	}
+	if p.err == nil {
+		p.err = fmt.Errorf("decision %d dispatches P%d, but only %v are ready", p.step, p.script[p.step].pid, ids)
+	}
+
+	return 0
+}
+
+func (p *scriptedPolicy) quantum(*task) int64 {
+	if p.step >= len(p.script) {
+		return 0
+	}
+	p.step++
+
+	return p.script[p.step-1].quantum
+}
+
+func Test_scriptedPolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		script    []scriptedDecision
+		wantGantt []TimeSlice
+		// wantOffered are the ready tasks the engine offered at each pick.
+		wantOffered [][]int64
+	}{
+		{
+			name: "preemption deferred to the end of a non-preemptible region",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 6, NonPreemptible: []Span{{From: 1, To: 4}}},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			// P1's quantum ends at 2, inside the region, so it runs on to 4
+			script:      []scriptedDecision{{1, 2}, {2, 0}},
+			wantGantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}, {PID: 1, Start: 6, Stop: 8}},
+			wantOffered: [][]int64{{1, 2}, {2, 1}, {1}},
+		},
+		{
+			name: "blocked until signalled",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4, Awaits: []int64{2}},
+				{ProcessID: 2, BurstDuration: 3, Signals: []Signal{{PID: 1, Offset: 1}}},
+			},
+			// P1 is not offered while it is blocked, and rejoins once P2
+			// signals it at 3
+			script:      []scriptedDecision{{1, 0}, {2, 0}},
+			wantGantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 5}, {PID: 1, Start: 5, Stop: 7}},
+			wantOffered: [][]int64{{1, 2}, {2}, {1}},
+		},
+		{
+			name: "preempted by an arrival",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+			},
+			// The arrival queues ahead of the task it preempted
+			script:      []scriptedDecision{{1, 1}, {2, 0}},
+			wantGantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 3}, {PID: 1, Start: 3, Stop: 6}},
+			wantOffered: [][]int64{{1}, {2, 1}, {1}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := &scriptedPolicy{script: tt.script}
+			got := simulate(tt.processes, p, options{})
+			if p.err != nil {
+				t.Fatal(p.err)
+			}
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("simulate() gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(p.offered, tt.wantOffered) {
+				t.Errorf("simulate() offered %v, want %v", p.offered, tt.wantOffered)
+			}
+		})
+	}
+}
+
+func TestResult_summarize(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	opts := options{events: []Event{{Time: 8, Kind: eventKill, PID: 2}}}
+	// Every algorithm's footer averages its own schedule table over every
+	// process, and counts only completed ones towards throughput
+	for _, alg := range algorithms {
+		alg := alg
+		t.Run(alg.key, func(t *testing.T) {
+			t.Parallel()
+			r := alg.run(append([]Process(nil), processes...), opts)
+			var wait, turnaround, completed, last float64
+			for _, row := range r.Schedule {
+				w, _ := strconv.ParseFloat(row[4], 64)
+				ta, _ := strconv.ParseFloat(row[5], 64)
+				exit, killed := strings.CutSuffix(row[6], " killed")
+				at, _ := strconv.ParseFloat(exit, 64)
+				wait, turnaround, last = wait+w, turnaround+ta, max(last, at)
+				if !killed {
+					completed++
+				}
+			}
+			n := float64(len(r.Schedule))
+			if r.AveWait != wait/n || r.AveTurnaround != turnaround/n || r.AveThroughput != completed/last {
+				t.Errorf("%s footer = %v, %v, %v, want %v, %v, %v", alg.key,
+					r.AveWait, r.AveTurnaround, r.AveThroughput, wait/n, turnaround/n, completed/last)
+			}
+		})
+	}
+
+	// Nothing to average is no statistics at all
+	var r Result
+	r.summarize()
+	if r.AveWait != 0 || r.AveTurnaround != 0 || r.AveThroughput != 0 {
+		t.Errorf("summarize() of no processes = %v, %v, %v, want zeros", r.AveWait, r.AveTurnaround, r.AveThroughput)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+//region Event log
+
+type (
+	// engineEvent is something that happened in a simulation: a process
+	// arrived, was dispatched, stopped running, blocked, woke, was
+	// signalled, spawned a child, was interrupted, or exited, was killed or
+	// dropped.
+	engineEvent struct {
+		// Algorithm is the key of the algorithm whose run it happened in,
+		// filled in by the event log.
+		Algorithm string       `json:"algorithm,omitempty"`
+		Clock     int64        `json:"clock"`
+		Type      string       `json:"type"`
+		PID       int64        `json:"pid"`
+		CPU       int          `json:"cpu"`
+		Details   eventDetails `json:"details"`
+	}
+	// eventDetails says more about an event; what does not apply to it is
+	// zero and omitted.
+	eventDetails struct {
+		// Reason is why a slice stopped, as in the Gantt slice reasons, or
+		// what a process blocked on: children or signal.
+		Reason string `json:"reason,omitempty"`
+		// Run is how long a process was dispatched for, and Remaining how
+		// much of its burst was left when dispatched or stopped.
+		Run       int64 `json:"run,omitempty"`
+		Remaining int64 `json:"remaining,omitempty"`
+		// Ready is the ready queue length once a process arrived, or left
+		// behind when one was dispatched.
+		Ready int `json:"ready,omitempty"`
+		// Parent is the process that spawned a child.
+		Parent int64 `json:"parent,omitempty"`
+	}
+)
+
+// log hands ev to the options' onEvent, if any. The simulator has a single
+// CPU, numbered 0.
+func (e *engine) log(ev engineEvent) {
+	e.logged++
+	if e.opts.onEvent != nil {
+		e.opts.onEvent(ev)
+	}
+	if e.invariants != nil {
+		e.invariants.check(e, ev)
+	}
+}
+
+// writeEventLog writes every engine event of every algorithm's run as one
+// JSON object per line, in the order the simulator handled them. Processes
+// that arrive while another runs are handled when it stops, so an arrival
+// may be clocked earlier than the event before it.
+func writeEventLog(w io.Writer, processes []Process, opts options) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	var err error
+	for _, alg := range algorithms {
+		opts := opts
+		opts.onEvent = func(ev engineEvent) {
+			ev.Algorithm = alg.key
+			if err == nil {
+				err = enc.Encode(ev)
+			}
+		}
+		alg.run(processes, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeEventLogFile(name string, processes []Process, opts options) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating event log", err)
+	}
+	if err := writeEventLog(f, processes, opts); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: error writing event log", err)
+	}
+
+	return f.Close()
+}
+
+//endregion
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_writeEventLog(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,2,0\n2,1,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := writeEventLog(&out, processes, options{}); err != nil {
+		t.Fatal(err)
+	}
+	var got []engineEvent
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var ev engineEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("writeEventLog() line %q: %v", line, err)
+		}
+		if ev.Algorithm == "fcfs" {
+			got = append(got, ev)
+		}
+	}
+	want := []engineEvent{
+		{Algorithm: "fcfs", Clock: 0, Type: "arrive", PID: 1, Details: eventDetails{Ready: 1}},
+		{Algorithm: "fcfs", Clock: 0, Type: "dispatch", PID: 1, Details: eventDetails{Run: 2, Remaining: 2}},
+		{Algorithm: "fcfs", Clock: 2, Type: "stop", PID: 1, Details: eventDetails{Reason: sliceExit}},
+		{Algorithm: "fcfs", Clock: 2, Type: "exit", PID: 1},
+		{Algorithm: "fcfs", Clock: 1, Type: "arrive", PID: 2, Details: eventDetails{Ready: 1}},
+		{Algorithm: "fcfs", Clock: 2, Type: "dispatch", PID: 2, Details: eventDetails{Run: 1, Remaining: 1}},
+		{Algorithm: "fcfs", Clock: 3, Type: "stop", PID: 2, Details: eventDetails{Reason: sliceExit}},
+		{Algorithm: "fcfs", Clock: 3, Type: "exit", PID: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("writeEventLog() fcfs events =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func Test_engineEventsBlocking(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,4,0,0,await=2\n2,3,0,0,signal=1@1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	fcfs(processes, options{onEvent: func(ev engineEvent) {
+		types = append(types, ev.Type)
+	}})
+	want := []string{"arrive", "arrive", "dispatch", "block", "stop", "dispatch", "signal", "wake", "stop", "exit", "dispatch", "stop", "exit"}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("fcfs() events = %v, want %v", types, want)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//region Examples
+
+// ErrExampleMismatch is returned when an example does not reproduce its
+// expected results.
+var ErrExampleMismatch = errors.New("example results differ from expected")
+
+type (
+	// exampleExpectation is the machine-readable answer key stored next to
+	// each example workload, as name.json beside name.csv.
+	exampleExpectation struct {
+		// Source cites where the example comes from.
+		Source string `json:"source"`
+		// Results are keyed by algorithm key, e.g. "fcfs".
+		Results map[string]expectedResult `json:"results"`
+	}
+	expectedResult struct {
+		AverageWait       float64     `json:"average_wait"`
+		AverageTurnaround float64     `json:"average_turnaround"`
+		Gantt             []TimeSlice `json:"gantt"`
+	}
+)
+
+// verifyExamplesCommand checks that every example in a directory (examples
+// by default) reproduces its expected results, printing one line per check.
+// With -hashes it instead checks every algorithm's results against the
+// reference hashes, and with -update-hashes rewrites them.
+func verifyExamplesCommand(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("verify-examples", flag.ContinueOnError)
+	hashes := fs.Bool("hashes", false, "compare the results of every algorithm with the reference hashes")
+	update := fs.Bool("update-hashes", false, "rewrite the reference hashes from the results on this platform")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	dir := "examples"
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: verify-examples takes at most one directory", ErrInvalidArgs)
+	}
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	if *hashes || *update {
+		return verifyHashes(w, dir, *update)
+	}
+
+	return verifyExamples(w, dir)
+}
+
+func verifyExamples(w io.Writer, dir string) error {
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%w: no examples in %s", ErrInvalidArgs, dir)
+	}
+	sort.Strings(names)
+
+	failed := 0
+	for _, name := range names {
+		base := strings.TrimSuffix(name, ".json")
+		problems, err := verifyExample(base)
+		if err != nil {
+			return fmt.Errorf("%w: example %s", err, filepath.Base(base))
+		}
+		for _, p := range problems {
+			_, _ = fmt.Fprintf(w, "FAIL %s: %s\n", filepath.Base(base), p)
+		}
+		if len(problems) > 0 {
+			failed++
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "ok   %s\n", filepath.Base(base))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d examples", ErrExampleMismatch, failed, len(names))
+	}
+
+	return nil
+}
+
+// verifyExample runs the algorithms named in base.json over base.csv and
+// describes every way the results differ from the expected ones.
+func verifyExample(base string) ([]string, error) {
+	b, err := os.ReadFile(base + ".json")
+	if err != nil {
+		return nil, err
+	}
+	var want exampleExpectation
+	if err := json.Unmarshal(b, &want); err != nil {
+		return nil, fmt.Errorf("%w: reading expected results", err)
+	}
+	f, err := os.Open(base + ".csv")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(want.Results))
+	for key := range want.Results {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		run := algorithmByKey(key)
+		if run == nil {
+			problems = append(problems, fmt.Sprintf("unknown algorithm %q", key))
+			continue
+		}
+		expected := want.Results[key]
+		got := run(processes, options{})
+		if math.Abs(got.AveWait-expected.AverageWait) > 0.005 {
+			problems = append(problems, fmt.Sprintf("%s average wait %.2f, want %.2f", key, got.AveWait, expected.AverageWait))
+		}
+		if math.Abs(got.AveTurnaround-expected.AverageTurnaround) > 0.005 {
+			problems = append(problems, fmt.Sprintf("%s average turnaround %.2f, want %.2f", key, got.AveTurnaround, expected.AverageTurnaround))
+		}
+		if expected.Gantt != nil && !reflect.DeepEqual(got.Gantt, expected.Gantt) {
+			problems = append(problems, fmt.Sprintf("%s gantt %v, want %v", key, got.Gantt, expected.Gantt))
+		}
+	}
+
+	return problems, nil
+}
+
+// algorithmByKey returns the scheduler registered under key, or nil.
+func algorithmByKey(key string) func([]Process, options) Result {
+	for _, alg := range algorithms {
+		if alg.key == key {
+			return alg.run
+		}
+	}
+
+	return nil
+}
+
+//endregion
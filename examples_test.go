@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_verifyExamples(t *testing.T) {
+	t.Parallel()
+	if err := verifyExamples(io.Discard, "examples"); err != nil {
+		t.Errorf("verifyExamples() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wrong.csv"), []byte("1,5,0\n2,5,0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "wrong.json"), []byte(`{"results": {"fcfs": {"average_wait": 1}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyExamples(io.Discard, dir); !errors.Is(err, ErrExampleMismatch) {
+		t.Errorf("verifyExamples() error = %v, want %v", err, ErrExampleMismatch)
+	}
+}
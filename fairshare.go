@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Fair share
+
+type (
+	// fairShare divides the CPU equally among the groups with ready
+	// processes first, then equally among the ready processes of each
+	// group, a quantum at a time. It runs the group that has had the least
+	// CPU time, and within it the process that has. A group or process that
+	// becomes ready again starts level with the least of those that were
+	// ready all along, so time spent away does not count as credit.
+	fairShare struct {
+		groups map[string]*fairGroup
+		tasks  map[int64]*fairTask
+		// active holds the groups ready at the last pick and last the task
+		// it dispatched, which had run lastRan then.
+		active  map[string]bool
+		last    *task
+		lastRan int64
+	}
+
+	fairGroup struct {
+		// usage is the CPU time the group had, raised when it becomes ready
+		// again, for picking among groups, and cpu the CPU time it had.
+		usage, cpu int64
+		// order is when the group was first seen, to break ties.
+		order int
+		// contended is the CPU time handed out while the group was ready
+		// together with others, ran how much of it the group got and
+		// expected its equal share of it.
+		contended, ran int64
+		expected       float64
+	}
+
+	fairTask struct {
+		usage int64
+		// blocked is how long the task had been blocked when last seen.
+		blocked int64
+	}
+)
+
+// group returns the group p belongs to, or one of its own.
+func (p Process) group() string {
+	if p.Group != "" {
+		return p.Group
+	}
+
+	return fmt.Sprintf("P%d", p.ProcessID)
+}
+
+func (p *fairShare) reset() {
+	*p = fairShare{groups: make(map[string]*fairGroup), tasks: make(map[int64]*fairTask), active: make(map[string]bool)}
+}
+
+// charge accounts for what the last task dispatched ran, to it, its group
+// and, while groups contended, to every group's share.
+func (p *fairShare) charge() {
+	t := p.last
+	if t == nil {
+		return
+	}
+	d := t.ran - p.lastRan
+	g := p.groups[t.group()]
+	g.usage += d
+	g.cpu += d
+	p.tasks[t.ProcessID].usage += d
+	if len(p.active) < 2 {
+		return
+	}
+	for name := range p.active {
+		a := p.groups[name]
+		a.contended += d
+		a.expected += float64(d) / float64(len(p.active))
+	}
+	g.ran += d
+}
+
+func (p *fairShare) pick(ready []*task) int {
+	p.charge()
+
+	// Groups that became ready start level with the least usage of those
+	// that stayed ready
+	active := make(map[string]bool)
+	for _, t := range ready {
+		active[t.group()] = true
+	}
+	lowest, stayed := int64(0), false
+	for name := range active {
+		if g, ok := p.groups[name]; ok && p.active[name] && (!stayed || g.usage < lowest) {
+			lowest, stayed = g.usage, true
+		}
+	}
+	for _, t := range ready {
+		name := t.group()
+		g, ok := p.groups[name]
+		if !ok {
+			g = &fairGroup{order: len(p.groups)}
+			p.groups[name] = g
+		}
+		if !p.active[name] && stayed {
+			g.usage = max(g.usage, lowest)
+		}
+	}
+	p.active = active
+
+	// And tasks that arrived or woke with the least usage of the other
+	// ready tasks of their group
+	var joined []*task
+	low := make(map[string]int64)
+	for _, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		if !ok || t.blockedFor > s.blocked {
+			joined = append(joined, t)
+			continue
+		}
+		if l, ok := low[t.group()]; !ok || s.usage < l {
+			low[t.group()] = s.usage
+		}
+	}
+	for _, t := range joined {
+		s, ok := p.tasks[t.ProcessID]
+		if !ok {
+			s = &fairTask{}
+			p.tasks[t.ProcessID] = s
+		}
+		s.blocked = t.blockedFor
+		if l, ok := low[t.group()]; ok {
+			s.usage = max(s.usage, l)
+		}
+	}
+
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		g, bg := p.groups[t.group()], p.groups[b.group()]
+		switch {
+		case g != bg:
+			if g.usage < bg.usage || g.usage == bg.usage && g.order < bg.order {
+				best = i
+			}
+		case p.tasks[t.ProcessID].usage < p.tasks[b.ProcessID].usage:
+			best = i
+		}
+	}
+	p.last, p.lastRan = ready[best], ready[best].ran
+
+	return best
+}
+
+func (*fairShare) quantum(*task) int64 { return shareQuantum }
+
+// Fair share among groups
+func FairShareSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, fairShareScheduling(processes, options{}), display{})
+	outputFairShare(w, processes, options{})
+}
+
+func fairShareScheduling(processes []Process, opts options) Result {
+	r, _ := fairShareGroups(processes, opts)
+
+	return r
+}
+
+// fairShareGroups runs the fair-share scheduler and returns what each group
+// got out of it as well.
+func fairShareGroups(processes []Process, opts options) (Result, *fairShare) {
+	p := &fairShare{}
+	r := simulate(processes, p, opts)
+	// Charge the last task dispatched
+	p.charge()
+
+	return r, p
+}
+
+// outputFairShare shows, for every group, how many processes it has, its CPU
+// time and, while it contended with other groups, its equal share of the
+// CPU against the share it got.
+func outputFairShare(w io.Writer, processes []Process, opts options) {
+	_, p := fairShareGroups(append([]Process(nil), processes...), opts)
+	names := make([]string, len(p.groups))
+	for name, g := range p.groups {
+		names[g.order] = name
+	}
+	counts := make(map[string]int)
+	for _, proc := range processes {
+		counts[proc.group()]++
+	}
+
+	outputTitle(w, "Fair-share groups")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Group", "Processes", "CPU Time", "Contended", "Target Share", "Achieved Share"})
+	for _, name := range names {
+		g := p.groups[name]
+		target, achieved := "-", "-"
+		if g.contended > 0 {
+			target = fmt.Sprintf("%.1f%%", 100*g.expected/float64(g.contended))
+			achieved = fmt.Sprintf("%.1f%%", 100*float64(g.ran)/float64(g.contended))
+		}
+		table.Append([]string{name, fmt.Sprint(counts[name]), fmt.Sprint(g.cpu), fmt.Sprint(g.contended), target, achieved})
+	}
+	table.Render()
+}
+
+//endregion
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_fairShareScheduling(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   string
+		want []TimeSlice
+	}{
+		{
+			name: "groups take turns before processes",
+			in:   "1,10,0,0,group=alice\n2,10,0,0,group=alice\n3,20,0,0,group=bob\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5}, {PID: 3, Start: 5, Stop: 10},
+				{PID: 2, Start: 10, Stop: 15}, {PID: 3, Start: 15, Stop: 20},
+				{PID: 1, Start: 20, Stop: 25}, {PID: 3, Start: 25, Stop: 30},
+				{PID: 2, Start: 30, Stop: 35}, {PID: 3, Start: 35, Stop: 40},
+			},
+		},
+		{
+			name: "processes without a group are groups of their own",
+			in:   "1,10,0,0\n2,10,0,0\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10},
+				{PID: 1, Start: 10, Stop: 15}, {PID: 2, Start: 15, Stop: 20},
+			},
+		},
+		{
+			name: "a late group starts level rather than catching up",
+			in:   "1,20,0,0,group=alice\n2,10,10,0,group=bob\n",
+			want: []TimeSlice{
+				// Bob joins level with alice, who wins the tie for being first
+				{PID: 1, Start: 0, Stop: 15}, {PID: 2, Start: 15, Stop: 20},
+				{PID: 1, Start: 20, Stop: 25}, {PID: 2, Start: 25, Stop: 30},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fairShareScheduling(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fairShareScheduling() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputFairShare(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,10,0,0,group=alice\n2,10,0,0,group=alice\n3,20,0,0,group=bob\n4,5,50,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outputFairShare(&out, processes, options{})
+	// Bob's one process got as much of the CPU as alice's two together,
+	// though its last quantum ran alone and does not count as contended
+	for _, want := range []string{"Fair-share groups", "| alice |         2 |       20 |        35 | 50.0%        | 57.1%          |", "| bob   |         1 |       20 |        35 | 50.0%        | 42.9%          |", "| P4    |         1 |        5 |         0 | -            | -              |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputFairShare() missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//region Workload generator
+
+// batchWindow is a daily time-of-day window in which a burst of extra
+// processes arrives, e.g. the nightly batch run.
+type batchWindow struct {
+	from, to int64
+	count    int
+	burst    float64
+}
+
+// windowFlags collects repeated -window flags.
+type windowFlags []batchWindow
+
+func (w *windowFlags) String() string { return fmt.Sprint(*w) }
+
+// Set parses from-to:count[:burst].
+func (w *windowFlags) Set(s string) error {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("%w: window %q must be from-to:count[:burst]", ErrInvalidArgs, s)
+	}
+	from, to, ok := strings.Cut(parts[0], "-")
+	if !ok {
+		return fmt.Errorf("%w: window %q must be from-to:count[:burst]", ErrInvalidArgs, s)
+	}
+	var (
+		b   batchWindow
+		err error
+	)
+	if b.from, err = strconv.ParseInt(from, 10, 64); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if b.to, err = strconv.ParseInt(to, 10, 64); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if b.count, err = strconv.Atoi(parts[1]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if len(parts) == 3 {
+		if b.burst, err = strconv.ParseFloat(parts[2], 64); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+	}
+	if b.from < 0 || b.to <= b.from || b.count < 0 || b.burst < 0 {
+		return fmt.Errorf("%w: window %q is empty or negative", ErrInvalidArgs, s)
+	}
+	*w = append(*w, b)
+
+	return nil
+}
+
+// generateCommand writes a random workload CSV to w. Processes arrive
+// steadily through every simulated day, plus a batch of extra arrivals in each
+// time-of-day window, so diurnal patterns can be studied.
+func generateCommand(w io.Writer, args []string) error {
+	var windows windowFlags
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	n := fs.Int("n", 10, "processes arriving steadily through each day")
+	days := fs.Int("days", 1, "number of simulated days")
+	day := fs.Int64("day", 2400, "length of a simulated day")
+	burst := fs.Float64("burst", 10, "mean burst of the steady processes")
+	priorities := fs.Int64("priorities", 5, "priorities are drawn from 1 to this")
+	seed := fs.Int64("seed", 1, "random seed")
+	fs.Var(&windows, "window", "daily batch window from-to:count[:burst], may be repeated")
+	manifestFile := fs.String("manifest", "", "also write a JSON manifest of the version, command line, seed and options to this file")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	switch {
+	case *n < 0:
+		return fmt.Errorf("%w: -n %d is negative", ErrInvalidArgs, *n)
+	case *days < 0:
+		return fmt.Errorf("%w: -days %d is negative", ErrInvalidArgs, *days)
+	case *day < 1:
+		return fmt.Errorf("%w: -day %d must be at least 1", ErrInvalidArgs, *day)
+	case !(*burst > 0):
+		return fmt.Errorf("%w: -burst %g must be positive", ErrInvalidArgs, *burst)
+	case *priorities < 1:
+		return fmt.Errorf("%w: -priorities %d must be at least 1", ErrInvalidArgs, *priorities)
+	}
+	for _, b := range windows {
+		if b.to > *day {
+			return fmt.Errorf("%w: window %d-%d does not fit in a day of %d", ErrInvalidArgs, b.from, b.to, *day)
+		}
+	}
+
+	if *manifestFile != "" {
+		m, err := newManifest(fs, append([]string{"generate"}, args...))
+		if err != nil {
+			return err
+		}
+		m.Seed = seed
+		if err := writeManifestFile(*manifestFile, m); err != nil {
+			return err
+		}
+	}
+
+	processes := generateProcesses(rand.New(rand.NewSource(*seed)), *n, *days, *day, *burst, *priorities, windows)
+
+	cw := csv.NewWriter(w)
+	for _, p := range processes {
+		err := cw.Write([]string{
+			fmt.Sprint(p.ProcessID),
+			fmt.Sprint(p.BurstDuration),
+			fmt.Sprint(p.ArrivalTime),
+			fmt.Sprint(p.Priority),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func generateProcesses(r *rand.Rand, n, days int, day int64, burst float64, priorities int64, windows []batchWindow) []Process {
+	draw := func(mean float64) int64 {
+		return int64(r.ExpFloat64()*mean) + 1
+	}
+
+	var processes []Process
+	for d := 0; d < days; d++ {
+		start := int64(d) * day
+		for i := 0; i < n; i++ {
+			processes = append(processes, Process{
+				ArrivalTime:   start + r.Int63n(day),
+				BurstDuration: draw(burst),
+				Priority:      r.Int63n(priorities) + 1,
+			})
+		}
+		for _, b := range windows {
+			mean := b.burst
+			if mean == 0 {
+				mean = burst
+			}
+			for i := 0; i < b.count; i++ {
+				processes = append(processes, Process{
+					ArrivalTime:   start + b.from + r.Int63n(b.to-b.from),
+					BurstDuration: draw(mean),
+					Priority:      r.Int63n(priorities) + 1,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(processes, func(i, j int) bool {
+		return processes[i].ArrivalTime < processes[j].ArrivalTime
+	})
+	for i := range processes {
+		processes[i].ProcessID = int64(i + 1)
+	}
+
+	return processes
+}
+
+//endregion
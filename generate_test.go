@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_generateProcesses(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := generateCommand(&w, []string{"-n", "5", "-days", "2", "-day", "100", "-window", "80-90:20:30"}); err != nil {
+		t.Fatal(err)
+	}
+	processes, err := loadProcesses(&w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 50 {
+		t.Fatalf("generated %d processes, want %d", len(processes), 50)
+	}
+
+	inWindow := 0
+	for i, p := range processes {
+		if p.ProcessID != int64(i+1) {
+			t.Errorf("process %d has ID %d", i, p.ProcessID)
+		}
+		if i > 0 && p.ArrivalTime < processes[i-1].ArrivalTime {
+			t.Errorf("process %d arrives before process %d", p.ProcessID, processes[i-1].ProcessID)
+		}
+		if p.BurstDuration < 1 || p.Priority < 1 || p.Priority > 5 {
+			t.Errorf("process %v is out of range", p)
+		}
+		if tod := p.ArrivalTime % 100; tod >= 80 && tod < 90 {
+			inWindow++
+		}
+	}
+	if inWindow < 40 {
+		t.Errorf("%d processes arrived in the batch window, want at least %d", inWindow, 40)
+	}
+
+	var again bytes.Buffer
+	_ = generateCommand(&again, []string{"-n", "5", "-days", "2", "-day", "100", "-window", "80-90:20:30"})
+	var first bytes.Buffer
+	_ = generateCommand(&first, []string{"-n", "5", "-days", "2", "-day", "100", "-window", "80-90:20:30"})
+	if again.String() != first.String() {
+		t.Error("the same seed generated different workloads")
+	}
+}
+
+func Test_generateCommandInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"negative n", []string{"-n", "-1"}},
+		{"negative days", []string{"-days", "-1"}},
+		{"empty day", []string{"-day", "0"}},
+		{"zero burst", []string{"-burst", "0"}},
+		{"NaN burst", []string{"-burst", "NaN"}},
+		{"no priorities", []string{"-priorities", "0"}},
+		{"empty window", []string{"-window", "10-10:5"}},
+		{"backwards window", []string{"-window", "20-10:5"}},
+		{"negative window burst", []string{"-window", "10-20:5:-3"}},
+		{"window past the day", []string{"-day", "100", "-window", "90-110:5"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := generateCommand(&w, tt.args); !errors.Is(err, ErrInvalidArgs) {
+				t.Errorf("generateCommand() error = %v, want %v", err, ErrInvalidArgs)
+			}
+		})
+	}
+}
@@ -1,15 +1,15 @@
 module github.com/SamFisher0208/CSCE4600
 
-go 1.19
+go 1.23
 
 require (
+	github.com/mattn/go-runewidth v0.0.14
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/stretchr/testify v1.8.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.4.3 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
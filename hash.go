@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+//region Result hashing
+
+// resultsHashPrefix starts the line appended to results by -hash.
+const resultsHashPrefix = "Results hash: sha256:"
+
+var (
+	// ErrNoResultsHash is returned when results have no hash line to verify.
+	ErrNoResultsHash = errors.New("no results hash")
+	// ErrResultsHashMismatch is returned when results were changed after
+	// they were hashed.
+	ErrResultsHashMismatch = errors.New("results do not match their hash")
+)
+
+// resultsHash hashes results in canonical form: line endings are normalised
+// and trailing whitespace is dropped, so results survive being opened and
+// saved in an editor, or copied between platforms, but not being edited.
+// The hash is unkeyed: it catches accidental edits, not deliberate ones,
+// whose author can simply hash the results again.
+func resultsHash(results []byte) string {
+	h := sha256.New()
+	for _, line := range bytes.Split(bytes.ReplaceAll(results, []byte("\r\n"), []byte("\n")), []byte("\n")) {
+		line = bytes.TrimRight(line, " \t\r")
+		if len(line) == 0 {
+			continue
+		}
+		_, _ = h.Write(line)
+		_, _ = h.Write([]byte("\n"))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeResultsHash appends the hash line for results to w.
+func writeResultsHash(w io.Writer, results []byte) {
+	_, _ = fmt.Fprintf(w, "%s%s\n", resultsHashPrefix, resultsHash(results))
+}
+
+// verifyCommand checks that a results file still matches the hash appended
+// to it by -hash.
+func verifyCommand(w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: verify takes one results file", ErrInvalidArgs)
+	}
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: error reading results", err)
+	}
+	if err := verifyResults(b); err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+	_, _ = fmt.Fprintf(w, "ok   %s\n", args[0])
+
+	return nil
+}
+
+// verifyResults checks the last hash line in b against everything before it.
+func verifyResults(b []byte) error {
+	i := bytes.LastIndex(b, []byte(resultsHashPrefix))
+	if i < 0 || (i > 0 && b[i-1] != '\n') {
+		return ErrNoResultsHash
+	}
+	want := string(bytes.TrimSpace(b[i+len(resultsHashPrefix):]))
+	if resultsHash(b[:i]) != want {
+		return ErrResultsHashMismatch
+	}
+
+	return nil
+}
+
+//endregion
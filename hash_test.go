@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_verifyResults(t *testing.T) {
+	t.Parallel()
+	var results bytes.Buffer
+	FCFSSchedule(&results, "First-come, first-serve", []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Priority: 1},
+	})
+	hashed := append([]byte(nil), results.Bytes()...)
+	var line bytes.Buffer
+	writeResultsHash(&line, results.Bytes())
+	hashed = append(hashed, line.Bytes()...)
+
+	tests := []struct {
+		name    string
+		results []byte
+		wantErr error
+	}{
+		{"unchanged", hashed, nil},
+		{"windows line endings", bytes.ReplaceAll(hashed, []byte("\n"), []byte("\r\n")), nil},
+		{"trailing whitespace", bytes.ReplaceAll(hashed, []byte("\n"), []byte("  \n")), nil},
+		{"edited", bytes.Replace(hashed, []byte("|  1 |"), []byte("|  2 |"), 1), ErrResultsHashMismatch},
+		{"no hash", results.Bytes(), ErrNoResultsHash},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := verifyResults(tt.results); !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifyResults() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
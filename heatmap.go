@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"os"
+)
+
+//region Run-queue heatmap
+
+const (
+	// heatmapBuckets is the most columns a heatmap row is divided into.
+	heatmapBuckets = 200
+	heatmapLabel   = 180
+	heatmapWidth   = 800
+	heatmapRow     = 28
+)
+
+// meanDepths divides [0, end) into buckets and returns the time-weighted mean
+// ready queue depth in each, from depth samples sorted by time.
+func meanDepths(samples []depthSample, end int64, buckets int) []float64 {
+	means := make([]float64, buckets)
+	i := 0
+	for b := range means {
+		from, to := int64(b)*end/int64(buckets), int64(b+1)*end/int64(buckets)
+		if to <= from {
+			continue
+		}
+		area := 0.0
+		for t := from; t < to; {
+			for i+1 < len(samples) && samples[i+1].time <= t {
+				i++
+			}
+			depth, stop := 0, to
+			switch {
+			case i < len(samples) && samples[i].time > t:
+				// Nothing has been queued yet
+				stop = min(stop, samples[i].time)
+			case i < len(samples):
+				depth = samples[i].depth
+				if i+1 < len(samples) {
+					stop = min(stop, samples[i+1].time)
+				}
+			}
+			area += float64(depth) * float64(stop-t)
+			t = stop
+		}
+		means[b] = area / float64(to-from)
+	}
+
+	return means
+}
+
+// writeHeatmap draws the ready queue depth over time of every algorithm as
+// an SVG heatmap, one row per algorithm on a shared time axis. Darker cells
+// are periods with more processes waiting, such as a convoy behind a long
+// job. Hovering over a cell shows its time range and mean depth.
+func writeHeatmap(w io.Writer, processes []Process, opts options) error {
+	results := make([]Result, len(algorithms))
+	end := int64(0)
+	for i, alg := range algorithms {
+		results[i] = alg.run(processes, opts)
+		if n := len(results[i].Gantt); n > 0 && results[i].Gantt[n-1].Stop > end {
+			end = results[i].Gantt[n-1].Stop
+		}
+	}
+	buckets := heatmapBuckets
+	if end < int64(buckets) {
+		buckets = int(end)
+	}
+	if buckets < 1 {
+		buckets = 1
+	}
+	means := make([][]float64, len(results))
+	peak := 0.0
+	for i := range results {
+		means[i] = meanDepths(results[i].depth, end, buckets)
+		for _, m := range means[i] {
+			if m > peak {
+				peak = m
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	height := heatmapRow*len(algorithms) + 50
+	cell := float64(heatmapWidth) / float64(buckets)
+	_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n",
+		heatmapLabel+heatmapWidth+10, height)
+	_, _ = fmt.Fprintln(bw, `<title>Ready queue depth over time</title>`)
+	for i, alg := range algorithms {
+		y := 10 + i*heatmapRow
+		_, _ = fmt.Fprintf(bw, `<text x="0" y="%d">%s</text>`+"\n", y+heatmapRow/2+4, html.EscapeString(alg.name))
+		for b, m := range means[i] {
+			shade := 0.0
+			if peak > 0 {
+				shade = m / peak
+			}
+			from, to := int64(b)*end/int64(buckets), int64(b+1)*end/int64(buckets)
+			_, _ = fmt.Fprintf(bw, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="rgb(255,%d,%d)"><title>%d-%d: mean depth %.2f</title></rect>`+"\n",
+				float64(heatmapLabel)+float64(b)*cell, y, cell, heatmapRow-2,
+				255-int(215*shade), 255-int(235*shade), from, to, m)
+		}
+	}
+	axis := 10 + len(algorithms)*heatmapRow + 14
+	_, _ = fmt.Fprintf(bw, `<text x="%d" y="%d">0</text>`+"\n", heatmapLabel, axis)
+	_, _ = fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="end">%d</text>`+"\n", heatmapLabel+heatmapWidth, axis, end)
+	_, _ = fmt.Fprintf(bw, `<text x="0" y="%d">darkest = %.2f waiting</text>`+"\n", axis+18, peak)
+	_, _ = fmt.Fprintln(bw, `</svg>`)
+
+	return bw.Flush()
+}
+
+func writeHeatmapFile(name string, processes []Process, opts options) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating heatmap file", err)
+	}
+	if err := writeHeatmap(f, processes, opts); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: error writing heatmap file", err)
+	}
+
+	return f.Close()
+}
+
+//endregion
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_queueDepth(t *testing.T) {
+	t.Parallel()
+	// A convoy builds up behind the long first job
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2},
+	}
+	got := fcfs(processes, options{})
+	want := []depthSample{{0, 0}, {1, 1}, {2, 2}, {10, 1}, {12, 0}}
+	if !reflect.DeepEqual(got.depth, want) {
+		t.Errorf("fcfs() depth = %v, want %v", got.depth, want)
+	}
+
+	means := meanDepths(got.depth, 14, 14)
+	if want := []float64{0, 1, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 0, 0}; !reflect.DeepEqual(means, want) {
+		t.Errorf("meanDepths() = %v, want %v", means, want)
+	}
+	if means := meanDepths(got.depth, 14, 7); means[0] != 0.5 || means[5] != 1 {
+		t.Errorf("meanDepths() in wider buckets = %v", means)
+	}
+}
+
+func Test_writeHeatmap(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	var b bytes.Buffer
+	if err := writeHeatmap(&b, processes, options{}); err != nil {
+		t.Fatalf("writeHeatmap() error = %v", err)
+	}
+	svg := b.String()
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("writeHeatmap() is not an SVG document:\n%s", svg)
+	}
+	for _, alg := range algorithms {
+		if !strings.Contains(svg, ">"+alg.name+"<") {
+			t.Errorf("writeHeatmap() has no row for %s", alg.name)
+		}
+	}
+	if want := 12 * len(algorithms); strings.Count(svg, "<rect") != want {
+		t.Errorf("writeHeatmap() has %d cells, want %d", strings.Count(svg, "<rect"), want)
+	}
+}
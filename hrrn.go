@@ -0,0 +1,57 @@
+package main
+
+import "io"
+
+//region Highest response ratio next
+
+// highestResponseRatio dispatches the ready task with the highest response
+// ratio, (wait + burst) / burst, to completion. Short jobs go first as under
+// shortest job first, but a long job's ratio grows as it waits, so it cannot
+// starve. Ties go to the task that arrived first, then the lower ID.
+type highestResponseRatio struct {
+	now int64
+}
+
+func (p *highestResponseRatio) setNow(now int64) { p.now = now }
+
+// wait returns how long t has spent on the ready queue by now.
+func (t *task) wait(now int64) int64 {
+	return now - t.readyAt - t.ran - t.blockedFor
+}
+
+func (p *highestResponseRatio) pick(ready []*task) int {
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		// Compare the ratios cross-multiplied, exactly
+		r := (t.wait(p.now) + t.BurstDuration) * max(b.BurstDuration, 1)
+		br := (b.wait(p.now) + b.BurstDuration) * max(t.BurstDuration, 1)
+		switch {
+		case r != br:
+			if r > br {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (*highestResponseRatio) quantum(*task) int64 { return 0 }
+
+// Highest response ratio next
+func HRRNSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, hrrn(processes, options{}), display{})
+}
+
+func hrrn(processes []Process, opts options) Result {
+	return simulate(processes, &highestResponseRatio{}, opts)
+}
+
+//endregion
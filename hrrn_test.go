@@ -0,0 +1,55 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_hrrn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			// At 6, P2 has ratio (5+4)/4 and P3 (1+2)/2, so the shorter P3
+			// waits for P2 that has waited longer
+			name: "highest ratio",
+			csv:  "1,6,0\n2,4,1\n3,2,5\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 6}, {PID: 2, Start: 6, Stop: 10}, {PID: 3, Start: 10, Stop: 12}},
+		},
+		{
+			// At 10, P2 has ratio (9+10)/10 and P3 (0+1)/1, so P2 runs
+			// ahead of the short P3 that just arrived, unlike under SJF
+			name: "no starvation",
+			csv:  "1,10,0\n2,10,1\n3,1,10\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 10}, {PID: 2, Start: 10, Stop: 20}, {PID: 3, Start: 20, Stop: 21}},
+		},
+		{
+			// Equal ratios go to the process that arrived first
+			name: "tie",
+			csv:  "1,2,0\n3,4,1\n2,4,1\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 6}, {PID: 3, Start: 6, Stop: 10}},
+		},
+		{
+			name: "honors arrivals",
+			csv:  "1,2,0\n2,3,5\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 5, Stop: 8}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hrrn(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hrrn() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+//region iCalendar export
+
+// icsTime is the UTC date-time format of iCalendar.
+const icsTime = "20060102T150405Z"
+
+// writeICS writes the Gantt chart of one algorithm as an iCalendar file with
+// an event per slice, so a schedule can be viewed in any calendar app. Time
+// unit 0 is start and each unit lasts unit.
+func writeICS(w io.Writer, name string, r Result, start time.Time, unit time.Duration) error {
+	bw := bufio.NewWriter(w)
+	// iCalendar lines end in CRLF
+	line := func(format string, a ...any) {
+		_, _ = fmt.Fprintf(bw, format+"\r\n", a...)
+	}
+	at := func(t int64) string {
+		return start.Add(time.Duration(t) * unit).UTC().Format(icsTime)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Process_Scheduler//Schedule export//EN")
+	line("X-WR-CALNAME:%s", icsText(name))
+	for i, s := range r.Gantt {
+		line("BEGIN:VEVENT")
+		line("UID:%d-%d-%d@process-scheduler", i, s.PID, s.Start)
+		// The stamp is fixed so the same schedule always exports the same file
+		line("DTSTAMP:%s", at(0))
+		line("DTSTART:%s", at(s.Start))
+		line("DTEND:%s", at(s.Stop))
+		line("SUMMARY:Process %d", s.PID)
+		if i < len(r.reasons) {
+			line("DESCRIPTION:%s\\, time %d-%d\\, %s", icsText(name), s.Start, s.Stop, r.reasons[i])
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// icsText escapes s for an iCalendar text value.
+func icsText(s string) string {
+	var out []rune
+	for _, r := range s {
+		switch r {
+		case '\\', ';', ',':
+			out = append(out, '\\', r)
+		case '\n':
+			out = append(out, '\\', 'n')
+		default:
+			out = append(out, r)
+		}
+	}
+
+	return string(out)
+}
+
+func writeICSFile(name, key string, processes []Process, opts options, start time.Time, unit time.Duration) error {
+	if unit <= 0 {
+		return fmt.Errorf("%w: -ics-unit %v must be greater than 0", ErrInvalidArgs, unit)
+	}
+	for _, alg := range algorithms {
+		if alg.key != key {
+			continue
+		}
+		f, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("%w: error creating calendar", err)
+		}
+		if err := writeICS(f, alg.name, alg.run(processes, opts), start, unit); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("%w: error writing calendar", err)
+		}
+
+		return f.Close()
+	}
+
+	return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, key)
+}
+
+//endregion
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_writeICS(t *testing.T) {
+	t.Parallel()
+	r := rr([]Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 7},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 2},
+	}, options{})
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var b bytes.Buffer
+	if err := writeICS(&b, "Round-robin, q=5", r, start, time.Hour); err != nil {
+		t.Fatalf("writeICS() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Round-robin\\, q=5\r\n",
+		"DTSTART:20260302T090000Z\r\nDTEND:20260302T140000Z\r\nSUMMARY:Process 1\r\n",
+		"DTSTART:20260302T140000Z\r\nDTEND:20260302T160000Z\r\nSUMMARY:Process 2\r\n",
+		"DTSTART:20260302T160000Z\r\nDTEND:20260302T180000Z\r\nSUMMARY:Process 1\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeICS() missing %q in\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != len(r.Gantt) {
+		t.Errorf("writeICS() wrote %d events, want %d", n, len(r.Gantt))
+	}
+}
+
+func Test_writeICSFileUnit(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 3}}
+	for _, unit := range []time.Duration{0, -time.Minute} {
+		name := filepath.Join(t.TempDir(), "out.ics")
+		if err := writeICSFile(name, "fcfs", processes, options{}, time.Time{}, unit); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("writeICSFile(unit %v) error = %v, want %v", unit, err, ErrInvalidArgs)
+		}
+		if _, err := os.Stat(name); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("writeICSFile(unit %v) created %s", unit, name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+//region Invariants
+
+// invariantHistory is how many of the latest events a violation dumps.
+const invariantHistory = 16
+
+type (
+	// invariants checks the engine after every event when -debug-invariants
+	// is set: that its clock never goes back, that no task has run more
+	// than its burst, and that every process moves between the states of
+	// its life in a legal order. A violation is a bug in the engine or a
+	// policy, so it panics with a dump of the engine's state.
+	invariants struct {
+		clock  int64
+		states map[int64]processState
+		recent []engineEvent
+	}
+
+	// processState is where a process is in its life, as the events show.
+	processState int
+)
+
+const (
+	// stateNew processes have not arrived or been spawned yet.
+	stateNew processState = iota
+	stateSpawned
+	stateReady
+	stateRunning
+	stateBlocking
+	stateBlocked
+	stateDone
+)
+
+var processStates = [...]string{"new", "spawned", "ready", "running", "blocking", "blocked", "done"}
+
+func (s processState) String() string { return processStates[s] }
+
+// transitions holds, for every event, the states a process may be in
+// before it and the state each moves it to. A running process that blocks
+// stays blocking until its slice stops, and one that is interrupted runs on
+// once the handler is done.
+var transitions = map[string]map[processState]processState{
+	"arrive":    {stateNew: stateReady, stateSpawned: stateReady},
+	"dispatch":  {stateReady: stateRunning},
+	"stop":      {stateRunning: stateReady, stateBlocking: stateBlocked},
+	"interrupt": {stateReady: stateRunning},
+	"block":     {stateRunning: stateBlocking},
+	"wake":      {stateBlocked: stateReady},
+	"spawn":     {stateNew: stateSpawned},
+	"exit":      {stateReady: stateDone, stateBlocked: stateDone},
+	"killed":    {stateNew: stateDone, stateSpawned: stateDone, stateReady: stateDone, stateBlocked: stateDone},
+	"dropped":   {stateNew: stateDone, stateSpawned: stateDone, stateReady: stateDone, stateBlocked: stateDone},
+}
+
+func newInvariants() *invariants {
+	return &invariants{states: make(map[int64]processState)}
+}
+
+// check checks e right after ev, panicking if any invariant is violated.
+func (in *invariants) check(e *engine, ev engineEvent) {
+	in.recent = append(in.recent, ev)
+	if len(in.recent) > invariantHistory {
+		in.recent = in.recent[1:]
+	}
+
+	now := e.clock.now()
+	if now < in.clock {
+		in.fail(e, fmt.Sprintf("clock went back from %d to %d", in.clock, now))
+	}
+	in.clock = now
+
+	if t, ok := e.byID[ev.PID]; ok {
+		if t.remaining < 0 || t.ran < 0 || t.ran+t.remaining != t.BurstDuration {
+			in.fail(e, fmt.Sprintf("P%d ran %d and has %d remaining of a burst of %d", t.ProcessID, t.ran, t.remaining, t.BurstDuration))
+		}
+	}
+
+	// Signals leave the state as it is
+	moves, ok := transitions[ev.Type]
+	if !ok {
+		return
+	}
+	state := in.states[ev.PID]
+	to, ok := moves[state]
+	if !ok {
+		in.fail(e, fmt.Sprintf("P%d is %s, so it cannot %s", ev.PID, state, ev.Type))
+	}
+	in.states[ev.PID] = to
+}
+
+// fail panics with what went wrong, the latest events and the state of
+// every task.
+func (in *invariants) fail(e *engine, violation string) {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "invariant violated: %s\nclock %d, %d of %d tasks done\n", violation, e.clock.now(), e.done, len(e.tasks))
+	_, _ = fmt.Fprintln(&b, "latest events:")
+	for _, ev := range in.recent {
+		_, _ = fmt.Fprintf(&b, "  %+v\n", ev)
+	}
+	_, _ = fmt.Fprintln(&b, "tasks:")
+	for _, t := range e.tasks {
+		_, _ = fmt.Fprintf(&b, "  P%d %s: ran %d, remaining %d, ready at %d, blocked for %d\n",
+			t.ProcessID, in.states[t.ProcessID], t.ran, t.remaining, t.readyAt, t.blockedFor)
+	}
+	panic(b.String())
+}
+
+//endregion
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_invariants(t *testing.T) {
+	t.Parallel()
+	// Blocking on children and signals, yields, non-preemptible regions,
+	// kills and interrupts all move processes between states
+	processes, err := loadProcesses(strings.NewReader("1,10,0,2,np=3-6,yield=2;8,wait=9,await=4\n" +
+		"2,6,1,1,signal=1@3\n" +
+		"3,4,2,3,parent=1,spawn=2\n" +
+		"4,5,3\n" +
+		"5,3,4,0,deadline=6\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := options{
+		debugInvariants: true,
+		events:          []Event{{Time: 12, Kind: eventKill, PID: 4}},
+		interrupts:      &interruptLoad{fraction: 0.1},
+		aging:           &agingConfig{every: 3, step: 1},
+	}
+	for _, alg := range algorithms {
+		alg := alg
+		t.Run(alg.key, func(t *testing.T) {
+			t.Parallel()
+			// Any violation panics
+			alg.run(processes, opts)
+		})
+	}
+}
+
+func Test_invariantsViolated(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,5,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		events []engineEvent
+		want   string
+	}{
+		{"dispatch before arrival", []engineEvent{{Type: "dispatch", PID: 1}}, "P1 is new, so it cannot dispatch"},
+		{"exit while running", []engineEvent{{Type: "arrive", PID: 1}, {Type: "dispatch", PID: 1}, {Type: "exit", PID: 1}}, "P1 is running, so it cannot exit"},
+		{"wake after exit", []engineEvent{{Type: "arrive", PID: 1}, {Type: "exit", PID: 1}, {Type: "wake", PID: 1}}, "P1 is done, so it cannot wake"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := newEngine(processes, firstCome{}, options{})
+			in := newInvariants()
+			defer func() {
+				dump := fmt.Sprint(recover())
+				if !strings.Contains(dump, tt.want) || !strings.Contains(dump, "latest events:") {
+					t.Errorf("check() panicked with %q, want %q", dump, tt.want)
+				}
+			}()
+			for _, ev := range tt.events {
+				in.check(e, ev)
+			}
+		})
+	}
+}
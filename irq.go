@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Interrupt load
+
+// interruptLoad steals a fraction of the CPU from running processes, one time
+// unit at a time, as interrupt handlers do.
+type interruptLoad struct {
+	// fraction is the share of time units an interrupt takes.
+	fraction float64
+	// random spreads the interrupts randomly instead of periodically.
+	random bool
+	// seed picks the random interrupt times.
+	seed int64
+}
+
+// newInterruptLoad validates the interrupt load flags. A fraction of 0 means
+// no interrupts.
+func newInterruptLoad(fraction float64, random bool, seed int64) (*interruptLoad, error) {
+	if fraction < 0 || fraction >= 1 || math.IsNaN(fraction) {
+		return nil, fmt.Errorf("%w: -irq must be at least 0 and less than 1", ErrInvalidArgs)
+	}
+	if fraction == 0 {
+		return nil, nil
+	}
+
+	return &interruptLoad{fraction: fraction, random: random, seed: seed}, nil
+}
+
+// steals reports whether an interrupt takes the time unit starting at at.
+// Random interrupts hash the time rather than draw from a generator, so
+// every algorithm is interrupted at the same times.
+func (irq *interruptLoad) steals(at int64) bool {
+	if !irq.random {
+		period := max(int64(math.Round(1/irq.fraction)), 1)
+		return at%period == period-1
+	}
+
+	// splitmix64 finalizer
+	x := uint64(irq.seed) ^ uint64(at)*0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return float64(x>>11)/(1<<53) < irq.fraction
+}
+
+// outputInterrupts compares the tail latency of every algorithm with and
+// without the interrupt load.
+func outputInterrupts(w io.Writer, processes []Process, opts options) {
+	quiet := opts
+	quiet.interrupts = nil
+
+	outputTitle(w, "Interrupt load")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Stolen", "P99 Response", "Change", "P99 Turnaround", "Change"})
+	for _, alg := range algorithms {
+		r := alg.run(append([]Process(nil), processes...), opts)
+		base := alg.run(append([]Process(nil), processes...), quiet)
+		l, lb := latencies(r.processes, r.Gantt), latencies(base.processes, base.Gantt)
+		response, baseResponse := percentile(l.response, 99), percentile(lb.response, 99)
+		turnaround, baseTurnaround := percentile(l.turnaround, 99), percentile(lb.turnaround, 99)
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(r.Stolen),
+			fmt.Sprint(response),
+			fmt.Sprintf("%+d", response-baseResponse),
+			fmt.Sprint(turnaround),
+			fmt.Sprintf("%+d", turnaround-baseTurnaround),
+		})
+	}
+	table.Render()
+}
+
+//endregion
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_newInterruptLoad(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		fraction float64
+		want     *interruptLoad
+		wantErr  error
+	}{
+		{"none", 0, nil, nil},
+		{"periodic", 0.05, &interruptLoad{fraction: 0.05, seed: 1}, nil},
+		{"negative", -0.1, nil, ErrInvalidArgs},
+		{"all", 1, nil, ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := newInterruptLoad(tt.fraction, false, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("newInterruptLoad() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newInterruptLoad() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_interruptLoad_steals(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		irq  interruptLoad
+	}{
+		{"periodic", interruptLoad{fraction: 0.05}},
+		{"random", interruptLoad{fraction: 0.05, random: true, seed: 7}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var stolen int
+			for at := int64(0); at < 100000; at++ {
+				if tt.irq.steals(at) {
+					stolen++
+				}
+			}
+			if stolen < 4800 || stolen > 5200 {
+				t.Errorf("steals() took %d of 100000 units, want about 5000", stolen)
+			}
+		})
+	}
+}
+
+func Test_interrupts(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,5,0\n2,2,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := options{interrupts: &interruptLoad{fraction: 0.25}}
+	r := fcfs(processes, opts)
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 1, Start: 4, Stop: 6}, {PID: 2, Start: 6, Stop: 7}, {PID: 2, Start: 8, Stop: 9}}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("fcfs() gantt = %v, want %v", r.Gantt, want)
+	}
+	if r.Stolen != 2 {
+		t.Errorf("fcfs() stolen = %d, want 2", r.Stolen)
+	}
+
+	var out bytes.Buffer
+	outputInterrupts(&out, processes, opts)
+	var row []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.Contains(line, "First-come, first-serve") {
+			row = strings.Fields(strings.ReplaceAll(line, "|", " "))
+		}
+	}
+	if want := []string{"First-come,", "first-serve", "2", "6", "+1", "9", "+2"}; !reflect.DeepEqual(row, want) {
+		t.Errorf("outputInterrupts() FCFS row = %v, want %v\n%s", row, want, out.String())
+	}
+}
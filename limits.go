@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//region Resource limits
+
+// Limits cap what a run may take, so that a pathological workload, say from
+// a request to a server or a submission to a grading pipeline, cannot tie up
+// the machine. Zero means no limit.
+type Limits struct {
+	// Processes caps the processes in the workload, periodic jobs included.
+	Processes int
+	// Time caps the simulated time.
+	Time int64
+	// Events caps the engine events, such as dispatches and arrivals, of
+	// each run.
+	Events int
+}
+
+// checkProcesses returns ErrLimitExceeded if processes has more processes
+// than l allows.
+func (l Limits) checkProcesses(processes []Process) error {
+	if l.Processes > 0 && len(processes) > l.Processes {
+		return fmt.Errorf("%w: %d processes, more than the limit of %d", ErrLimitExceeded, len(processes), l.Processes)
+	}
+
+	return nil
+}
+
+// overLimit returns ErrLimitExceeded once the run has gone past the
+// simulated time or number of events its limits allow.
+func (e *engine) overLimit() error {
+	l := e.opts.limits
+	if l.Time > 0 && e.clock.now() > l.Time {
+		return fmt.Errorf("%w: simulated time %d is past the limit of %d", ErrLimitExceeded, e.clock.now(), l.Time)
+	}
+	if l.Events > 0 && e.logged > l.Events {
+		return fmt.Errorf("%w: %d engine events, more than the limit of %d", ErrLimitExceeded, e.logged, l.Events)
+	}
+
+	return nil
+}
+
+// ScheduleLimited is Schedule within limits. It returns ErrLimitExceeded,
+// and no result, if the workload has more processes than they allow or the
+// run goes past them.
+func ScheduleLimited(ctx context.Context, key string, processes []Process, limits Limits) (Result, error) {
+	run := algorithmByKey(key)
+	if run == nil {
+		return Result{}, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, key)
+	}
+	if err := limits.checkProcesses(processes); err != nil {
+		return Result{}, err
+	}
+	r := run(processes, options{ctx: ctx, limits: limits})
+	if errors.Is(r.err, ErrTimeout) || errors.Is(r.err, ErrLimitExceeded) {
+		return Result{}, r.err
+	}
+
+	return r, r.err
+}
+
+// checkRuns runs every algorithm over processes for the statistics only and
+// returns the first run to go past opts.limits, so that the CLI fails before
+// it writes anything rather than print schedules cut short.
+func checkRuns(processes []Process, opts options) error {
+	opts.lazy = true
+	for _, alg := range algorithms {
+		if r := alg.run(processes, opts); errors.Is(r.err, ErrLimitExceeded) {
+			return fmt.Errorf("%w under %s", r.err, alg.name)
+		}
+	}
+
+	return nil
+}
+
+//endregion
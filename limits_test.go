@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScheduleLimited(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 12},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 8},
+	}
+	tests := []struct {
+		name    string
+		key     string
+		limits  Limits
+		wantErr error
+	}{
+		{"no limits", "rr", Limits{}, nil},
+		{"within limits", "rr", Limits{Processes: 2, Time: 20, Events: 100}, nil},
+		{"too many processes", "fcfs", Limits{Processes: 1}, ErrLimitExceeded},
+		{"past the time limit", "rr", Limits{Time: 10}, ErrLimitExceeded},
+		// The only slice runs past the limit
+		{"last slice past the time limit", "fcfs", Limits{Time: 19}, ErrLimitExceeded},
+		{"too many events", "rr", Limits{Events: 5}, ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ScheduleLimited(context.Background(), tt.key, processes, tt.limits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ScheduleLimited() error = %v, want %v", err, tt.wantErr)
+			}
+			if (len(got.Gantt) > 0) != (tt.wantErr == nil) {
+				t.Errorf("ScheduleLimited() gantt = %v, want one only without an error", got.Gantt)
+			}
+		})
+	}
+}
+
+func Test_checkRuns(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 12},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 8},
+	}
+	if err := checkRuns(processes, options{limits: Limits{Time: 20, Events: 1000}}); err != nil {
+		t.Errorf("checkRuns() error = %v within the limits", err)
+	}
+	if err := checkRuns(processes, options{limits: Limits{Time: 19}}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("checkRuns() error = %v, want %v", err, ErrLimitExceeded)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Least laxity first
+
+type (
+	// leastLaxity dispatches the ready task with the least laxity, the time
+	// it can still afford to wait: its absolute deadline less the time now
+	// and the burst it has left. Tasks without a deadline have all the
+	// laxity in the world and run last. It reconsiders its choice every unit.
+	//
+	// Plain LLF thrashes when tasks tie for the least laxity, as each one
+	// waiting falls behind the one running and preempts it in turn. Like
+	// Oh and Yang's modified LLF, a tie goes to the task due first, then
+	// the one that arrived first and the lower ID, which then keeps the CPU
+	// until it is done or another task runs out of laxity while it still
+	// has some; once both are out, switching would only thrash again.
+	leastLaxity struct {
+		now int64
+		// hold is the task that won the last tie, if it still holds the CPU.
+		hold *task
+		// trace holds the decisions where the ready tasks or the choice
+		// changed, when tracing.
+		trace   []llfDecision
+		tracing bool
+	}
+
+	// llfDecision is a pick: when, the laxity of every ready task, by ID,
+	// and the task dispatched.
+	llfDecision struct {
+		at       int64
+		laxities []llfLaxity
+		chosen   int64
+	}
+	llfLaxity struct {
+		pid, laxity int64
+	}
+)
+
+// laxity returns how long t can wait at now and still meet its deadline, or
+// math.MaxInt64 if it has none.
+func (t *task) laxity(now int64) int64 {
+	if t.Deadline == 0 {
+		return math.MaxInt64
+	}
+
+	return t.absoluteDeadline() - now - t.remaining
+}
+
+func (p *leastLaxity) reset() {
+	*p = leastLaxity{tracing: p.tracing}
+}
+
+func (p *leastLaxity) setNow(now int64) { p.now = now }
+
+func (p *leastLaxity) pick(ready []*task) int {
+	best, held, urgent, tied := 0, -1, false, false
+	for i, t := range ready {
+		if t == p.hold {
+			held = i
+		} else if t.laxity(p.now) <= 0 {
+			urgent = true
+		}
+		b := ready[best]
+		switch l, bl := t.laxity(p.now), b.laxity(p.now); {
+		case l != bl:
+			if l < bl {
+				best, tied = i, false
+			}
+			continue
+		case i > 0:
+			tied = true
+		}
+		switch d, bd := t.absoluteDeadline(), b.absoluteDeadline(); {
+		case d != bd:
+			if d < bd {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	switch {
+	case held >= 0 && (!urgent || ready[held].laxity(p.now) <= 0):
+		best = held
+	case tied:
+		p.hold = ready[best]
+	default:
+		p.hold = nil
+	}
+	if p.tracing {
+		p.record(ready, best)
+	}
+
+	return best
+}
+
+// record adds the pick of ready[chosen] to the trace, unless the same tasks
+// were ready and the same one chosen at the last pick.
+func (p *leastLaxity) record(ready []*task, chosen int) {
+	d := llfDecision{at: p.now, chosen: ready[chosen].ProcessID}
+	for _, t := range ready {
+		d.laxities = append(d.laxities, llfLaxity{pid: t.ProcessID, laxity: t.laxity(p.now)})
+	}
+	sort.Slice(d.laxities, func(i, j int) bool { return d.laxities[i].pid < d.laxities[j].pid })
+	if n := len(p.trace); n > 0 && p.trace[n-1].chosen == d.chosen && len(p.trace[n-1].laxities) == len(d.laxities) {
+		same := true
+		for i, l := range p.trace[n-1].laxities {
+			same = same && l.pid == d.laxities[i].pid
+		}
+		if same {
+			return
+		}
+	}
+	p.trace = append(p.trace, d)
+}
+
+func (*leastLaxity) quantum(*task) int64 { return 1 }
+
+// Least laxity first
+func LLFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, llf(processes, options{}), display{})
+	outputLaxity(w, processes, options{}, display{})
+}
+
+func llf(processes []Process, opts options) Result {
+	return simulate(processes, &leastLaxity{}, opts)
+}
+
+// llfTrace runs least laxity first and returns its decisions as well.
+func llfTrace(processes []Process, opts options) (Result, []llfDecision) {
+	p := &leastLaxity{tracing: true}
+	r := simulate(processes, p, opts)
+
+	return r, p.trace
+}
+
+// outputLaxity shows the laxity of every ready task at each decision of
+// least laxity first where the ready tasks or the choice changed. Tasks
+// without a deadline show a dash.
+func outputLaxity(w io.Writer, processes []Process, opts options, d display) {
+	_, trace := llfTrace(append([]Process(nil), processes...), opts)
+	rows := make([][]string, len(trace))
+	for i, dec := range trace {
+		laxities := make([]string, len(dec.laxities))
+		for j, l := range dec.laxities {
+			laxity := "-"
+			if l.laxity != math.MaxInt64 {
+				laxity = fmt.Sprint(l.laxity)
+			}
+			laxities[j] = fmt.Sprintf("P%d=%s", l.pid, laxity)
+		}
+		rows[i] = []string{fmt.Sprint(dec.at), strings.Join(laxities, " "), fmt.Sprint(dec.chosen)}
+	}
+	if head, tail, omitted := d.truncated(len(rows)); omitted > 0 {
+		marker := []string{"...", fmt.Sprintf("%d more", omitted), "..."}
+		rows = append(append(append([][]string(nil), rows[:head]...), marker), rows[len(rows)-tail:]...)
+	}
+
+	outputTitle(w, "Least-laxity-first laxity trace")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Time", "Laxities", "Dispatched"})
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+//endregion
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_llf(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			// Tied for laxity, P1 keeps the CPU instead of the two taking
+			// turns every unit
+			name: "tie",
+			csv:  "1,4,0,0,deadline=10\n2,4,0,0,deadline=10\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 8}},
+		},
+		{
+			// P1 holds the CPU after the tie until P3 has no laxity left,
+			// then P2 has none either. Overloaded, P1 and P2 tie again at
+			// no laxity, and P1 runs to completion rather than the two
+			// taking turns
+			name: "no laxity left",
+			csv:  "1,5,0,0,deadline=10\n2,5,0,0,deadline=10\n3,2,1,0,deadline=4\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 3, Start: 3, Stop: 5}, {PID: 2, Start: 5, Stop: 8}, {PID: 1, Start: 8, Stop: 10}, {PID: 2, Start: 10, Stop: 12}},
+		},
+		{
+			name: "least laxity first",
+			csv:  "1,2,0,0,deadline=10\n2,3,0,0,deadline=5\n3,1,0\n",
+			want: []TimeSlice{{PID: 2, Start: 0, Stop: 3}, {PID: 1, Start: 3, Stop: 5}, {PID: 3, Start: 5, Stop: 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := llf(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("llf() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_llfTrace(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,4,0,0,deadline=10\n2,4,0,0,deadline=10\n3,1,9\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, trace := llfTrace(processes, options{})
+	want := []llfDecision{
+		{at: 0, laxities: []llfLaxity{{1, 6}, {2, 6}}, chosen: 1},
+		{at: 4, laxities: []llfLaxity{{2, 2}}, chosen: 2},
+		{at: 9, laxities: []llfLaxity{{3, math.MaxInt64}}, chosen: 3},
+	}
+	if !reflect.DeepEqual(trace, want) {
+		t.Errorf("llfTrace() = %v, want %v", trace, want)
+	}
+
+	var out bytes.Buffer
+	outputLaxity(&out, processes, options{}, display{})
+	for _, want := range []string{"|    0 | P1=6 P2=6 |          1 |", "|    9 | P3=-      |          3 |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputLaxity() is missing %q in\n%s", want, out.String())
+		}
+	}
+}
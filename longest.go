@@ -0,0 +1,61 @@
+package main
+
+import "io"
+
+//region Longest job first
+
+type (
+	// longestJob dispatches the ready task with the longest burst, to
+	// completion, the opposite of shortestJob. It maximizes the average
+	// wait, which makes it a useful foil to SJF when teaching.
+	longestJob struct{}
+	// longestRemaining dispatches the ready task with the most of its burst
+	// left, reconsidering every unit, the opposite of shortestRemaining.
+	longestRemaining struct{}
+)
+
+func (longestJob) pick(ready []*task) int {
+	best := 0
+	for i := range ready {
+		if ready[i].BurstDuration > ready[best].BurstDuration {
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (longestJob) quantum(*task) int64 { return 0 }
+
+func (longestRemaining) pick(ready []*task) int {
+	best := 0
+	for i := range ready {
+		if ready[i].remaining > ready[best].remaining {
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (longestRemaining) quantum(*task) int64 { return 1 }
+
+// Longest job first
+func LJFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, ljf(processes, options{}), display{})
+}
+
+func ljf(processes []Process, opts options) Result {
+	return simulate(processes, longestJob{}, opts)
+}
+
+// Longest remaining time first, preemptive LJF
+func LRTFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, lrtf(processes, options{}), display{})
+}
+
+func lrtf(processes []Process, opts options) Result {
+	return simulate(processes, longestRemaining{}, opts)
+}
+
+//endregion
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ljf(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,2,0\n2,4,1\n3,6,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 3, Start: 2, Stop: 8}, {PID: 2, Start: 8, Stop: 12}}
+	r := ljf(processes, options{})
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("ljf() gantt = %v, want %v", r.Gantt, want)
+	}
+	// The longest job first waits the most, the opposite of SJF
+	if s := sjf(processes, options{}); r.AveWait <= s.AveWait {
+		t.Errorf("ljf() average wait %v, want more than sjf() %v", r.AveWait, s.AveWait)
+	}
+}
+
+func Test_lrtf(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,2,0\n2,5,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// P2 preempts P1 on arrival and runs until it has no more left than
+	// P1, after which the two take turns
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 5}, {PID: 1, Start: 5, Stop: 6}, {PID: 2, Start: 6, Stop: 7}}
+	r := lrtf(processes, options{})
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("lrtf() gantt = %v, want %v", r.Gantt, want)
+	}
+	if s := srpt(processes, options{}); r.AveWait <= s.AveWait {
+		t.Errorf("lrtf() average wait %v, want more than srpt() %v", r.AveWait, s.AveWait)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Lottery
+
+const (
+	// shareQuantum is how long the task a proportional-share policy picks
+	// runs, the same as round-robin's time slice.
+	shareQuantum = 5
+	// defaultTickets, less the priority, is how many tickets a process
+	// without a tickets attribute holds, and at least 1.
+	defaultTickets = 10
+)
+
+type (
+	// lottery draws a ticket among those of the ready tasks every quantum
+	// and runs the task holding it, so that over time each gets a share of
+	// the CPU in proportion to its tickets. The draws are seeded, so a run
+	// is reproducible.
+	lottery struct {
+		seed   int64
+		random *rand.Rand
+		shareLedger
+	}
+
+	// shareLedger keeps track of the share of the CPU proportional-share
+	// policies give each process against the share its tickets entitle it
+	// to, while it contends with others.
+	shareLedger struct {
+		shares map[int64]*cpuShare
+		// contenders are the tasks ready at the last pick, when another was
+		// ready too, and last the one dispatched; it had run lastRan then.
+		contenders []*task
+		last       *task
+		lastRan    int64
+	}
+
+	// cpuShare is what a process got while it contended with others for the
+	// CPU.
+	cpuShare struct {
+		// contended is the CPU time handed out while the process was ready
+		// together with others, ran how much of it the process got and
+		// expected how much its tickets entitled it to.
+		contended, ran int64
+		expected       float64
+		// tickets is how many tickets the process holds.
+		tickets int64
+	}
+)
+
+// tickets returns how many lottery tickets t holds.
+func (t *task) tickets() int64 {
+	if t.Tickets > 0 {
+		return t.Tickets
+	}
+
+	return max(defaultTickets-t.rank, 1)
+}
+
+func (p *lottery) reset() {
+	*p = lottery{seed: p.seed, random: rand.New(rand.NewSource(p.seed)), shareLedger: newShareLedger()}
+}
+
+func newShareLedger() shareLedger {
+	return shareLedger{shares: make(map[int64]*cpuShare)}
+}
+
+// charge accounts for what the last task dispatched ran among the
+// contenders.
+func (l *shareLedger) charge() {
+	if l.last == nil || len(l.contenders) == 0 {
+		return
+	}
+	d := l.last.ran - l.lastRan
+	var total int64
+	for _, t := range l.contenders {
+		total += t.tickets()
+	}
+	for _, t := range l.contenders {
+		s, ok := l.shares[t.ProcessID]
+		if !ok {
+			s = &cpuShare{tickets: t.tickets()}
+			l.shares[t.ProcessID] = s
+		}
+		s.contended += d
+		s.expected += float64(d*t.tickets()) / float64(total)
+		if t == l.last {
+			s.ran += d
+		}
+	}
+}
+
+// dispatch records that ready[i] is dispatched.
+func (l *shareLedger) dispatch(ready []*task, i int) {
+	l.contenders = l.contenders[:0]
+	if len(ready) > 1 {
+		l.contenders = append(l.contenders, ready...)
+	}
+	l.last, l.lastRan = ready[i], ready[i].ran
+}
+
+func (p *lottery) pick(ready []*task) int {
+	p.charge()
+	var total int64
+	for _, t := range ready {
+		total += t.tickets()
+	}
+	winner, draw := 0, p.random.Int63n(total)
+	for i, t := range ready {
+		if draw -= t.tickets(); draw < 0 {
+			winner = i
+			break
+		}
+	}
+	p.dispatch(ready, winner)
+
+	return winner
+}
+
+func (*lottery) quantum(*task) int64 { return shareQuantum }
+
+// LotterySchedule outputs the schedule of a lottery seeded with 1 and the
+// share of the CPU each process achieved against its share of the tickets.
+func LotterySchedule(w io.Writer, title string, processes []Process) {
+	opts := options{lotterySeed: 1}
+	outputResult(w, title, lotteryScheduling(processes, opts), display{})
+	outputLottery(w, processes, opts)
+}
+
+// Lottery, seeded with opts.lotterySeed
+func lotteryScheduling(processes []Process, opts options) Result {
+	r, _ := lotteryShares(processes, opts)
+	return r
+}
+
+// lotteryShares runs the lottery and returns what each process got out of
+// it as well.
+func lotteryShares(processes []Process, opts options) (Result, *lottery) {
+	p := &lottery{seed: opts.lotterySeed}
+	r := simulate(processes, p, opts)
+	// Charge the last winner
+	p.charge()
+
+	return r, p
+}
+
+// outputLottery shows the share of the CPU each process won in the lottery.
+func outputLottery(w io.Writer, processes []Process, opts options) {
+	_, p := lotteryShares(append([]Process(nil), processes...), opts)
+	outputShares(w, "Lottery shares", processes, p.shareLedger)
+}
+
+// outputShares shows, for every process that contended for the CPU, its
+// tickets and the share of the contended CPU time its tickets entitled it to
+// against the share it achieved.
+func outputShares(w io.Writer, title string, processes []Process, l shareLedger) {
+	outputTitle(w, title)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"PID", "Tickets", "Contended", "Target Share", "Achieved Share"})
+	for _, proc := range processes {
+		s, ok := l.shares[proc.ProcessID]
+		if !ok || s.contended == 0 {
+			continue
+		}
+		table.Append([]string{
+			fmt.Sprint(proc.ProcessID),
+			fmt.Sprint(s.tickets),
+			fmt.Sprint(s.contended),
+			fmt.Sprintf("%.1f%%", 100*s.expected/float64(s.contended)),
+			fmt.Sprintf("%.1f%%", 100*float64(s.ran)/float64(s.contended)),
+		})
+	}
+	table.Render()
+}
+
+//endregion
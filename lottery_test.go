@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_tickets(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		csv     string
+		want    int64
+		wantErr error
+	}{
+		{"attribute", "1,5,0,3,tickets=20\n", 20, nil},
+		{"from priority", "1,5,0,3\n", 7, nil},
+		{"at least one", "1,5,0,30\n", 1, nil},
+		{"not positive", "1,5,0,3,tickets=0\n", 0, ErrInvalidAttribute},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadProcesses() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := (&task{Process: processes[0], rank: options{}.rank(processes[0])}).tickets(); got != tt.want {
+				t.Errorf("tickets() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lottery(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,10000,0,0,tickets=3\n2,10000,0,0,tickets=1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same seed draws the same schedule
+	r, p := lotteryShares(processes, options{lotterySeed: 7})
+	if again := lotteryScheduling(processes, options{lotterySeed: 7}); !reflect.DeepEqual(again.Gantt, r.Gantt) {
+		t.Errorf("lotteryScheduling() is not reproducible")
+	}
+	if other := lotteryScheduling(processes, options{lotterySeed: 8}); reflect.DeepEqual(other.Gantt, r.Gantt) {
+		t.Errorf("lotteryScheduling() draws the same with another seed")
+	}
+
+	// Three tickets in four win about three quarters of the CPU
+	for pid, want := range map[int64]float64{1: 0.75, 2: 0.25} {
+		s := p.shares[pid]
+		if got := s.expected / float64(s.contended); math.Abs(got-want) > 1e-9 {
+			t.Errorf("P%d ticket share = %v, want %v", pid, got, want)
+		}
+		if got := float64(s.ran) / float64(s.contended); math.Abs(got-want) > 0.03 {
+			t.Errorf("P%d achieved share = %v, want about %v", pid, got, want)
+		}
+	}
+
+	var out bytes.Buffer
+	LotterySchedule(&out, "Lottery", processes)
+	if !strings.Contains(out.String(), "| 75.0%        |") {
+		t.Errorf("LotterySchedule() =\n%s", out.String())
+	}
+}
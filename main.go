@@ -1,484 +1,2062 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/csv"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"iter"
 	"log"
 	"math"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/olekukonko/tablewriter"
 )
 
 func main() {
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "generate":
+			if err := generateCommand(os.Stdout, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "anonymize":
+			if err := anonymizeCommand(os.Stdout, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "aggregate":
+			if err := aggregateCommand(os.Stdout, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "stress":
+			if err := stressCommand(os.Stdout, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "replay":
+			if err := replayCommand(os.Stdout, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "verify":
+			if err := verifyCommand(os.Stdout, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "verify-examples":
+			if err := verifyExamplesCommand(os.Stdout, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	// CLI flags
+	srptCheck := flag.Bool("srpt", false, "compare the mean flow time of every algorithm with SRPT's, which is optimal, instead of printing each schedule")
+	stream := flag.Bool("stream", false, "write each algorithm's Gantt slices and finished processes as CSV rows as soon as they are final, instead of printing each schedule")
+	tail := flag.Bool("tail", false, "compare algorithms by tail latency instead of printing each schedule")
+	cdf := flag.String("cdf", "", "with -tail, also write each algorithm's latency CDF as CSV to this file")
+	classesFile := flag.String("classes", "", "CSV file of process classes, one name,burst,priority[,key=value...] row of defaults per class, for processes with class=name to leave out")
+	events := flag.String("events", "", "CSV file of time,kind,pid events (kind is kill or signal) applied during the run")
+	swf := flag.String("swf", "", "also export the workload in the Standard Workload Format to this file")
+	dispatchTable := flag.String("dptbl", "", "CSV dispatch table for the SVR4 time-sharing scheduler, one quantum,tqexp,slpret,maxwait,lwait row per level")
+	bypass := flag.Int("bypass", defaultBypass, "how many shorter jobs may overtake the head of the queue under FCFS with limited bypass")
+	mlfqQuanta := flag.String("mlfq-quanta", "", "comma-separated quanta of the multilevel feedback queue, one per queue from the top, e.g. 2,4,8")
+	mlfqBoost := flag.Int64("mlfq-boost", defaultMLFQ.boost, "CPU time between boosts of every process to the top queue of the multilevel feedback queue, 0 for none")
+	priorityOrder := flag.String("priority-order", lowFirst, "which priorities come first: low-first, lower priority numbers first like Unix nice values, or high-first")
+	aging := flag.String("aging", "", "age ready processes under priority scheduling and the multilevel feedback queue, raising their priority by step every N units waited, as N[:step]")
+	debugInvariants := flag.Bool("debug-invariants", false, "check the engine's clock, accounting and process states after every event, panicking with a dump on the first violation")
+	mlqShares := flag.String("mlq-shares", "", "CPU shares of the system, interactive and batch queues of the multilevel queue, e.g. 60,30,10, instead of strict priority")
+	horizon := flag.Int64("horizon", 0, "release periodic processes without jobs= until this time")
+	lotterySeed := flag.Int64("lottery-seed", 1, "random seed of the lottery scheduler's draws")
+	randomSeed := flag.Int64("random-seed", 1, "random seed of the random dispatch baseline's draws")
+	srrRates := flag.String("srr-rates", "", "rates at which the priorities of new and accepted processes rise under selfish round-robin, as new:accepted, e.g. 2:1")
+	predictAlpha := flag.Float64("predict-alpha", defaultPredictAlpha, "weight α of the last CPU burst in the exponential average τ(n+1) = αt(n) + (1-α)τ(n) that predicts the next under SJF and SRTF with predicted bursts, from 0 (exclusive) to 1")
+	drrQuantum := flag.Int64("drr-quantum", defaultDRRQuantum, "credit deficit round-robin adds to a process's deficit at each visit")
+	foregroundShare := flag.Int("fg-share", defaultForegroundShare, "percentage of the CPU the foreground queue gets while the background queue also has work")
+	systemNonPreemptible := flag.Bool("sys-nonpreemptible", false, "make the system time of processes non-preemptible, like a non-preemptive kernel")
+	irq := flag.Float64("irq", 0, "fraction of the time interrupts steal from running processes, e.g. 0.05")
+	irqRandom := flag.Bool("irq-random", false, "with -irq, spread the interrupts randomly instead of periodically")
+	irqSeed := flag.Int64("irq-seed", 1, "with -irq-random, the seed of the interrupt times")
+	timeUnit := flag.Float64("time-unit", 1, "input time units per simulation time unit, so fractional trace times can be kept, e.g. 0.001 for milliseconds of a trace in seconds")
+	day := flag.Int64("day", 2400, "length of a simulated day, for processes with a time-of-day window")
+	precision := flag.Int("precision", defaultPrecision, "decimal places of averages and throughput in the schedule tables, HTML report and saved runs")
+	throughputUnit := flag.String("throughput-unit", "t", "unit of throughput: t (per time unit), 100t (per 100 time units) or the real duration of a time unit, e.g. 1ms, for per second")
+	maxRows := flag.Int("max-rows", 0, "show at most this many schedule rows and Gantt slices, keeping the first and last")
+	ascii := flag.Bool("ascii", false, "write only plain ASCII, replacing any other characters")
+	pager := flag.Bool("pager", false, "page the output through $PAGER (less by default)")
+	queue := flag.String("queue", "fifo", "ready queue discipline: fifo, burst-heap, priority-heap or levels")
+	outURI := flag.String("out", "", "write the output to a file, an http(s) URL (POST) or s3://bucket/key instead of standard output")
+	selfCheckFlag := flag.Bool("self-check", false, "when every process arrives at 0, check FCFS's and SJF's average waits against the closed-form ones, failing the run on a mismatch")
+	assertions := flag.String("assert", "", "file of assertions on the results, e.g. avg_wait[rr] < avg_wait[fcfs], failing the run if any does not hold")
+	ics := flag.String("ics", "", "also export one algorithm's schedule as an iCalendar file to view in a calendar app")
+	icsAlgorithm := flag.String("ics-algorithm", "fcfs", "with -ics, the algorithm whose schedule is exported")
+	icsStart := flag.String("ics-start", "2026-01-05T09:00:00Z", "with -ics, the RFC 3339 date-time of time 0")
+	icsUnit := flag.Duration("ics-unit", time.Minute, "with -ics, the real time one time unit stands for")
+	slicesCSV := flag.String("slices-csv", "", "also write every Gantt slice of every algorithm as CSV rows to this file")
+	pngDir := flag.String("png", "", "also render the Gantt charts and a bar chart of the averages as PNG images in this directory")
+	otlp := flag.String("otlp", "", "also export every algorithm's schedule as OpenTelemetry spans, one trace per algorithm, to this OTLP/JSON file or OTLP/HTTP URL such as http://localhost:4318/v1/traces")
+	otlpStart := flag.String("otlp-start", "", "with -otlp, the RFC 3339 date-time of time 0, by default the time of the run")
+	otlpUnit := flag.Duration("otlp-unit", time.Millisecond, "with -otlp, the real time one time unit stands for")
+	eventLog := flag.String("event-log", "", "also write every engine event of every algorithm to this file as JSON lines, for custom analysis")
+	metricsTextfile := flag.String("metrics-textfile", "", "also write every algorithm's summary metrics to this file in the Prometheus text format, for node_exporter's textfile collector")
+	report := flag.String("html", "", "also write an HTML report with each algorithm's Gantt timeline and toggleable overlays to this file")
+	heatmap := flag.String("heatmap", "", "also draw each algorithm's ready queue depth over time as an SVG heatmap in this file")
+	diff := flag.String("diff", "", "compare the Gantt charts of two algorithms, e.g. fcfs,rr, instead of printing each schedule")
+	hash := flag.Bool("hash", false, "append a hash of the results, checked by the verify command")
+	runFile := flag.String("json", "", "also save the run, with each algorithm's summary metrics, as JSON to this file for the aggregate command")
+	manifestFile := flag.String("manifest", "", "also write a JSON manifest of the version, command line, inputs and options to this file")
+	tick := flag.Float64("tick", defaultTick, "timer tick resolution: preemptive schedulers only notice an expired quantum on a tick, e.g. 2.5 or 10")
+	minGranularity := flag.Int64("min-granularity", 0, "least time a process runs before a preemptive scheduler may switch away from it")
+	maxProcesses := flag.Int("max-processes", 0, "fail if the workload has more processes than this, periodic jobs included, 0 for no limit")
+	maxTime := flag.Int64("max-time", 0, "fail if any algorithm's run goes past this simulated time, 0 for no limit")
+	maxEvents := flag.Int("max-events", 0, "fail if any algorithm's run has more engine events than this, 0 for no limit")
+	cpuProfile := flag.String("cpuprofile", "", "write a CPU profile to this file")
+	memProfile := flag.String("memprofile", "", "write a heap profile to this file when the run ends")
+	traceFile := flag.String("trace", "", "write an execution trace to this file")
+	flag.Parse()
+
+	// Profiling
+	stopProfiling, err := startProfiling(*cpuProfile, *memProfile, *traceFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := stopProfiling(); err != nil {
+			log.Print(err)
+		}
+	}()
+
 	// CLI args
-	f, closeFile, err := openProcessingFile(os.Args...)
+	f, closeFile, err := openProcessingFile(append([]string{os.Args[0]}, flag.Args()...)...)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer closeFile()
 
-	// Load and parse processes
-	processes, err := loadProcesses(f)
+	// Load and parse processes, from an SWF trace or the CSV format
+	if !(*timeUnit > 0) {
+		log.Fatal(fmt.Errorf("%w: -time-unit must be greater than 0", ErrInvalidArgs))
+	}
+	load := loadScaledProcesses
+	var input io.Reader = f
+	if strings.EqualFold(filepath.Ext(f.Name()), ".swf") {
+		load = loadScaledSWF
+	} else if *classesFile != "" {
+		// Fill in what processes leave to their class
+		classes, err := loadClassesFile(*classesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if input, err = expandClasses(f, classes); err != nil {
+			log.Fatal(err)
+		}
+	}
+	processes, err := load(input, *timeUnit)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if *maxProcesses < 0 || *maxTime < 0 || *maxEvents < 0 {
+		log.Fatal(fmt.Errorf("%w: -max-processes, -max-time and -max-events must not be negative", ErrInvalidArgs))
+	}
+	limits := Limits{Processes: *maxProcesses, Time: *maxTime, Events: *maxEvents}
+	if err := limits.checkProcesses(processes); err != nil {
+		log.Fatal(err)
+	}
+	if limits.Time > 0 && *horizon > limits.Time {
+		log.Fatal(fmt.Errorf("%w: -horizon %d is past the limit of %d", ErrLimitExceeded, *horizon, limits.Time))
+	}
+	if processes, err = expandPeriodic(processes, *horizon); err != nil {
+		log.Fatal(err)
+	}
+	if err := limits.checkProcesses(processes); err != nil {
+		log.Fatal(err)
+	}
+
+	// Workload export
+	if *swf != "" {
+		if err := writeSWFFile(*swf, processes); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *bypass < 1 {
+		log.Fatal(fmt.Errorf("%w: -bypass must be at least 1", ErrInvalidArgs))
+	}
+	if *drrQuantum < 1 {
+		log.Fatal(fmt.Errorf("%w: -drr-quantum must be at least 1", ErrInvalidArgs))
+	}
+	if !(*predictAlpha > 0 && *predictAlpha <= 1) {
+		log.Fatal(fmt.Errorf("%w: -predict-alpha must be greater than 0 and at most 1", ErrInvalidArgs))
+	}
+	if *foregroundShare < 1 || *foregroundShare > 99 {
+		log.Fatal(fmt.Errorf("%w: -fg-share must be from 1 to 99", ErrInvalidArgs))
+	}
+	if err := checkTick(*tick); err != nil {
+		log.Fatal(err)
+	}
+	if *precision < 1 || *precision > 15 {
+		log.Fatal(fmt.Errorf("%w: -precision must be from 1 to 15", ErrInvalidArgs))
+	}
+	d := display{maxRows: *maxRows, precision: *precision}
+	if d.throughputScale, d.throughputSuffix, err = parseThroughputUnit(*throughputUnit); err != nil {
+		log.Fatal(err)
+	}
+	throughputUnitSet := false
+	flag.Visit(func(f *flag.Flag) { throughputUnitSet = throughputUnitSet || f.Name == "throughput-unit" })
+	if *timeUnit != 1 && !throughputUnitSet {
+		// Per simulation unit, a fine unit would round throughput to nothing
+		d.throughputScale, d.throughputSuffix = 1 / *timeUnit, "/input t"
+	}
+	opts := options{
+		minGranularity:       *minGranularity,
+		tick:                 *tick,
+		day:                  *day,
+		bypass:               *bypass,
+		lotterySeed:          *lotterySeed,
+		randomSeed:           *randomSeed,
+		foregroundShare:      *foregroundShare,
+		drrQuantum:           *drrQuantum,
+		predictAlpha:         *predictAlpha,
+		systemNonPreemptible: *systemNonPreemptible,
+		debugInvariants:      *debugInvariants,
+		limits:               limits,
+	}
+	if *mlfqQuanta != "" || *mlfqBoost != defaultMLFQ.boost {
+		if *mlfqBoost < 0 {
+			log.Fatal(fmt.Errorf("%w: -mlfq-boost must not be negative", ErrInvalidArgs))
+		}
+		opts.mlfq = &mlfqConfig{quanta: defaultMLFQ.quanta, boost: *mlfqBoost}
+		if *mlfqQuanta != "" {
+			if opts.mlfq.quanta, err = parseQuanta(*mlfqQuanta); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	if opts.highPriorityFirst, err = parsePriorityOrder(*priorityOrder); err != nil {
+		log.Fatal(err)
+	}
+	if *aging != "" {
+		if opts.aging, err = parseAging(*aging); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *srrRates != "" {
+		rates, err := parseSRRRates(*srrRates)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.srrRates = &rates
+	}
+	if *mlqShares != "" {
+		if opts.mlqShares, err = parseShares(*mlqShares); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if opts.interrupts, err = newInterruptLoad(*irq, *irqRandom, *irqSeed); err != nil {
+		log.Fatal(err)
+	}
+	if opts.queue, err = queueByKey(*queue); err != nil {
+		log.Fatal(err)
+	}
+	if *events != "" {
+		if opts.events, err = loadEventsFile(*events, *timeUnit); err != nil {
+			log.Fatal(err)
+		}
+		if err := checkEvents(opts.events, processes); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *dispatchTable != "" {
+		if opts.dispatchTable, err = loadDispatchTableFile(*dispatchTable); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Runs past the limits fail before anything is written
+	if limits.Time > 0 || limits.Events > 0 {
+		if err := checkRuns(processes, opts); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Run manifest and saved run
+	if *manifestFile != "" || *runFile != "" {
+		inputs := []string{f.Name()}
+		if *events != "" {
+			inputs = append(inputs, *events)
+		}
+		if *classesFile != "" {
+			inputs = append(inputs, *classesFile)
+		}
+		m, err := newManifest(flag.CommandLine, os.Args, inputs...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *manifestFile != "" {
+			if err := writeManifestFile(*manifestFile, m); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if *runFile != "" {
+			if err := writeRunFile(*runFile, newRunRecord(m, processes, opts, d)); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	// Ready queue heatmap
+	if *heatmap != "" {
+		if err := writeHeatmapFile(*heatmap, processes, opts); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Calendar of one schedule
+	if *ics != "" {
+		start, err := time.Parse(time.RFC3339, *icsStart)
+		if err != nil {
+			log.Fatal(fmt.Errorf("%w: %w", ErrInvalidArgs, err))
+		}
+		if err := writeICSFile(*ics, *icsAlgorithm, processes, opts, start, *icsUnit); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Gantt slices in long format
+	if *slicesCSV != "" {
+		if err := writeSlicesCSVFile(*slicesCSV, processes, opts); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// PNG charts
+	if *pngDir != "" {
+		if err := writePNGDir(*pngDir, processes, opts); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Schedules as OpenTelemetry traces
+	if *otlp != "" {
+		start := time.Now()
+		if *otlpStart != "" {
+			if start, err = time.Parse(time.RFC3339, *otlpStart); err != nil {
+				log.Fatal(fmt.Errorf("%w: %w", ErrInvalidArgs, err))
+			}
+		}
+		if err := exportOTLP(*otlp, processes, opts, start, *otlpUnit); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Engine events as JSON lines
+	if *eventLog != "" {
+		if err := writeEventLogFile(*eventLog, processes, opts); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Metrics for the node_exporter textfile collector
+	if *metricsTextfile != "" {
+		if err := writeMetricsTextFile(*metricsTextfile, processes, opts); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// HTML report
+	if *report != "" {
+		if err := writeReportFile(*report, processes, opts, d); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Output, to a file, URL or object store, or through a pager if asked
+	dest, err := openSink(*outURI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := dest.Close(); err != nil {
+			log.Print(err)
+			exitCode = 1
+		}
+	}()
+	var out io.Writer = dest
+	if *pager && (*outURI == "" || *outURI == "-") {
+		w, closePager, err := startPager()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer closePager()
+		out = w
+	}
+	if *hash {
+		// Hash exactly what is written, so it is appended last of all
+		var results bytes.Buffer
+		hashed := out
+		defer func() { writeResultsHash(hashed, results.Bytes()) }()
+		out = io.MultiWriter(out, &results)
+	}
+	if *ascii {
+		out = &asciiWriter{w: out}
+	}
+	if *timeUnit != 1 {
+		_, _ = fmt.Fprintf(out, "Times are in simulation units (t), each %g input time units (input t). Throughput is per %s.\n",
+			*timeUnit, strings.TrimPrefix(d.throughputSuffix, "/"))
+	}
+
+	// The analytic check, like the assertions, fails the run once the
+	// output is complete
+	if *selfCheckFlag {
+		defer func() {
+			if err := selfCheck(out, processes, opts, d); err != nil {
+				log.Print(err)
+				exitCode = 1
+			}
+		}()
+	}
+
+	// Assertions are checked after everything else is printed, failing the
+	// run once the output is complete
+	if *assertions != "" {
+		defer func() {
+			if err := checkAssertionsFile(out, *assertions, processes, opts); err != nil {
+				log.Print(err)
+				exitCode = 1
+			}
+		}()
+	}
+
+	// Side-by-side Gantt charts of two algorithms
+	if *diff != "" {
+		if err := diffCommand(out, *diff, processes, opts, d); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Gap to the optimal mean flow time
+	if *srptCheck {
+		outputSRPT(out, processes, opts, d)
+		return
+	}
+
+	// Results written as they are final
+	if *stream {
+		if err := streamResults(out, processes, opts); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Tail-latency comparison
+	if *tail {
+		outputTail(out, processes, opts)
+		if *cdf != "" {
+			if err := writeCDFFile(*cdf, processes, opts); err != nil {
+				log.Fatal(err)
+			}
+		}
+		return
+	}
+
+	// First-come, first-serve, shortest job first, priority and round robin
+	for _, alg := range algorithms {
+		outputResult(out, alg.name, alg.run(processes, opts), d)
+	}
+
+	// Effect of the minimum granularity on the preemptive schedulers
+	if opts.minGranularity > 0 {
+		outputGranularity(out, processes, opts, d)
+	}
+
+	// Effect of the timer tick on the preemptive schedulers
+	if opts.tick != defaultTick {
+		outputTick(out, processes, opts, d)
+	}
+
+	// How processes moved through the multilevel feedback queue
+	if opts.mlfq != nil {
+		outputMLFQ(out, processes, opts)
+	}
+
+	// Error of the burst predictions, where processes replay several bursts
+	// or the prediction is tuned
+	for i := range processes {
+		if len(processes[i].Bursts) > 1 || opts.predictAlpha != defaultPredictAlpha {
+			outputPrediction(out, processes, opts, d)
+			break
+		}
+	}
+
+	// Effective priorities of the schedulers that age
+	if opts.aging != nil {
+		outputAging(out, processes, opts, d)
+	}
+
+	// Share of the CPU each process won in the lottery and got under stride
+	// scheduling and weighted round-robin
+	for i := range processes {
+		if processes[i].Tickets > 0 {
+			outputLottery(out, processes, opts)
+			outputStride(out, processes, opts)
+			outputWRR(out, processes, opts)
+			break
+		}
+	}
+
+	// Share of the CPU each group got under fair-share scheduling
+	for i := range processes {
+		if processes[i].Group != "" {
+			outputFairShare(out, processes, opts)
+			break
+		}
+	}
+
+	// Tail latency added by interrupts
+	if opts.interrupts != nil {
+		outputInterrupts(out, processes, opts)
+	}
+
+	// User and system time
+	for i := range processes {
+		if len(processes[i].System) > 0 {
+			outputAccounting(out, processes, opts)
+			break
+		}
+	}
+
+	// Latency added by non-preemptible regions
+	for i := range processes {
+		if len(processes[i].NonPreemptible) > 0 || opts.systemNonPreemptible && len(processes[i].System) > 0 {
+			outputDeferredWait(out, processes, opts, d)
+			break
+		}
+	}
+
+	// How each scheduler treats voluntary yields
+	for i := range processes {
+		if len(processes[i].Yields) > 0 {
+			outputYields(out, processes, opts, d)
+			break
+		}
+	}
+
+	// How ULE classified processes that block
+	for i := range processes {
+		if processes[i].WaitOffset > 0 || len(processes[i].Awaits) > 0 {
+			outputInteractivity(out, processes, opts)
+			break
+		}
+	}
+
+	// Periodic tasks admitted by rate-monotonic scheduling
+	for i := range processes {
+		if processes[i].Period > 0 {
+			outputAdmission(out, processes)
+			break
+		}
+	}
+
+	// How least laxity first decided
+	for i := range processes {
+		if processes[i].Deadline > 0 {
+			outputLaxity(out, processes, opts, d)
+			break
+		}
+	}
+
+	// Jobs dropped for missing their deadline by more than their tolerance
+	for i := range processes {
+		if processes[i].Tolerance != nil {
+			outputDrops(out, processes, opts)
+			break
+		}
+	}
+
+	// Makespan of each process tree
+	for i := range processes {
+		if processes[i].Spawn != nil {
+			outputTrees(out, processes, opts)
+			break
+		}
+	}
+}
+
+// startPager starts $PAGER, or less, and returns a writer to its input and a
+// function that waits for the user to quit it.
+func startPager() (io.Writer, func(), error) {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: error starting pager", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("%w: error starting pager", err)
+	}
+	closeFn := func() {
+		_ = in.Close()
+		_ = cmd.Wait()
+	}
+
+	return in, closeFn, nil
+}
+
+func openProcessingFile(args ...string) (*os.File, func(), error) {
+	if len(args) != 2 {
+		return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
+	}
+	// Read in CSV process CSV file
+	f, err := os.Open(args[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: error opening scheduling file", err)
+	}
+	closeFn := func() {
+		if err := f.Close(); err != nil {
+			log.Fatalf("%v: error closing scheduling file", err)
+		}
+	}
+
+	return f, closeFn, nil
+}
+
+type (
+	Process struct {
+		ProcessID     int64
+		ArrivalTime   int64
+		BurstDuration int64
+		Priority      int64
+		// NonPreemptible lists the parts of the burst a preemptive scheduler
+		// must not interrupt, e.g. critical sections.
+		NonPreemptible []Span
+		// System lists the parts of the burst spent in the kernel on the
+		// process's behalf; the rest is user time.
+		System []Span
+		// Yields are offsets into the burst where the process voluntarily
+		// gives up the CPU without blocking.
+		Yields []int64
+		// Bursts, when set, are the CPU bursts of a measured trace, run in
+		// order one per visit to the CPU; BurstDuration is their sum.
+		Bursts []int64
+		// Spawn, when set, makes this a child process created once its parent
+		// has run part of its burst. Its arrival column is then ignored.
+		Spawn *Spawn
+		// WaitOffset, when positive, blocks the process once it has run that
+		// much of its burst until every child it has spawned completes.
+		WaitOffset int64
+		// Awaits are offsets into the burst where the process blocks until
+		// it is signalled, unless a signal is already waiting for it.
+		Awaits []int64
+		// Signals are raised to other processes as this one runs.
+		Signals []Signal
+		// Window, when set, holds the process back until the time of day
+		// falls within it, e.g. batch jobs only admitted overnight.
+		Window *Span
+		// Deadline, when positive, is the time after arrival by which the
+		// process should complete.
+		Deadline int64
+		// Tolerance, when set, is how late past its deadline the process may
+		// still complete. A deadline scheduler drops it once it is later.
+		Tolerance *int64
+		// Task groups processes that are jobs of the same task, e.g. the
+		// frames of a video stream. Empty means a task of its own.
+		Task string
+		// Warp, under borrowed virtual time, is how far ahead in virtual
+		// time the process is dispatched, for lower latency. WarpLimit, when
+		// positive, is how long it may run warped after arriving or waking.
+		Warp      int64
+		WarpLimit int64
+		// Class is the type of job the process is, e.g. "backup", for
+		// schedulers that only know a job's type and not its burst.
+		Class string
+		// Group is the user or group the process belongs to, among which
+		// the fair-share scheduler divides the CPU. Empty means a group of
+		// its own.
+		Group string
+		// Period, when positive, makes the process a periodic task, released
+		// every Period units from its arrival. Jobs, when positive, is how
+		// many times; otherwise it is released until the horizon.
+		// expandPeriodic turns it into a process per job.
+		Period int64
+		Jobs   int64
+		// Tickets, when positive, is how many lottery tickets the process
+		// holds; otherwise it holds defaultTickets less its rank.
+		Tickets int64
+		// Background puts the process in the background queue of the
+		// foreground/background scheduler.
+		Background bool
+	}
+	// Signal wakes process PID, or is kept for its next await, once the
+	// signalling process has run Offset units of its burst.
+	Signal struct {
+		PID    int64
+		Offset int64
+	}
+	// Spawn places a child process in a process tree: it arrives when the
+	// parent has run Offset units of its burst.
+	Spawn struct {
+		Parent int64
+		Offset int64
+	}
+	// Span is the half-open range [From, To) of a process's own execution
+	// time, or of the time of day for a window.
+	Span struct {
+		From int64
+		To   int64
+	}
+	TimeSlice struct {
+		PID   int64 `json:"pid"`
+		Start int64 `json:"start"`
+		Stop  int64 `json:"stop"`
+	}
+	// Result is the outcome of running a scheduling algorithm over a set of processes.
+	Result struct {
+		// processes are the scheduled processes in arrival order, with the
+		// arrival of spawned children set to when they were spawned.
+		processes []Process
+		// outcomes are the per-process figures the footer statistics are
+		// computed from, in the order of processes.
+		outcomes []processOutcome
+		Schedule [][]string
+		// Gantt is the Gantt chart, or nil when the result streams it; use
+		// Slices to read either.
+		Gantt         []TimeSlice
+		stream        iter.Seq[TimeSlice]
+		AveWait       float64
+		AveTurnaround float64
+		AveThroughput float64
+		// DeferredWait is the wait non-preemptible regions added: the total
+		// wait of the run less that of the same run without the regions.
+		DeferredWait int64
+		// Yields counts the voluntary yields the scheduler honored.
+		Yields int
+		// Dispatches counts the times a process was given the CPU.
+		Dispatches int
+		// Stolen is the time interrupts took from running processes.
+		Stolen int64
+		// err is ErrUnschedulable, ErrTimeout or ErrLimitExceeded when the
+		// run could not complete.
+		err error
+		// depth is the ready queue depth over time, as a step function.
+		depth []depthSample
+		// reasons says why each Gantt slice ended, e.g. sliceExit.
+		reasons []string
+		// dropped holds the processes a deadline scheduler dropped.
+		dropped map[int64]bool
+		// completions holds, for each of processes with a deadline, when it
+		// completed, or -1 if it did not. It is nil when none has one.
+		completions []int64
+	}
+	// processOutcome is how one process fared: its wait and turnaround, when
+	// it left and whether it completed rather than being killed, dropped or
+	// blocked for good.
+	processOutcome struct {
+		wait, turnaround, completion int64
+		completed                    bool
+	}
+	// options are the tunables shared by every scheduler.
+	options struct {
+		// minGranularity is the least time a process runs before a preemptive
+		// scheduler may switch away from it, like CFS's min_granularity.
+		minGranularity int64
+		// tick is the timer tick resolution; quanta expire on the first tick
+		// at or after their end. 0 means every time unit.
+		tick float64
+		// events are external happenings, such as kills, applied during the run.
+		events []Event
+		// day is the length of a simulated day for time-of-day windows.
+		day int64
+		// lazy streams the Gantt chart on demand instead of keeping it.
+		lazy bool
+		// onSlice and onExit, when set, are given each Gantt slice and each
+		// finished process as soon as they are final. The Gantt chart is then
+		// not kept, and onSlice returning false stops the run.
+		onSlice func(TimeSlice) bool
+		onExit  func(t *task)
+		// onEvent, when set, is given every engine event as it happens.
+		onEvent func(engineEvent)
+		// clock makes the simulated clock for each run, and must return a new
+		// one on every call for runs to be independent; nil is instantClock.
+		clock func() clock
+		// queue makes a new ready queue for each run; nil is fifoQueue.
+		queue func() readyQueue
+		// ctx, when set, stops the run with ErrTimeout once it is done.
+		ctx context.Context
+		// limits stop the run with ErrLimitExceeded once it goes past them.
+		limits Limits
+		// dispatchTable is the SVR4 time-sharing dispatch table; nil is
+		// defaultDispatchTable.
+		dispatchTable []dispatchLevel
+		// bypass is how many shorter jobs may overtake the head of the queue
+		// under FCFS with limited bypass; 0 is defaultBypass.
+		bypass int
+		// mlfq sets up the multilevel feedback queue; nil is defaultMLFQ.
+		mlfq *mlfqConfig
+		// highPriorityFirst has every scheduler that reads the priority
+		// column run higher priority numbers first rather than lower ones.
+		highPriorityFirst bool
+		// aging, if set, ages ready processes under priority scheduling and
+		// the multilevel feedback queue.
+		aging *agingConfig
+		// debugInvariants checks the engine after every event, panicking
+		// on the first violation.
+		debugInvariants bool
+		// mlqShares are the CPU shares of the multilevel queues, which have
+		// strict priority when nil.
+		mlqShares []int64
+		// lotterySeed seeds the draws of the lottery scheduler.
+		lotterySeed int64
+		// randomSeed seeds the draws of the random dispatch baseline.
+		randomSeed int64
+		// foregroundShare is the percentage of the CPU the foreground queue
+		// gets while both have work; 0 is defaultForegroundShare.
+		foregroundShare int
+		// srrRates are the rates at which priorities rise under selfish
+		// round-robin; nil is defaultSRRRates.
+		srrRates *srrRates
+		// predictAlpha is the weight of the last CPU burst in the prediction
+		// of the next under SJF and SRTF with predicted bursts; 0 is
+		// defaultPredictAlpha.
+		predictAlpha float64
+		// drrQuantum is the credit deficit round-robin gives a process per
+		// visit; 0 is defaultDRRQuantum.
+		drrQuantum int64
+		// systemNonPreemptible makes system time non-preemptible, as in a
+		// kernel that cannot be preempted.
+		systemNonPreemptible bool
+		// interrupts, when set, steal time from running processes.
+		interrupts *interruptLoad
+	}
+	// Event is something that happens to a process at a simulated time,
+	// loaded from an events file.
+	Event struct {
+		Time int64
+		Kind string
+		PID  int64
+	}
+)
+
+// Event kinds accepted in an events file.
+const (
+	// eventKill terminates the process, keeping whatever it has run so far.
+	eventKill = "kill"
+	// eventSignal signals the process, waking it from an await.
+	eventSignal = "signal"
+)
+
+// algorithms lists every scheduler in the order they are run and compared.
+// Each has a short key used to refer to it in files such as examples.
+var algorithms = []struct {
+	key        string
+	name       string
+	run        func([]Process, options) Result
+	preemptive bool
+}{
+	{"fcfs", "First-come, first-serve", fcfs, false},
+	{"sjf", "Shortest-job-first", sjf, false},
+	{"priority", "Priority", sjfPriority, false},
+	{"rr", "Round-robin", rr, true},
+	{"edf", "Earliest-deadline-first", edf, true},
+	{"bvt", "Borrowed virtual time", bvt, true},
+	{"o1", "Linux O(1)", o1, true},
+	{"svr4", "SVR4 time-sharing", svr4, true},
+	{"ule", "FreeBSD ULE", ule, true},
+	{"sept", "Shortest-expected-time", sept, false},
+	{"fb", "FCFS with limited bypass", fbk, false},
+	{"fgbg", "Foreground/background", fgbg, true},
+	{"srpt", "Shortest-remaining-time", srpt, true},
+	{"ppriority", "Preemptive priority", priorityPreemptive, true},
+	{"mlfq", "Multilevel feedback queue", mlfq, true},
+	{"mlq", "Multilevel queue", mlq, true},
+	{"lottery", "Lottery", lotteryScheduling, true},
+	{"stride", "Stride", strideScheduling, true},
+	{"cfs", "Completely fair", cfs, true},
+	{"rms", "Rate-monotonic", rms, true},
+	{"llf", "Least-laxity-first", llf, true},
+	{"hrrn", "Highest-response-ratio-next", hrrn, false},
+	{"ljf", "Longest-job-first", ljf, false},
+	{"lrtf", "Longest-remaining-time", lrtf, true},
+	{"prr", "Priority round-robin", priorityRR, true},
+	{"wrr", "Weighted round-robin", wrr, true},
+	{"drr", "Deficit round-robin", drr, true},
+	{"srr", "Selfish round-robin", srr, true},
+	{"fair", "Fair share", fairShareScheduling, true},
+	{"eevdf", "Earliest eligible virtual deadline first", eevdf, true},
+	{"random", "Random dispatch", randomScheduling, true},
+	{"psjf", "Predicted shortest-job-first", psjf, false},
+	{"psrtf", "Predicted shortest-remaining-time", psrtf, true},
+}
+
+// preemptionPoint returns the earliest offset into the burst, at or after
+// offset, where the process may be preempted.
+func (p Process) preemptionPoint(offset int64) int64 {
+	for moved := true; moved; {
+		moved = false
+		for _, span := range p.NonPreemptible {
+			if span.From < offset && offset < span.To {
+				offset = span.To
+				moved = true
+			}
+		}
+	}
+
+	return offset
+}
+
+// nice is the priority read as a Unix nice value, clamped to -20 to 19, for
+// schedulers emulating Unix ones. It is negated when higher priorities come
+// first, so they still get more of the CPU.
+func (t *task) nice() int64 {
+	return min(max(t.rank, -20), 19)
+}
+
+// nextYield returns the first yield point in (from, to), or to when the
+// process does not yield in between.
+func (p Process) nextYield(from, to int64) int64 {
+	for _, y := range p.Yields {
+		if from < y && y < to {
+			to = y
+		}
+	}
+
+	return to
+}
+
+// burstEnd returns the end of the replayed burst the process is in once
+// it has run ran, or its whole burst when it replays none.
+func (p Process) burstEnd(ran int64) int64 {
+	var end int64
+	for _, b := range p.Bursts {
+		if end += b; end > ran {
+			return end
+		}
+	}
+
+	return p.BurstDuration
+}
+
+//region Schedulers
+
+// Every scheduler is safe to call from many goroutines at once, e.g. one per
+// request in a server: each run has an engine of its own, the caller's
+// processes are copied rather than sorted in place, and the algorithm and
+// queue registries are never modified.
+
+// Schedule runs the scheduler registered under key, e.g. "fcfs", over
+// processes. It returns ErrTimeout if ctx is done before the run completes,
+// and the result along with ErrUnschedulable if some processes blocked for
+// good.
+func Schedule(ctx context.Context, key string, processes []Process) (Result, error) {
+	return ScheduleLimited(ctx, key, processes, Limits{})
+}
+
+// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+func FCFSSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, fcfs(processes, options{}), display{})
+}
+
+func fcfs(processes []Process, opts options) Result {
+	return simulate(processes, firstCome{}, opts)
+}
+
+// Shortest job first, priority
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, sjfPriority(processes, options{}), display{})
+}
+
+func sjfPriority(processes []Process, opts options) Result {
+	if opts.aging != nil {
+		r, _ := priorityAging(processes, opts, false)
+		return r
+	}
+
+	return simulate(processes, highestPriority{}, opts)
+}
+
+// Shortest job first
+func SJFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, sjf(processes, options{}), display{})
+}
+
+func sjf(processes []Process, opts options) Result {
+	return simulate(processes, shortestJob{}, opts)
+}
+
+// Round robin
+func RRSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, rr(processes, options{}), display{})
+}
+
+func rr(processes []Process, opts options) Result {
+	// Max amount of time that each process can execute before moving onto next process
+	return simulate(processes, roundRobin{slice: 5}, opts)
+}
+
+//endregion
+
+//region Output helpers
+
+func init() {
+	// Column widths are counted the same way whatever the locale; in CJK
+	// locales tablewriter would otherwise count ambiguous-width characters
+	// twice and misalign the tables.
+	runewidth.DefaultCondition.EastAsianWidth = false
+}
+
+// asciiWriter passes output through as plain ASCII for logs and assignments
+// submitted as text. Tables and Gantt charts are already drawn in ASCII;
+// any other character, e.g. from a process name or a path, is replaced with
+// an ASCII look-alike or '?'.
+type asciiWriter struct {
+	w io.Writer
+	// partial holds the start of a character split across writes.
+	partial []byte
+}
+
+// asciiReplacements are the look-alikes for common typographic characters.
+var asciiReplacements = map[rune]string{
+	'–': "-", '—': "--", '‘': "'", '’': "'",
+	'“': "\"", '”': "\"", '…': "...", '\u00a0': " ",
+	'─': "-", '│': "|", '┌': "+", '┐': "+",
+	'└': "+", '┘': "+", '├': "+", '┤': "+",
+}
+
+func (a *asciiWriter) Write(p []byte) (int, error) {
+	buf := append(a.partial, p...)
+	a.partial = nil
+	var b strings.Builder
+	for len(buf) > 0 {
+		if buf[0] < utf8.RuneSelf {
+			b.WriteByte(buf[0])
+			buf = buf[1:]
+			continue
+		}
+		if !utf8.FullRune(buf) {
+			a.partial = append([]byte(nil), buf...)
+			break
+		}
+		r, size := utf8.DecodeRune(buf)
+		buf = buf[size:]
+		if s, ok := asciiReplacements[r]; ok {
+			b.WriteString(s)
+			continue
+		}
+		b.WriteByte('?')
+	}
+	if _, err := io.WriteString(a.w, b.String()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// display holds the settings for how results are printed.
+type display struct {
+	// maxRows, when positive, limits the schedule table rows and Gantt
+	// slices shown, keeping the first and last ones around a marker.
+	maxRows int
+	// precision is the decimal places of averages and throughput; 0 is
+	// defaultPrecision.
+	precision int
+	// throughputScale converts throughput per time unit into the reported
+	// unit, named by throughputSuffix; zero values report per time unit.
+	throughputScale  float64
+	throughputSuffix string
+}
+
+// parseThroughputUnit parses the unit throughput is reported in: "t" for per
+// time unit, "100t" for per 100 time units, or the real duration one time
+// unit stands for, e.g. "1ms", for per second.
+func parseThroughputUnit(s string) (scale float64, suffix string, err error) {
+	switch s {
+	case "t":
+		return 1, "/t", nil
+	case "100t":
+		return 100, "/100t", nil
+	}
+	unit, err := time.ParseDuration(s)
+	if err != nil || unit <= 0 {
+		return 0, "", fmt.Errorf("%w: throughput unit %q must be t, 100t or a duration such as 1ms", ErrInvalidArgs, s)
+	}
+
+	return float64(time.Second) / float64(unit), "/s", nil
+}
+
+// throughput formats a throughput per time unit in the reported unit.
+func (d display) throughput(v float64) string {
+	if d.throughputScale == 0 {
+		return d.format(v) + "/t"
+	}
+
+	return d.format(v*d.throughputScale) + d.throughputSuffix
+}
+
+// defaultPrecision is the decimal places averages and throughput are shown with.
+const defaultPrecision = 2
+
+// places returns the decimal places averages and throughput are shown with.
+func (d display) places() int {
+	if d.precision == 0 {
+		return defaultPrecision
+	}
+
+	return d.precision
+}
+
+// format formats an average or throughput to the display precision, rounding
+// to nearest with ties to even.
+func (d display) format(v float64) string {
+	return strconv.FormatFloat(v, 'f', d.places(), 64)
+}
+
+// truncated splits n items into the number shown from the head and from the
+// tail, and how many are omitted in between.
+func (d display) truncated(n int) (head, tail, omitted int) {
+	if d.maxRows <= 0 || n <= d.maxRows {
+		return n, 0, 0
+	}
+	head = (d.maxRows + 1) / 2
+	tail = d.maxRows - head
+
+	return head, tail, n - head - tail
+}
+
+func outputResult(w io.Writer, title string, r Result, d display) {
+	outputTitle(w, title)
+	outputGantt(w, r.Gantt, d)
+	outputSchedule(w, r.Schedule, r.AveWait, r.AveTurnaround, r.AveThroughput, deadlines(r), d)
+}
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+func outputGantt(w io.Writer, gantt []TimeSlice, d display) {
+	head, tail, omitted := d.truncated(len(gantt))
+	shown := gantt
+	if omitted > 0 {
+		shown = append(append([]TimeSlice(nil), gantt[:head]...), gantt[len(gantt)-tail:]...)
+	}
+
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	_, _ = fmt.Fprint(w, "|")
+	for i := range shown {
+		if omitted > 0 && i == head {
+			_, _ = fmt.Fprintf(w, " ...%d more... |", omitted)
+		}
+		pid := pidLabel(shown[i].PID)
+		padding := strings.Repeat(" ", (8-len(pid))/2)
+		_, _ = fmt.Fprint(w, padding, pid, padding, "|")
+	}
+	_, _ = fmt.Fprintln(w)
+	for i := range shown {
+		if omitted > 0 && i == head {
+			_, _ = fmt.Fprint(w, "...", "\t")
+		}
+		_, _ = fmt.Fprint(w, fmt.Sprint(shown[i].Start), "\t")
+		if len(shown)-1 == i {
+			_, _ = fmt.Fprint(w, fmt.Sprint(shown[i].Stop))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n\n")
+}
+
+// outputSchedule shows the schedule table, with a column of whether each
+// process met its deadline when dl is not nil.
+func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64, dl *deadlineReport, d display) {
+	header := []string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"}
+	footer := []string{"", "", "", "",
+		"Average\n" + d.format(wait),
+		"Average\n" + d.format(turnaround),
+		"Throughput\n" + d.throughput(throughput)}
+	marker := []string{"...", "", "", "", "", "", "..."}
+	if dl != nil {
+		header = append(header, "Deadline")
+		footer = append(footer, fmt.Sprintf("Missed %d of %d\nTardiness %d", dl.missed, dl.missed+dl.met, dl.tardiness))
+		marker = append(marker, "")
+		withDeadlines := make([][]string, len(rows))
+		for i, row := range rows {
+			withDeadlines[i] = append(row, dl.cells[i])
+		}
+		rows = withDeadlines
+	}
+	if head, tail, omitted := d.truncated(len(rows)); omitted > 0 {
+		marker[2] = fmt.Sprintf("%d more", omitted)
+		rows = append(append(append([][]string(nil), rows[:head]...), marker), rows[len(rows)-tail:]...)
+	}
+
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.AppendBulk(rows)
+	table.SetFooter(footer)
+	table.Render()
+}
+
+//endregion
+
+//region Tail latency
+
+// latency holds the per-process response and turnaround times of the given
+// processes in a schedule, each sorted ascending. Both are derived from the
+// Gantt chart: response is the first dispatch minus arrival and turnaround
+// is the last stop minus arrival.
+type latency struct {
+	response   []int64
+	turnaround []int64
+}
+
+func latencies(processes []Process, gantt []TimeSlice) latency {
+	arrival := make(map[int64]int64, len(processes))
+	for i := range processes {
+		arrival[processes[i].ProcessID] = processes[i].ArrivalTime
+	}
+
+	first := make(map[int64]int64)
+	last := finalStops(gantt)
+	order := make([]int64, 0, len(processes))
+	for _, slice := range gantt {
+		if _, ok := arrival[slice.PID]; !ok {
+			continue
+		}
+		if _, ok := first[slice.PID]; !ok {
+			first[slice.PID] = slice.Start
+			order = append(order, slice.PID)
+		}
+	}
 
-	// First-come, first-serve scheduling
-	FCFSSchedule(os.Stdout, "First-come, first-serve", processes)
+	var l latency
+	for _, pid := range order {
+		l.response = append(l.response, first[pid]-arrival[pid])
+		l.turnaround = append(l.turnaround, last[pid]-arrival[pid])
+	}
+	sort.Slice(l.response, func(i, j int) bool { return l.response[i] < l.response[j] })
+	sort.Slice(l.turnaround, func(i, j int) bool { return l.turnaround[i] < l.turnaround[j] })
 
-	// Shortest job first
-	SJFSchedule(os.Stdout, "Shortest-job-first", processes)
+	return l
+}
 
-	// Shortest job first, priority
-	SJFPrioritySchedule(os.Stdout, "Priority", processes)
+// finalStops returns when the final slice of each process in a Gantt chart
+// stopped. For a process that exited on the CPU, that is its completion in
+// the schedule table; one that ended its burst waiting for its children
+// completes when the last of them does, and one killed or dropped while
+// waiting when it was.
+func finalStops(gantt []TimeSlice) map[int64]int64 {
+	last := make(map[int64]int64)
+	for _, slice := range gantt {
+		last[slice.PID] = slice.Stop
+	}
 
-	// Round robin
-	RRSchedule(os.Stdout, "Round-robin", processes)
+	return last
 }
 
-func openProcessingFile(args ...string) (*os.File, func(), error) {
-	if len(args) != 2 {
-		return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
-	}
-	// Read in CSV process CSV file
-	f, err := os.Open(args[1])
-	if err != nil {
-		return nil, nil, fmt.Errorf("%v: error opening scheduling file", err)
-	}
-	closeFn := func() {
-		if err := f.Close(); err != nil {
-			log.Fatalf("%v: error closing scheduling file", err)
+// fairness returns Jain's fairness index of the slowdown of every process that
+// ran, its turnaround over its burst: 1 when every process is slowed down
+// alike, falling towards 1/n as a few are slowed down far more than others.
+func fairness(r Result) float64 {
+	last := finalStops(r.Gantt)
+	var sum, squares float64
+	n := 0
+	for _, p := range r.processes {
+		stop, ok := last[p.ProcessID]
+		if !ok || p.BurstDuration == 0 {
+			continue
 		}
+		slowdown := float64(stop-p.ArrivalTime) / float64(p.BurstDuration)
+		sum += slowdown
+		squares += slowdown * slowdown
+		n++
+	}
+	if n == 0 {
+		return 1
 	}
 
-	return f, closeFn, nil
+	return sum * sum / (float64(n) * squares)
 }
 
-type (
-	Process struct {
-		ProcessID     int64
-		ArrivalTime   int64
-		BurstDuration int64
-		Priority      int64
+// percentile returns the nearest-rank p-th percentile of an ascending slice.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
 	}
-	TimeSlice struct {
-		PID   int64
-		Start int64
-		Stop  int64
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
 	}
-)
 
-// Sorting helper functions
-func sortBurstDuration(processes []Process) []Process {
-	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].BurstDuration < processes[j].BurstDuration
-	})
+	return sorted[rank-1]
+}
 
-	return processes
+func outputTail(w io.Writer, processes []Process, opts options) {
+	outputTitle(w, "Tail latency")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "P50 Response", "P99 Response", "Max Response", "P99 Turnaround", "Max Turnaround", "Fairness"})
+	for _, alg := range algorithms {
+		r := alg.run(append([]Process(nil), processes...), opts)
+		l := latencies(r.processes, r.Gantt)
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(percentile(l.response, 50)),
+			fmt.Sprint(percentile(l.response, 99)),
+			fmt.Sprint(percentile(l.response, 100)),
+			fmt.Sprint(percentile(l.turnaround, 99)),
+			fmt.Sprint(percentile(l.turnaround, 100)),
+			fmt.Sprintf("%.3f", fairness(r)),
+		})
+	}
+	table.Render()
 }
 
-func sortPriority(processes []Process) []Process {
-	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].Priority < processes[j].Priority
-	})
+// writeCDF writes the empirical response and turnaround CDF of every algorithm
+// in long format: algorithm, metric, value, cumulative fraction of processes.
+func writeCDF(w io.Writer, processes []Process, opts options) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"algorithm", "metric", "value", "fraction"}); err != nil {
+		return err
+	}
+	for _, alg := range algorithms {
+		r := alg.run(append([]Process(nil), processes...), opts)
+		l := latencies(r.processes, r.Gantt)
+		for _, m := range []struct {
+			name   string
+			values []int64
+		}{{"response", l.response}, {"turnaround", l.turnaround}} {
+			for i, v := range m.values {
+				err := cw.Write([]string{
+					alg.name,
+					m.name,
+					fmt.Sprint(v),
+					fmt.Sprintf("%.4f", float64(i+1)/float64(len(m.values))),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
 
-	return processes
+	return cw.Error()
 }
 
-func sortArrivalTime(processes []Process) []Process {
-	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].ArrivalTime < processes[j].ArrivalTime
-	})
+func writeCDFFile(name string, processes []Process, opts options) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating CDF file", err)
+	}
+	if err := writeCDF(f, processes, opts); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: error writing CDF file", err)
+	}
 
-	return processes
+	return f.Close()
 }
 
-//region Schedulers
-
-// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
-// • an output writer
-// • a title for the chart
-// • a slice of processes
-func FCFSSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-	for i := range processes {
-		if processes[i].ArrivalTime > 0 {
-			waitingTime = serviceTime - processes[i].ArrivalTime
-		}
-		totalWait += float64(waitingTime)
+//endregion
 
-		start := waitingTime + processes[i].ArrivalTime
+//region Minimum granularity
 
-		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
+// switches counts how many times the CPU moves from one process to another.
+func switches(gantt []TimeSlice) int {
+	var n int
+	for i := 1; i < len(gantt); i++ {
+		if gantt[i].PID != gantt[i-1].PID {
+			n++
+		}
+	}
 
-		completion := processes[i].BurstDuration + processes[i].ArrivalTime + waitingTime
-		lastCompletion = float64(completion)
+	return n
+}
 
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
+// outputGranularity compares each preemptive scheduler with and without the
+// minimum granularity, showing the switches saved and the latency paid for them.
+func outputGranularity(w io.Writer, processes []Process, opts options, d display) {
+	free := opts
+	free.minGranularity = 0
+	g := fmt.Sprintf("G=%d", opts.minGranularity)
+	outputTitle(w, "Minimum granularity")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Switches G=0", "Switches " + g,
+		"Ave Response G=0", "Ave Response " + g, "Max Turnaround G=0", "Max Turnaround " + g})
+	for _, alg := range algorithms {
+		if !alg.preemptive {
+			continue
 		}
-		serviceTime += processes[i].BurstDuration
-
-		gantt = append(gantt, TimeSlice{
-			PID:   processes[i].ProcessID,
-			Start: start,
-			Stop:  serviceTime,
+		base := alg.run(append([]Process(nil), processes...), free)
+		limited := alg.run(append([]Process(nil), processes...), opts)
+		lb, ll := latencies(base.processes, base.Gantt), latencies(limited.processes, limited.Gantt)
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(switches(base.Gantt)),
+			fmt.Sprint(switches(limited.Gantt)),
+			d.format(mean(lb.response)),
+			d.format(mean(ll.response)),
+			fmt.Sprint(percentile(lb.turnaround, 100)),
+			fmt.Sprint(percentile(ll.turnaround, 100)),
 		})
 	}
-
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	table.Render()
 }
 
-// Shortest job first, priority
-func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-
-	processes = sortPriority(processes)
+// outputDeferredWait shows, for each preemptive scheduler, how much longer
+// processes waited in all because of non-preemptible regions.
+func outputDeferredWait(w io.Writer, processes []Process, opts options, d display) {
+	outputTitle(w, "Non-preemptible regions")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Added Wait", "Ave Added Wait"})
+	for _, alg := range algorithms {
+		if !alg.preemptive {
+			continue
+		}
+		r := alg.run(append([]Process(nil), processes...), opts)
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(r.DeferredWait),
+			d.format(float64(r.DeferredWait) / float64(len(processes))),
+		})
+	}
+	table.Render()
+}
 
+// outputYields compares how every scheduler treats processes that yield: how
+// many yields it honored and the average turnaround of yielding processes
+// against the rest.
+func outputYields(w io.Writer, processes []Process, opts options, d display) {
+	yielding := make(map[int64]bool)
 	for i := range processes {
-		// Calculate waiting time
-		if i > 0 {
-			waitingTime += processes[i-1].BurstDuration
+		if len(processes[i].Yields) > 0 {
+			yielding[processes[i].ProcessID] = true
 		}
+	}
 
-		// Add to total waiting time
-		totalWait += float64(waitingTime)
-
-		// Calculate start time
-		start := waitingTime
-
-		// Calculate turnaround time
-		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
-
-		// Calculate completion time
-		completion := processes[i].BurstDuration + waitingTime
-		lastCompletion = float64(completion)
-
-		// Add to schedule
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
+	outputTitle(w, "Voluntary yields")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Yields Honored", "Ave Turnaround Yielding", "Ave Turnaround Others"})
+	for _, alg := range algorithms {
+		r := alg.run(append([]Process(nil), processes...), opts)
+		var yielders, others []Process
+		for i := range r.processes {
+			if yielding[r.processes[i].ProcessID] {
+				yielders = append(yielders, r.processes[i])
+			} else {
+				others = append(others, r.processes[i])
+			}
 		}
-
-		// Add to service time
-		serviceTime += processes[i].BurstDuration
-
-		// Add to GANTT chart
-		gantt = append(gantt, TimeSlice{
-			PID:   processes[i].ProcessID,
-			Start: start,
-			Stop:  serviceTime,
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(r.Yields),
+			d.format(mean(latencies(yielders, r.Gantt).turnaround)),
+			d.format(mean(latencies(others, r.Gantt).turnaround)),
 		})
 	}
-
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	table.Render()
 }
 
-// Shortest job first
-func SJFSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-
-	processes = sortBurstDuration(processes)
-
-	for i := range processes {
-		// Calculate waiting time
-		if i > 0 {
-			waitingTime += processes[i-1].BurstDuration
+// treeRoots maps every process that belongs to a process tree to the root of
+// its tree. Processes that neither spawn nor were spawned are left out.
+func treeRoots(processes []Process) map[int64]int64 {
+	parent := make(map[int64]int64)
+	for _, p := range processes {
+		if p.Spawn != nil {
+			parent[p.ProcessID] = p.Spawn.Parent
 		}
+	}
 
-		// Add to total waiting time
-		totalWait += float64(waitingTime)
-
-		// Calculate start time
-		start := waitingTime
-
-		// Calculate turnaround time
-		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
-
-		// Calculate completion time
-		completion := processes[i].BurstDuration + waitingTime
-		lastCompletion = float64(completion)
-
-		// Add to schedule
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
+	roots := make(map[int64]int64)
+	for pid := range parent {
+		rt := pid
+		for p, ok := parent[rt]; ok; p, ok = parent[rt] {
+			rt = p
 		}
+		roots[pid] = rt
+		roots[rt] = rt
+	}
 
-		// Add to service time
-		serviceTime += processes[i].BurstDuration
+	return roots
+}
 
-		// Add to GANTT chart
-		gantt = append(gantt, TimeSlice{
-			PID:   processes[i].ProcessID,
-			Start: start,
-			Stop:  serviceTime,
-		})
+// makespans returns, for the root of every process tree, the time from the
+// root's arrival until the last process in its tree completes.
+func makespans(r Result) map[int64]int64 {
+	roots := treeRoots(r.processes)
+	arrival := make(map[int64]int64)
+	for _, p := range r.processes {
+		arrival[p.ProcessID] = p.ArrivalTime
 	}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
+	spans := make(map[int64]int64)
+	for _, slice := range r.Gantt {
+		rt, ok := roots[slice.PID]
+		if !ok {
+			continue
+		}
+		if span := slice.Stop - arrival[rt]; span > spans[rt] {
+			spans[rt] = span
+		}
+	}
 
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return spans
 }
 
-// Round robin
-func RRSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-		// Max amount of time that each process can execute before moving onto next process
-		quantumTime int64
-		// Processes completed check
-		procsCompleted int64
-		// Arrays of ints to keep track of the time left and wait times for each process
-		timeLeft  []int64
-		waitTimes []int64
-		// Count to keep track of total number of time units that have elapsed
-		countTimeUnits float64
-		// Sum of wait times
-		sumWaitTimes float64
-		// Minimun calculation var to ensure that no process runs longer than quantumTime
-		min int64
-	)
-
-	processes = sortArrivalTime(processes)
-
-	quantumTime = 5
-	countTimeUnits = 0.0
-	sumWaitTimes = 0.0
-
-	// Create timeLeft array with the burst duration of each process
-	for i := range processes {
-		timeLeft = append(timeLeft, processes[i].BurstDuration)
-		waitTimes = append(waitTimes, 0)
-	}
-
-	for procsCompleted < int64(len(processes)) {
-		// Iterate over processes
-		for i := range processes {
+// outputTrees compares the makespan of every process tree across algorithms,
+// a row for each tree under each algorithm.
+func outputTrees(w io.Writer, processes []Process, opts options) {
+	roots := treeRoots(processes)
 
-			// If the time left at i is less than zero, continue
-			if timeLeft[i] <= 0 {
+	outputTitle(w, "Process tree makespan")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Tree Root", "Makespan"})
+	for _, alg := range algorithms {
+		spans := makespans(alg.run(append([]Process(nil), processes...), opts))
+		for _, p := range processes {
+			if rt, ok := roots[p.ProcessID]; !ok || rt != p.ProcessID {
 				continue
 			}
+			table.Append([]string{alg.name, fmt.Sprint(p.ProcessID), fmt.Sprint(spans[p.ProcessID])})
+		}
+	}
+	table.Render()
+}
 
-			min = int64(math.Min(float64(quantumTime), float64(timeLeft[i])))
-
-			// Calculate and update the waiting time for a process
-			if countTimeUnits > 0 {
-				waitingTime += min
-				waitTimes[i] += min
-			}
+func mean(values []int64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum int64
+	for _, v := range values {
+		sum += v
+	}
 
-			countTimeUnits++
+	return float64(sum) / float64(len(values))
+}
 
-			// Add to total waiting time
-			totalWait += float64(waitingTime)
+//endregion
 
-			// Calculate start time
-			start := waitingTime
+//region Loading processes.
 
-			// Calculate turnaround time
-			turnaround := min + waitingTime
-			totalTurnaround += float64(turnaround)
+// Errors fall into a few categories that the commands and tests can tell
+// apart with errors.Is.
+var (
+	// ErrParse is returned for input that is not well formed, such as a
+	// number that does not parse or a row with too few columns.
+	ErrParse = errors.New("parse error")
+	// ErrInvalidWorkload is returned for well-formed input describing a
+	// workload that cannot be simulated, such as a child of an unknown
+	// parent. ErrInvalidAttribute and ErrInvalidEvent are in this category.
+	ErrInvalidWorkload = errors.New("invalid workload")
+	// ErrUnschedulable is returned with the result of a run in which some
+	// processes could never finish, because they blocked for good.
+	ErrUnschedulable = errors.New("unschedulable workload")
+	// ErrTimeout is returned when a run is stopped by its context before it
+	// completes.
+	ErrTimeout = errors.New("simulation timed out")
+	// ErrLimitExceeded is returned when a workload or run goes past the
+	// limits set on it.
+	ErrLimitExceeded = errors.New("resource limit exceeded")
+
+	ErrInvalidArgs      = errors.New("invalid args")
+	ErrInvalidAttribute = categorized("invalid process attribute", ErrInvalidWorkload)
+	ErrInvalidEvent     = categorized("invalid event", ErrInvalidWorkload)
+)
 
-			// Calculate completion time
-			completion := min + waitingTime
-			lastCompletion = float64(completion)
+// categoryError is an error value that also matches its wider category.
+type categoryError struct {
+	msg      string
+	category error
+}
 
-			// Append to schedule
-			schedule = append(schedule, []string{
-				fmt.Sprint(processes[i].ProcessID),
-				fmt.Sprint(processes[i].Priority),
-				fmt.Sprint(processes[i].BurstDuration),
-				fmt.Sprint(processes[i].ArrivalTime),
-				fmt.Sprint(waitingTime),
-				fmt.Sprint(turnaround),
-				fmt.Sprint(completion),
-			})
+func categorized(msg string, category error) error {
+	return &categoryError{msg: msg, category: category}
+}
 
-			// Add to service time
-			serviceTime = start + min
+func (e *categoryError) Error() string { return e.msg }
+func (e *categoryError) Unwrap() error { return e.category }
 
-			// Add to GANTT chart
-			gantt = append(gantt, TimeSlice{
-				PID:   processes[i].ProcessID,
-				Start: start,
-				Stop:  serviceTime,
-			})
+func loadProcesses(r io.Reader) ([]Process, error) {
+	return loadScaledProcesses(r, 1)
+}
 
-			// Subtract the min of the quantumTime and the time left at i from the time left at i
-			timeLeft[i] -= min
+// loadScaledProcesses loads processes whose burst and arrival columns are in
+// input time units, unit of which make one simulation time unit. With a unit
+// other than 1 they may be fractional and are rounded to whole simulation
+// units, though a burst never rounds down to nothing.
+func loadScaledProcesses(r io.Reader, unit float64) ([]Process, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV: %w", ErrParse, err)
+	}
 
-			// If the time left at i is less than or equal to zero, increment procsCompleted
-			if timeLeft[i] <= 0 {
-				procsCompleted++
+	processes := make([]Process, len(rows))
+	for i := range rows {
+		if len(rows[i]) < 3 {
+			return nil, fmt.Errorf("%w: row %d must be id,burst,arrival[,priority]", ErrParse, i+1)
+		}
+		if processes[i].ProcessID, err = parseInt(rows[i][0]); err != nil {
+			return nil, fmt.Errorf("%w: row %d", err, i+1)
+		}
+		if err := checkPID(processes[i].ProcessID); err != nil {
+			return nil, fmt.Errorf("%w: row %d", err, i+1)
+		}
+		times := []*int64{&processes[i].BurstDuration, &processes[i].ArrivalTime}
+		for j, field := range times {
+			if *field, err = parseTime(rows[i][j+1], unit); err != nil {
+				return nil, fmt.Errorf("%w: row %d", err, i+1)
+			}
+		}
+		if processes[i].BurstDuration == 0 && unit != 1 {
+			if v, _ := strconv.ParseFloat(rows[i][1], 64); v > 0 {
+				processes[i].BurstDuration = 1
+			}
+		}
+		for j := 3; j < len(rows[i]); j++ {
+			if j == 3 && !strings.Contains(rows[i][j], "=") {
+				if processes[i].Priority, err = parseInt(rows[i][3]); err != nil {
+					return nil, fmt.Errorf("%w: row %d", err, i+1)
+				}
+				continue
+			}
+			if err := parseAttribute(&processes[i], rows[i][j], unit); err != nil {
+				return nil, fmt.Errorf("%w: process %d", err, processes[i].ProcessID)
 			}
-
 		}
-
 	}
-
-	// Get the sum of the wait times
-	for i := range waitTimes {
-		sumWaitTimes += float64(waitTimes[i])
+	if err := checkUniquePIDs(processes); err != nil {
+		return nil, err
+	}
+	if err := checkSpawns(processes); err != nil {
+		return nil, err
+	}
+	if err := checkSignals(processes); err != nil {
+		return nil, err
+	}
+	for i := range processes {
+		if processes[i].Tolerance != nil && processes[i].Deadline == 0 {
+			return nil, fmt.Errorf("%w: process %d has a tolerance but no deadline", ErrInvalidAttribute, processes[i].ProcessID)
+		}
+		if processes[i].Jobs > 0 && processes[i].Period == 0 {
+			return nil, fmt.Errorf("%w: process %d has jobs but no period", ErrInvalidAttribute, processes[i].ProcessID)
+		}
+		if processes[i].Period > 0 && processes[i].Spawn != nil {
+			return nil, fmt.Errorf("%w: spawned process %d cannot be periodic", ErrInvalidAttribute, processes[i].ProcessID)
+		}
 	}
 
-	aveWait := sumWaitTimes / float64(len(processes))
-	aveTurnaround := totalTurnaround / countTimeUnits
-	aveThroughput := countTimeUnits / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return processes, nil
 }
 
-//endregion
-
-//region Output helpers
+// checkSpawns makes sure every child names an existing parent, spawns within
+// the parent's burst and does not end up being its own ancestor.
+func checkSpawns(processes []Process) error {
+	byID := make(map[int64]*Process, len(processes))
+	for i := range processes {
+		byID[processes[i].ProcessID] = &processes[i]
+	}
+	for i := range processes {
+		s := processes[i].Spawn
+		if s == nil {
+			continue
+		}
+		parent, ok := byID[s.Parent]
+		if !ok {
+			return fmt.Errorf("%w: process %d: unknown parent %d", ErrInvalidAttribute, processes[i].ProcessID, s.Parent)
+		}
+		if s.Offset < 0 || s.Offset > parent.BurstDuration {
+			return fmt.Errorf("%w: process %d: spawn %d is outside the burst of parent %d",
+				ErrInvalidAttribute, processes[i].ProcessID, s.Offset, s.Parent)
+		}
+	}
+	for i := range processes {
+		for p, steps := &processes[i], 0; p.Spawn != nil; p, steps = byID[p.Spawn.Parent], steps+1 {
+			if steps > len(processes) {
+				return fmt.Errorf("%w: process %d is its own ancestor", ErrInvalidAttribute, processes[i].ProcessID)
+			}
+		}
+	}
 
-func outputTitle(w io.Writer, title string) {
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
-	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	return nil
 }
 
-func outputGantt(w io.Writer, gantt []TimeSlice) {
-	_, _ = fmt.Fprintln(w, "Gantt schedule")
-	_, _ = fmt.Fprint(w, "|")
-	for i := range gantt {
-		pid := fmt.Sprint(gantt[i].PID)
-		padding := strings.Repeat(" ", (8-len(pid))/2)
-		_, _ = fmt.Fprint(w, padding, pid, padding, "|")
+// checkSignals makes sure every signal is sent to an existing process.
+func checkSignals(processes []Process) error {
+	ids := make(map[int64]bool, len(processes))
+	for i := range processes {
+		ids[processes[i].ProcessID] = true
 	}
-	_, _ = fmt.Fprintln(w)
-	for i := range gantt {
-		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
-		if len(gantt)-1 == i {
-			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
+	for i := range processes {
+		for _, s := range processes[i].Signals {
+			if !ids[s.PID] {
+				return fmt.Errorf("%w: process %d signals unknown process %d", ErrInvalidAttribute, processes[i].ProcessID, s.PID)
+			}
 		}
 	}
-	_, _ = fmt.Fprintf(w, "\n\n")
+
+	return nil
 }
 
-func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
-	_, _ = fmt.Fprintln(w, "Schedule table")
-	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
-	table.AppendBulk(rows)
-	table.SetFooter([]string{"", "", "", "",
-		fmt.Sprintf("Average\n%.2f", wait),
-		fmt.Sprintf("Average\n%.2f", turnaround),
-		fmt.Sprintf("Throughput\n%.2f/t", throughput)})
-	table.Render()
+// parseAttribute applies one of the optional key=value columns that may follow
+// the ID, burst, arrival and priority columns, e.g. np=3-6 for a
+// non-preemptible region covering units 3 to 6 of the burst. Times are in
+// input units, like the burst and arrival (see parseTime).
+func parseAttribute(p *Process, field string, unit float64) error {
+	key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+	if !ok {
+		return fmt.Errorf("%w: %q is not key=value", ErrInvalidAttribute, field)
+	}
+	switch key {
+	case "yield":
+		for _, part := range strings.Split(value, ";") {
+			y, err := parseTime(part, unit)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+			}
+			if y <= 0 || y >= p.BurstDuration {
+				return fmt.Errorf("%w: yield %d is outside the burst of %d", ErrInvalidAttribute, y, p.BurstDuration)
+			}
+			p.Yields = append(p.Yields, y)
+		}
+	case "bursts":
+		var sum int64
+		for _, part := range strings.Split(value, ";") {
+			b, err := parseTime(part, unit)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+			}
+			if b <= 0 {
+				return fmt.Errorf("%w: burst %d is not positive", ErrInvalidAttribute, b)
+			}
+			p.Bursts = append(p.Bursts, b)
+			sum += b
+		}
+		if p.BurstDuration == 0 {
+			p.BurstDuration = sum
+		}
+		if sum != p.BurstDuration {
+			return fmt.Errorf("%w: bursts add up to %d, not the burst of %d", ErrInvalidAttribute, sum, p.BurstDuration)
+		}
+	case "parent", "spawn":
+		parse := parseInt
+		if key == "spawn" {
+			parse = func(s string) (int64, error) { return parseTime(s, unit) }
+		}
+		n, err := parse(value)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if p.Spawn == nil {
+			p.Spawn = &Spawn{}
+		}
+		if key == "parent" {
+			p.Spawn.Parent = n
+		} else {
+			p.Spawn.Offset = n
+		}
+	case "wait":
+		n, err := parseTime(value, unit)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if n <= 0 || n > p.BurstDuration {
+			return fmt.Errorf("%w: wait %d is outside the burst of %d", ErrInvalidAttribute, n, p.BurstDuration)
+		}
+		p.WaitOffset = n
+	case "await":
+		for _, part := range strings.Split(value, ";") {
+			a, err := parseTime(part, unit)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+			}
+			if a <= 0 || a > p.BurstDuration {
+				return fmt.Errorf("%w: await %d is outside the burst of %d", ErrInvalidAttribute, a, p.BurstDuration)
+			}
+			p.Awaits = append(p.Awaits, a)
+		}
+	case "signal":
+		for _, part := range strings.Split(value, ";") {
+			pid, offset, hasOffset := strings.Cut(part, "@")
+			s := Signal{Offset: p.BurstDuration}
+			var err error
+			if s.PID, err = parseInt(pid); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+			}
+			if hasOffset {
+				if s.Offset, err = parseTime(offset, unit); err != nil {
+					return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+				}
+			}
+			if s.Offset <= 0 || s.Offset > p.BurstDuration {
+				return fmt.Errorf("%w: signal at %d is outside the burst of %d", ErrInvalidAttribute, s.Offset, p.BurstDuration)
+			}
+			p.Signals = append(p.Signals, s)
+		}
+	case "window":
+		from, to, ok := strings.Cut(value, "-")
+		if !ok {
+			return fmt.Errorf("%w: window %q is not a from-to range", ErrInvalidAttribute, value)
+		}
+		var w Span
+		var err error
+		if w.From, err = parseTime(from, unit); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if w.To, err = parseTime(to, unit); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if w.From < 0 || w.To < 0 || w.From == w.To {
+			return fmt.Errorf("%w: window %q is empty", ErrInvalidAttribute, value)
+		}
+		p.Window = &w
+	case "np":
+		spans, err := parseSpans(value, p.BurstDuration, unit)
+		if err != nil {
+			return err
+		}
+		p.NonPreemptible = append(p.NonPreemptible, spans...)
+	case "sys":
+		spans, err := parseSpans(value, p.BurstDuration, unit)
+		if err != nil {
+			return err
+		}
+		p.System = append(p.System, spans...)
+	case "deadline", "tolerance":
+		n, err := parseTime(value, unit)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if key == "deadline" {
+			if n <= 0 {
+				return fmt.Errorf("%w: deadline %d is not positive", ErrInvalidAttribute, n)
+			}
+			p.Deadline = n
+			break
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: tolerance %d is negative", ErrInvalidAttribute, n)
+		}
+		p.Tolerance = &n
+	case "warp", "warp-limit":
+		n, err := parseTime(value, unit)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: %s %d is negative", ErrInvalidAttribute, key, n)
+		}
+		if key == "warp" {
+			p.Warp = n
+		} else {
+			p.WarpLimit = n
+		}
+	case "period", "jobs":
+		parse := parseInt
+		if key == "period" {
+			parse = func(s string) (int64, error) { return parseTime(s, unit) }
+		}
+		n, err := parse(value)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if n <= 0 {
+			return fmt.Errorf("%w: %s %d is not positive", ErrInvalidAttribute, key, n)
+		}
+		if key == "period" {
+			p.Period = n
+		} else {
+			p.Jobs = n
+		}
+	case "tickets":
+		n, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if n <= 0 {
+			return fmt.Errorf("%w: tickets %d is not positive", ErrInvalidAttribute, n)
+		}
+		p.Tickets = n
+	case "background":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		p.Background = b
+	case "task", "class", "group":
+		if value == "" {
+			return fmt.Errorf("%w: empty %s name", ErrInvalidAttribute, key)
+		}
+		switch key {
+		case "task":
+			p.Task = value
+		case "class":
+			p.Class = value
+		default:
+			p.Group = value
+		}
+	default:
+		return fmt.Errorf("%w: unknown attribute %q", ErrInvalidAttribute, key)
+	}
+
+	return nil
 }
 
-//endregion
+// parseSpans parses semicolon separated from-to ranges, in input units,
+// lying within a burst.
+func parseSpans(s string, burst int64, unit float64) ([]Span, error) {
+	var spans []Span
+	for _, part := range strings.Split(s, ";") {
+		from, to, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a from-to range", ErrInvalidAttribute, part)
+		}
+		var span Span
+		var err error
+		if span.From, err = parseTime(from, unit); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if span.To, err = parseTime(to, unit); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAttribute, err)
+		}
+		if span.From < 0 || span.From >= span.To || span.To > burst {
+			return nil, fmt.Errorf("%w: range %q is outside the burst of %d", ErrInvalidAttribute, part, burst)
+		}
+		spans = append(spans, span)
+	}
 
-//region Loading processes.
+	return spans, nil
+}
 
-var ErrInvalidArgs = errors.New("invalid args")
+// loadEvents reads an events file with one time,kind,pid row per event.
+func loadEvents(r io.Reader) ([]Event, error) {
+	return loadScaledEvents(r, 1)
+}
 
-func loadProcesses(r io.Reader) ([]Process, error) {
+// loadScaledEvents reads an events file whose times are in input units, like
+// those of the workload (see parseTime).
+func loadScaledEvents(r io.Reader, unit float64) ([]Event, error) {
 	rows, err := csv.NewReader(r).ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("%w: reading CSV", err)
+		return nil, fmt.Errorf("%w: reading CSV: %w", ErrParse, err)
 	}
 
-	processes := make([]Process, len(rows))
+	events := make([]Event, len(rows))
 	for i := range rows {
-		processes[i].ProcessID = mustStrToInt(rows[i][0])
-		processes[i].BurstDuration = mustStrToInt(rows[i][1])
-		processes[i].ArrivalTime = mustStrToInt(rows[i][2])
-		if len(rows[i]) == 4 {
-			processes[i].Priority = mustStrToInt(rows[i][3])
+		if len(rows[i]) != 3 {
+			return nil, fmt.Errorf("%w: row %d must be time,kind,pid", ErrInvalidEvent, i+1)
+		}
+		if events[i].Time, err = parseTime(rows[i][0], unit); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+		}
+		events[i].Kind = strings.TrimSpace(rows[i][1])
+		if events[i].Kind != eventKill && events[i].Kind != eventSignal {
+			return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidEvent, events[i].Kind)
+		}
+		if events[i].PID, err = parseInt(rows[i][2]); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
 		}
 	}
 
-	return processes, nil
+	return events, nil
+}
+
+// checkEvents makes sure every event is for a process of the workload, so a
+// mistyped ID fails the run rather than being silently ignored.
+func checkEvents(events []Event, processes []Process) error {
+	ids := make(map[int64]bool, len(processes))
+	for i := range processes {
+		ids[processes[i].ProcessID] = true
+	}
+	for i, ev := range events {
+		if !ids[ev.PID] {
+			return fmt.Errorf("%w: row %d: %s of unknown process %d", ErrInvalidEvent, i+1, ev.Kind, ev.PID)
+		}
+	}
+
+	return nil
 }
 
-func mustStrToInt(s string) int64 {
+func loadEventsFile(name string, unit float64) ([]Event, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening events file", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return loadScaledEvents(f, unit)
+}
+
+// parseInt parses a whole number, reporting a malformed one as ErrParse.
+func parseInt(s string) (int64, error) {
 	i, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return 0, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+
+	return i, nil
+}
+
+// parseTime parses a time in input units, unit of which make one simulation
+// unit, rounding it to whole simulation units. With the default unit of 1
+// times must be whole numbers. Times are never negative, and must fit in an
+// int64 once scaled.
+func parseTime(s string, unit float64) (int64, error) {
+	if unit == 1 {
+		t, err := parseInt(s)
+		if err != nil {
+			return 0, err
+		}
+		if t < 0 {
+			return 0, fmt.Errorf("%w: time %q is negative", ErrInvalidWorkload, s)
+		}
+
+		return t, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("%w: time %q is negative", ErrInvalidWorkload, s)
+	}
+	// 1<<63 is the first float64 past the int64 range
+	if v = math.Round(v / unit); !(v < 1<<63) {
+		return 0, fmt.Errorf("%w: time %q is out of range", ErrParse, s)
 	}
 
-	return i
+	return int64(v), nil
 }
 
 //endregion
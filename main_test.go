@@ -2,12 +2,16 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"math"
 	"os"
 	"path"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"testing/iotest"
 )
@@ -110,6 +114,173 @@ func Test_loadProcesses(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "non-preemptible regions",
+			args: args{
+				r: strings.NewReader(`1,10,0,2,np=3-6;8-10
+2,9,3,np=0-2`),
+			},
+			want: []Process{
+				{
+					ProcessID:      1,
+					ArrivalTime:    0,
+					BurstDuration:  10,
+					Priority:       2,
+					NonPreemptible: []Span{{From: 3, To: 6}, {From: 8, To: 10}},
+				},
+				{
+					ProcessID:      2,
+					ArrivalTime:    3,
+					BurstDuration:  9,
+					NonPreemptible: []Span{{From: 0, To: 2}},
+				},
+			},
+		},
+		{
+			name: "yield points",
+			args: args{
+				r: strings.NewReader(`1,10,0,2,yield=2;7`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 10,
+					Priority:      2,
+					Yields:        []int64{2, 7},
+				},
+			},
+		},
+		{
+			name: "replayed bursts",
+			args: args{
+				r: strings.NewReader(`1,0,0,2,bursts=3;5;2`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 10,
+					Priority:      2,
+					Bursts:        []int64{3, 5, 2},
+				},
+			},
+		},
+		{
+			name: "spawned child",
+			args: args{
+				r: strings.NewReader(`1,10,0,2
+2,4,0,1,parent=1,spawn=3`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 10,
+					Priority:      2,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   0,
+					BurstDuration: 4,
+					Priority:      1,
+					Spawn:         &Spawn{Parent: 1, Offset: 3},
+				},
+			},
+		},
+		{
+			name: "producer and consumer",
+			args: args{
+				r: strings.NewReader(`1,6,0,1,signal=2@3;2
+2,8,0,2,await=2;5`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 6,
+					Priority:      1,
+					Signals:       []Signal{{PID: 2, Offset: 3}, {PID: 2, Offset: 6}},
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   0,
+					BurstDuration: 8,
+					Priority:      2,
+					Awaits:        []int64{2, 5},
+				},
+			},
+		},
+		{
+			name: "unknown parent",
+			args: args{
+				r: strings.NewReader(`2,4,0,1,parent=1,spawn=3`),
+			},
+			wantErr: ErrInvalidAttribute,
+		},
+		{
+			name: "bursts not adding up",
+			args: args{
+				r: strings.NewReader(`1,9,0,2,bursts=3;5;2`),
+			},
+			wantErr: ErrInvalidAttribute,
+		},
+		{
+			name: "region outside burst",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,np=3-6`),
+			},
+			wantErr: ErrInvalidAttribute,
+		},
+		{
+			name: "invalid attributes are invalid workloads",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,np=3-6`),
+			},
+			wantErr: ErrInvalidWorkload,
+		},
+		{
+			name: "negative burst",
+			args: args{
+				r: strings.NewReader(`1,-5,0`),
+			},
+			wantErr: ErrInvalidWorkload,
+		},
+		{
+			name: "duplicate ID",
+			args: args{
+				r: strings.NewReader("1,5,0\n2,3,1\n1,4,2"),
+			},
+			wantErr: ErrInvalidWorkload,
+		},
+		{
+			name: "negative arrival",
+			args: args{
+				r: strings.NewReader(`1,5,-2`),
+			},
+			wantErr: ErrInvalidWorkload,
+		},
+		{
+			name: "malformed number",
+			args: args{
+				r: strings.NewReader(`1,five,0,2`),
+			},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "too few columns",
+			args: args{
+				r: strings.NewReader(`1,5`),
+			},
+			wantErr: ErrParse,
+		},
+		{
+			name: "malformed attribute",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,yield=x`),
+			},
+			wantErr: ErrParse,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -205,3 +376,471 @@ func Test_openProcessingFile1(t *testing.T) {
 		})
 	}
 }
+
+func Test_latencies(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6},
+	}
+	gantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 5},
+		{PID: 2, Start: 5, Stop: 10},
+		{PID: 3, Start: 10, Stop: 16},
+		{PID: 2, Start: 16, Stop: 20},
+	}
+	want := latency{
+		response:   []int64{0, 2, 4},
+		turnaround: []int64{5, 10, 17},
+	}
+	got := latencies(processes, gantt)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("latencies() = %v, want %v", got, want)
+	}
+	if p := percentile(got.turnaround, 50); p != 10 {
+		t.Errorf("percentile(50) = %v, want %v", p, 10)
+	}
+	if p := percentile(got.turnaround, 99); p != 17 {
+		t.Errorf("percentile(99) = %v, want %v", p, 17)
+	}
+}
+
+func Test_fairness(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2},
+		{ProcessID: 2, BurstDuration: 2},
+	}
+	tests := []struct {
+		name  string
+		gantt []TimeSlice
+		want  float64
+	}{
+		{"one slowed down", []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}}, 0.9},
+		{"both slowed down alike", []TimeSlice{
+			{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2},
+			{PID: 1, Start: 2, Stop: 3}, {PID: 2, Start: 3, Stop: 4},
+		}, 0.98},
+		{"nothing ran", nil, 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := fairness(Result{processes: processes, Gantt: tt.gantt}); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("fairness() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_finalStops(t *testing.T) {
+	t.Parallel()
+	// P3 ends its burst waiting for its child P4, and P5 is killed while
+	// waiting after it has run
+	processes, err := loadProcesses(strings.NewReader(
+		"1,10,0,2,yield=2;8,await=4\n" +
+			"2,6,1,1,signal=1@3\n" +
+			"3,8,2,3,wait=8\n" +
+			"4,12,3,0,parent=3,spawn=2\n" +
+			"5,9,4,4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := options{events: []Event{{Time: 30, Kind: eventKill, PID: 5}}}
+	for _, alg := range algorithms {
+		alg := alg
+		t.Run(alg.key, func(t *testing.T) {
+			t.Parallel()
+			r := alg.run(append([]Process(nil), processes...), opts)
+			last := finalStops(r.Gantt)
+			for i, row := range r.Schedule {
+				exit, err := strconv.ParseInt(row[6], 10, 64)
+				if err != nil {
+					// Killed, dropped or blocked for good
+					continue
+				}
+				p := r.processes[i]
+				switch stop, ran := last[p.ProcessID]; {
+				case !ran:
+					t.Errorf("P%d exited at %d without running", p.ProcessID, exit)
+				case p.WaitOffset == p.BurstDuration:
+					if exit < stop {
+						t.Errorf("P%d exited at %d before its final slice stopped at %d", p.ProcessID, exit, stop)
+					}
+				case exit != stop:
+					t.Errorf("P%d exited at %d, but its final slice stopped at %d", p.ProcessID, exit, stop)
+				}
+			}
+		})
+	}
+}
+
+func Test_rrMinGranularity(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 8},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 8},
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 8},
+		{PID: 2, Start: 8, Stop: 16},
+	}
+	got := rr(append([]Process(nil), processes...), options{minGranularity: 8}).Gantt
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rr() gantt = %v, want %v", got, want)
+	}
+	if n := switches(got); n != 1 {
+		t.Errorf("switches() = %v, want %v", n, 1)
+	}
+	if n := switches(rr(append([]Process(nil), processes...), options{}).Gantt); n != 3 {
+		t.Errorf("switches() without granularity = %v, want %v", n, 3)
+	}
+}
+
+func Test_outputGranularityKeepsOptions(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 10},
+	}
+	// The baseline run without granularity still kills P1
+	opts := options{minGranularity: 10, events: []Event{{Time: 4, Kind: eventKill, PID: 1}}}
+	var out bytes.Buffer
+	outputGranularity(&out, processes, opts, display{})
+	var row []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.Contains(line, "Round-robin") {
+			row = strings.Fields(strings.ReplaceAll(line, "|", " "))
+		}
+	}
+	if want := []string{"Round-robin", "1", "1", "2.00", "2.00", "14", "14"}; !reflect.DeepEqual(row, want) {
+		t.Errorf("outputGranularity() RR row = %v, want %v\n%s", row, want, out.String())
+	}
+}
+
+func Test_rrNonPreemptible(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 8, NonPreemptible: []Span{{From: 4, To: 7}}},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3},
+	}
+	got := rr(processes, options{})
+	var runs []int64
+	for _, slice := range got.Gantt {
+		runs = append(runs, slice.Stop-slice.Start)
+	}
+	if want := []int64{7, 3, 1}; !reflect.DeepEqual(runs, want) {
+		t.Errorf("rr() slice lengths = %v, want %v", runs, want)
+	}
+	if got.DeferredWait != 2 {
+		t.Errorf("rr() deferred wait = %v, want %v", got.DeferredWait, 2)
+	}
+}
+
+func Test_nonPreemptibleWithoutPreemption(t *testing.T) {
+	t.Parallel()
+	// P1 is shorter and of higher priority, so neither scheduler would ever
+	// preempt it, region or not
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 1, NonPreemptible: []Span{{From: 0, To: 9}}},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 100, Priority: 2},
+	}
+	tests := []struct {
+		name string
+		run  func([]Process, options) Result
+	}{
+		{"srpt", srpt},
+		{"ppriority", priorityPreemptive},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.run(append([]Process(nil), processes...), options{}); got.DeferredWait != 0 {
+				t.Errorf("%s() deferred wait = %v, want %v", tt.name, got.DeferredWait, 0)
+			}
+		})
+	}
+}
+
+func Test_rrYields(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 8, Yields: []int64{2}},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3},
+	}
+	got := rr(processes, options{})
+	var runs []int64
+	for _, slice := range got.Gantt {
+		runs = append(runs, slice.Stop-slice.Start)
+	}
+	if want := []int64{2, 3, 6}; !reflect.DeepEqual(runs, want) {
+		t.Errorf("rr() slice lengths = %v, want %v", runs, want)
+	}
+	if got.Yields != 1 {
+		t.Errorf("rr() yields = %v, want %v", got.Yields, 1)
+	}
+}
+
+func Test_outputMaxRows(t *testing.T) {
+	t.Parallel()
+	var processes []Process
+	for i := int64(1); i <= 6; i++ {
+		processes = append(processes, Process{ProcessID: i, ArrivalTime: 0, BurstDuration: 1})
+	}
+	r := fcfs(processes, options{})
+	tests := []struct {
+		name    string
+		maxRows int
+		want    []string
+		notWant []string
+	}{
+		{"unlimited", 0, []string{"|   6   |"}, []string{"more"}},
+		{"fits", 6, []string{"|   6   |"}, []string{"more"}},
+		{"truncated", 3, []string{"|   1   |   2   | ...3 more... |   6   |", "0\t1\t...\t5\t6", "3 more"}, []string{"|   3   |", "|   4   |"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			outputResult(&b, "FCFS", r, display{maxRows: tt.maxRows})
+			for _, s := range tt.want {
+				if !strings.Contains(b.String(), s) {
+					t.Errorf("outputResult() missing %q in\n%s", s, b.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(b.String(), s) {
+					t.Errorf("outputResult() contains %q in\n%s", s, b.String())
+				}
+			}
+		})
+	}
+}
+
+func Test_asciiWriter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"ascii unchanged", []string{"| 1 |\t0\t5\n"}, "| 1 |\t0\t5\n"},
+		{"look-alikes", []string{"a – b … “c”"}, "a - b ... \"c\""},
+		{"unknown", []string{"π=3"}, "?=3"},
+		{"split character", []string{"x\xe2\x80", "\xa6y"}, "x...y"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			w := &asciiWriter{w: &b}
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+					t.Fatalf("Write() = %v, %v, want %v, nil", n, err, len(s))
+				}
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("asciiWriter wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_schedulersConcurrent(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 3, ArrivalTime: 4, BurstDuration: 6, Priority: 1},
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 9, Priority: 3, Yields: []int64{4}},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 3, Priority: 2},
+		{ProcessID: 4, ArrivalTime: 1, BurstDuration: 1, Spawn: &Spawn{Parent: 1, Offset: 2}},
+	}
+	original := append([]Process(nil), processes...)
+	want := make(map[string]Result)
+	for _, alg := range algorithms {
+		want[alg.key] = alg.run(processes, options{})
+	}
+
+	// Every goroutine shares the same processes; run with -race to check
+	var wg sync.WaitGroup
+	results := make([]Result, 8*len(algorithms))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = algorithms[i%len(algorithms)].run(processes, options{})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		key := algorithms[i%len(algorithms)].key
+		if !reflect.DeepEqual(got.Gantt, want[key].Gantt) || !reflect.DeepEqual(got.Schedule, want[key].Schedule) {
+			t.Errorf("%s run %d = %v, want %v", key, i, got.Gantt, want[key].Gantt)
+		}
+	}
+	if !reflect.DeepEqual(processes, original) {
+		t.Errorf("schedulers modified the caller's processes: %v, want %v", processes, original)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 4, Awaits: []int64{2}},
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		key       string
+		processes []Process
+		wantErr   error
+		wantGantt bool
+	}{
+		{"completes", context.Background(), "fcfs", processes[:1], nil, true},
+		{"unknown algorithm", context.Background(), "unknown", processes, ErrInvalidArgs, false},
+		{"blocked for good", context.Background(), "rr", processes, ErrUnschedulable, true},
+		{"canceled", canceled, "sjf", processes, context.Canceled, false},
+		{"timeout", canceled, "sjf", processes, ErrTimeout, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Schedule(tt.ctx, tt.key, tt.processes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Schedule() error = %v, want %v", err, tt.wantErr)
+			}
+			if (len(got.Gantt) > 0) != tt.wantGantt {
+				t.Errorf("Schedule() gantt = %v, want one: %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func Test_loadScaledProcesses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		unit    float64
+		want    []Process
+		wantErr error
+	}{
+		{"whole units", "1,5,2\n", 1, []Process{{ProcessID: 1, BurstDuration: 5, ArrivalTime: 2}}, nil},
+		{"fractional in whole units", "1,2.5,0\n", 1, nil, ErrParse},
+		{"milliseconds", "1,0.0125,1.5\n", 0.001, []Process{{ProcessID: 1, BurstDuration: 13, ArrivalTime: 1500}}, nil},
+		{"tiny burst", "1,0.0001,0\n", 0.001, []Process{{ProcessID: 1, BurstDuration: 1}}, nil},
+		{
+			"scaled attributes",
+			"1,0.01,0,1,yield=0.004,np=0.002-0.006,deadline=0.02,period=0.05,jobs=2,window=0.1-0.2\n" +
+				"2,0.004,0,1,parent=1,spawn=0.003,signal=1@0.002\n",
+			0.001,
+			[]Process{
+				{
+					ProcessID: 1, BurstDuration: 10, Priority: 1, Yields: []int64{4},
+					NonPreemptible: []Span{{From: 2, To: 6}}, Deadline: 20, Period: 50, Jobs: 2,
+					Window: &Span{From: 100, To: 200},
+				},
+				{
+					ProcessID: 2, BurstDuration: 4, Priority: 1, Spawn: &Spawn{Parent: 1, Offset: 3},
+					Signals: []Signal{{PID: 1, Offset: 2}},
+				},
+			},
+			nil,
+		},
+		{"negative scaled arrival", "1,0.5,-0.0001\n", 0.001, nil, ErrInvalidWorkload},
+		{"negative scaled attribute", "1,0.5,0,1,deadline=-1\n", 0.001, nil, ErrInvalidAttribute},
+		{"overflow", "1,1e16,0\n", 0.001, nil, ErrParse},
+		{"not a number", "1,NaN,0\n", 0.001, nil, ErrParse},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := loadScaledProcesses(strings.NewReader(tt.input), tt.unit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadScaledProcesses() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadScaledProcesses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadScaledEvents(t *testing.T) {
+	t.Parallel()
+	got, err := loadScaledEvents(strings.NewReader("0.003,kill,1\n"), 0.001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Event{{Time: 3, Kind: eventKill, PID: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("loadScaledEvents() = %v, want %v", got, want)
+	}
+	if _, err := loadScaledEvents(strings.NewReader("-1,kill,1\n"), 0.001); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("negative time error = %v, want %v", err, ErrInvalidEvent)
+	}
+}
+
+func Test_checkEvents(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 3}}
+	events, err := loadEvents(strings.NewReader("1,kill,2\n2,signal,12\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = checkEvents(events, processes)
+	if !errors.Is(err, ErrInvalidEvent) || !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("checkEvents() error = %v, want %v", err, ErrInvalidEvent)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "row 2") || !strings.Contains(msg, "process 12") {
+		t.Errorf("checkEvents() error = %q, want it to name row 2 and process 12", msg)
+	}
+	if err := checkEvents(events[:1], processes); err != nil {
+		t.Errorf("checkEvents() error = %v, want nil", err)
+	}
+}
+
+func Test_parseThroughputUnit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		unit    string
+		want    string
+		wantErr error
+	}{
+		{"per unit", "t", "0.29/t", nil},
+		{"per 100 units", "100t", "28.57/100t", nil},
+		{"per second", "1ms", "285.71/s", nil},
+		{"unknown", "fortnight", "", ErrInvalidArgs},
+		{"zero duration", "0s", "", ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				d   display
+				err error
+			)
+			d.throughputScale, d.throughputSuffix, err = parseThroughputUnit(tt.unit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseThroughputUnit() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := d.throughput(2.0 / 7); got != tt.want {
+				t.Errorf("display.throughput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
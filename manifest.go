@@ -0,0 +1,106 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+//region Run manifest
+
+type (
+	// manifest records how a run was produced, so any output can be traced
+	// back to the exact tool version, inputs and options behind it.
+	manifest struct {
+		Version     string            `json:"version"`
+		GoVersion   string            `json:"go_version"`
+		CommandLine []string          `json:"command_line"`
+		Seed        *int64            `json:"seed,omitempty"`
+		Inputs      []manifestInput   `json:"inputs"`
+		Options     map[string]string `json:"options"`
+	}
+	manifestInput struct {
+		Path   string `json:"path"`
+		SHA256 string `json:"sha256"`
+	}
+)
+
+// newManifest describes a run from its command line, the value of every
+// flag in fs, defaults included, and the files it read.
+func newManifest(fs *flag.FlagSet, commandLine []string, inputs ...string) (manifest, error) {
+	m := manifest{
+		Version:     toolVersion(),
+		GoVersion:   runtime.Version(),
+		CommandLine: commandLine,
+		Inputs:      []manifestInput{},
+		Options:     map[string]string{},
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		m.Options[f.Name] = f.Value.String()
+	})
+	for _, name := range inputs {
+		sum, err := hashFile(name)
+		if err != nil {
+			return manifest{}, err
+		}
+		m.Inputs = append(m.Inputs, manifestInput{Path: name, SHA256: sum})
+	}
+
+	return m, nil
+}
+
+// toolVersion is the module version the binary was built from, with the VCS
+// revision when it was built from a checkout.
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	version := info.Main.Version
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			version += " " + s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				version += " (modified)"
+			}
+		}
+	}
+
+	return version
+}
+
+func hashFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: error hashing input", err)
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("%w: error hashing input", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeManifestFile(name string, m manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(name, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("%w: error writing manifest", err)
+	}
+
+	return nil
+}
+
+//endregion
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_newManifest(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	content := []byte("1,5,0,2\n")
+	if err := os.WriteFile(input, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("tail", false, "")
+	fs.Int64("day", 2400, "")
+	if err := fs.Parse([]string{"-tail", input}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := newManifest(fs, []string{"scheduler", "-tail", input}, input)
+	if err != nil {
+		t.Fatalf("newManifest() error = %v", err)
+	}
+	if want := map[string]string{"tail": "true", "day": "2400"}; !reflect.DeepEqual(m.Options, want) {
+		t.Errorf("newManifest() options = %v, want %v", m.Options, want)
+	}
+	if want := []manifestInput{{Path: input, SHA256: hex.EncodeToString(sum[:])}}; !reflect.DeepEqual(m.Inputs, want) {
+		t.Errorf("newManifest() inputs = %v, want %v", m.Inputs, want)
+	}
+
+	out := filepath.Join(dir, "manifest.json")
+	if err := writeManifestFile(out, m); err != nil {
+		t.Fatalf("writeManifestFile() error = %v", err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got manifest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("manifest round trip = %+v, want %+v", got, m)
+	}
+
+	if _, err := newManifest(fs, nil, filepath.Join(dir, "missing.csv")); err == nil {
+		t.Errorf("newManifest() with a missing input, want an error")
+	}
+}
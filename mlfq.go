@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Multilevel feedback queue
+
+type (
+	// mlfqConfig sets up the multilevel feedback queue.
+	mlfqConfig struct {
+		// quanta holds the quantum of every queue, the top one first.
+		quanta []int64
+		// boost is how much CPU time passes between moving every process
+		// back to the top queue, or 0 for never.
+		boost int64
+	}
+
+	// multilevelFeedback is a multilevel feedback queue scheduler following
+	// the rules in Operating Systems: Three Easy Pieces. It runs the highest
+	// non-empty queue round-robin, starts new processes in the top queue and
+	// demotes a process one queue once it has used up its quantum there, in
+	// one go or in parts between blocking, so it cannot game the scheduler.
+	// Every boost units of CPU time all processes go back to the top queue,
+	// so long ones do not starve and ones turning interactive move back up.
+	multilevelFeedback struct {
+		mlfqConfig
+		tasks map[int64]*mlfqTask
+		seq   int
+		// running is the task last dispatched, charged at the next pick.
+		running *task
+		// elapsed is the CPU time used so far, and lastBoost when the last
+		// boost happened.
+		elapsed, lastBoost int64
+		// levels holds the statistics of every queue.
+		levels []mlfqLevel
+		boosts int
+		// aging, if set, promotes processes that wait in lower queues.
+		aging *ager
+	}
+
+	mlfqTask struct {
+		level int
+		// allotment is what is left of the quantum at this level.
+		allotment int64
+		// ran and blocked are how long the process had run and been blocked
+		// when last seen, to charge what happened since.
+		ran, blocked int64
+		// seq orders processes of the same queue by when they joined it.
+		seq int
+		// deepest is the lowest queue the process has been in.
+		deepest int
+	}
+
+	// mlfqLevel is what happened at one queue of a run.
+	mlfqLevel struct {
+		// ran is the CPU time processes spent in the queue.
+		ran int64
+		// reached counts the processes that were ever in the queue, and
+		// demoted the demotions into it.
+		reached, demoted int
+	}
+)
+
+// defaultMLFQ is the multilevel feedback queue setup used unless the quanta
+// or boost are set: three queues with doubling quanta.
+var defaultMLFQ = mlfqConfig{quanta: []int64{2, 4, 8}, boost: 50}
+
+// parseQuanta parses comma-separated positive quanta, one per queue.
+func parseQuanta(s string) ([]int64, error) {
+	var quanta []int64
+	for _, part := range strings.Split(s, ",") {
+		q, err := parseInt(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%w: quanta %q: %w", ErrInvalidArgs, s, err)
+		}
+		if q <= 0 {
+			return nil, fmt.Errorf("%w: quantum %d is not positive", ErrInvalidArgs, q)
+		}
+		quanta = append(quanta, q)
+	}
+
+	return quanta, nil
+}
+
+func (p *multilevelFeedback) reset() {
+	p.tasks = make(map[int64]*mlfqTask)
+	p.seq = 0
+	p.running = nil
+	p.elapsed, p.lastBoost = 0, 0
+	p.levels = make([]mlfqLevel, len(p.quanta))
+	p.boosts = 0
+	if p.aging != nil {
+		p.aging.reset()
+	}
+}
+
+func (p *multilevelFeedback) setNow(now int64) {
+	if p.aging != nil {
+		p.aging.setNow(now)
+	}
+}
+
+// task returns the state of t, starting it in the top queue.
+func (p *multilevelFeedback) task(t *task) *mlfqTask {
+	s, ok := p.tasks[t.ProcessID]
+	if !ok {
+		p.seq++
+		s = &mlfqTask{allotment: p.quanta[0], ran: t.ran, blocked: t.blockedFor, seq: p.seq}
+		p.tasks[t.ProcessID] = s
+		p.levels[0].reached++
+	}
+
+	return s
+}
+
+// charge charges the task last dispatched for what it ran, demoting it
+// once its allotment is used up.
+func (p *multilevelFeedback) charge() {
+	if t := p.running; t != nil {
+		s := p.tasks[t.ProcessID]
+		if d := t.ran - s.ran; d > 0 {
+			s.ran = t.ran
+			p.elapsed += d
+			p.levels[s.level].ran += d
+			// A process that finished is not demoted
+			if s.allotment -= d; s.allotment <= 0 && t.remaining > 0 {
+				if s.level < len(p.quanta)-1 {
+					s.level++
+					p.levels[s.level].demoted++
+					if s.level > s.deepest {
+						s.deepest = s.level
+						p.levels[s.level].reached++
+					}
+				}
+				p.seq++
+				s.allotment, s.seq = p.quanta[s.level], p.seq
+			}
+		}
+		p.running = nil
+	}
+}
+
+// account charges the task last dispatched, sends tasks that woke to the
+// back of their queue, promotes tasks that aged and boosts every task when
+// it is time to.
+func (p *multilevelFeedback) account(ready []*task) {
+	p.charge()
+	for _, t := range ready {
+		s := p.task(t)
+		if t.blockedFor > s.blocked {
+			p.seq++
+			s.blocked, s.seq = t.blockedFor, p.seq
+		}
+		if p.aging == nil {
+			continue
+		}
+		if n := p.aging.boost(t); n > 0 {
+			p.aging.restart(t)
+			if s.level > 0 {
+				p.seq++
+				s.level = max(s.level-int(n), 0)
+				s.allotment, s.seq = p.quanta[s.level], p.seq
+			}
+		}
+	}
+	if p.boost > 0 && p.elapsed-p.lastBoost >= p.boost {
+		p.lastBoost = p.elapsed
+		p.boosts++
+		for _, s := range p.tasks {
+			s.level, s.allotment = 0, p.quanta[0]
+		}
+	}
+}
+
+func (p *multilevelFeedback) pick(ready []*task) int {
+	p.account(ready)
+	best := 0
+	for i, t := range ready {
+		s, b := p.tasks[t.ProcessID], p.tasks[ready[best].ProcessID]
+		if s.level < b.level || s.level == b.level && s.seq < b.seq {
+			best = i
+		}
+	}
+	p.running = ready[best]
+	if p.aging != nil {
+		p.aging.record(ready, best, func(t *task) int64 { return int64(p.tasks[t.ProcessID].level) })
+		p.aging.restart(ready[best])
+	}
+
+	return best
+}
+
+// quantum is a single unit, so that a process in a higher queue preempts the
+// running one as soon as it is ready; allotments are kept by account.
+func (*multilevelFeedback) quantum(*task) int64 { return 1 }
+
+// Multilevel feedback queue, with the setup in opts or the default one
+func mlfq(processes []Process, opts options) Result {
+	r, _ := mlfqLevels(processes, opts)
+
+	return r
+}
+
+// mlfqLevels runs the multilevel feedback queue and also returns the
+// scheduler, with the statistics of every queue.
+func mlfqLevels(processes []Process, opts options) (Result, *multilevelFeedback) {
+	config := defaultMLFQ
+	if opts.mlfq != nil {
+		config = *opts.mlfq
+	}
+	p := &multilevelFeedback{mlfqConfig: config}
+	if opts.aging != nil {
+		p.aging = newAger(*opts.aging)
+	}
+	r := simulate(processes, p, opts)
+	// Charge the last task dispatched
+	p.charge()
+
+	return r, p
+}
+
+// outputMLFQ shows, for every queue of the multilevel feedback queue, its
+// quantum, the CPU time spent in it and how many processes got there.
+func outputMLFQ(w io.Writer, processes []Process, opts options) {
+	_, p := mlfqLevels(append([]Process(nil), processes...), opts)
+
+	outputTitle(w, "Multilevel feedback queue")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Queue", "Quantum", "CPU Time", "CPU %", "Processes Reached", "Demotions In"})
+	for i, l := range p.levels {
+		percent := "-"
+		if p.elapsed > 0 {
+			percent = fmt.Sprintf("%.1f%%", 100*float64(l.ran)/float64(p.elapsed))
+		}
+		table.Append([]string{
+			fmt.Sprint(i),
+			fmt.Sprint(p.quanta[i]),
+			fmt.Sprint(l.ran),
+			percent,
+			fmt.Sprint(l.reached),
+			fmt.Sprint(l.demoted),
+		})
+	}
+	table.Render()
+	if p.boost > 0 {
+		_, _ = fmt.Fprintf(w, "Every %d units of CPU time all processes were boosted to queue 0: %d boosts.\n", p.boost, p.boosts)
+	}
+}
+
+//endregion
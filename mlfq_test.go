@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseQuanta(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    []int64
+		wantErr error
+	}{
+		{"queues", "2, 4,8", []int64{2, 4, 8}, nil},
+		{"not a number", "2,x", nil, ErrInvalidArgs},
+		{"zero", "2,0", nil, ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseQuanta(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseQuanta() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuanta() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mlfq(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		csv        string
+		config     mlfqConfig
+		want       []TimeSlice
+		wantLevels []mlfqLevel
+		wantBoosts int
+	}{
+		{
+			// P1 is demoted after 2 units, so P2 arriving in the top queue preempts it
+			name:       "demotion",
+			csv:        "1,10,0\n2,2,3\n",
+			config:     mlfqConfig{quanta: []int64{2, 4}},
+			want:       []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}, {PID: 1, Start: 5, Stop: 12}},
+			wantLevels: []mlfqLevel{{ran: 4, reached: 2}, {ran: 8, reached: 1, demoted: 1}},
+		},
+		{
+			// Boosts at 6 and 12 units of CPU time bring both back to the top
+			// queue, where they take turns again
+			name:       "boost",
+			csv:        "1,8,0\n2,8,0\n",
+			config:     mlfqConfig{quanta: []int64{1, 4}, boost: 6},
+			want:       []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2}, {PID: 1, Start: 2, Stop: 6}, {PID: 2, Start: 6, Stop: 7}, {PID: 1, Start: 7, Stop: 8}, {PID: 2, Start: 8, Stop: 12}, {PID: 1, Start: 12, Stop: 13}, {PID: 2, Start: 13, Stop: 14}, {PID: 1, Start: 14, Stop: 15}, {PID: 2, Start: 15, Stop: 16}},
+			wantBoosts: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, p := mlfqLevels(processes, options{mlfq: &tt.config})
+			if !reflect.DeepEqual(r.Gantt, tt.want) {
+				t.Errorf("mlfq() gantt = %v, want %v", r.Gantt, tt.want)
+			}
+			if tt.wantLevels != nil && !reflect.DeepEqual(p.levels, tt.wantLevels) {
+				t.Errorf("mlfq() levels = %+v, want %+v", p.levels, tt.wantLevels)
+			}
+			if p.boosts != tt.wantBoosts {
+				t.Errorf("mlfq() boosts = %d, want %d", p.boosts, tt.wantBoosts)
+			}
+		})
+	}
+}
+
+func Test_outputMLFQ(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,10,0\n2,2,3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outputMLFQ(&out, processes, options{mlfq: &mlfqConfig{quanta: []int64{2, 4}, boost: 20}})
+	for _, want := range []string{"|     1 |       4 |        8 | 66.7% |                 1 |            1 |", "0 boosts"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputMLFQ() missing %q:\n%s", want, out.String())
+		}
+	}
+}
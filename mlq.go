@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+//region Multilevel queue
+
+// mlqQueues are the queues of the multilevel queue scheduler, highest
+// priority first, with the round-robin time slice of each or 0 for first
+// come, first served.
+var mlqQueues = []struct {
+	class string
+	slice int64
+}{
+	{"system", 0},
+	{"interactive", foregroundSlice},
+	{"batch", 0},
+}
+
+type (
+	// multilevelQueue assigns every process for good to one of mlqQueues by
+	// its class: system, interactive or batch, with background processes of
+	// no such class batch and the rest interactive. Each queue has its own
+	// policy, round-robin for interactive processes and first come, first
+	// served for the others. Without shares, a queue only runs while every
+	// higher one is empty; with them, the queues with work split the CPU in
+	// proportion to their shares.
+	multilevelQueue struct {
+		shares []int64
+		tasks  map[int64]*mlqTask
+		seq    int
+		// used is the CPU time each queue received while another had work.
+		used []int64
+		// last is the task dispatched by the last pick, which had run lastRan
+		// then, and contended whether another queue had work.
+		last      *task
+		lastRan   int64
+		contended bool
+	}
+
+	mlqTask struct {
+		queue int
+		// slice is what is left of the time slice in a round-robin queue.
+		slice int64
+		// seq orders tasks by their turn in a round-robin queue, and by when
+		// they first became ready in the others.
+		seq int
+	}
+)
+
+// mlqQueue returns the queue p belongs to.
+func mlqQueue(p Process) int {
+	for i, q := range mlqQueues {
+		if strings.EqualFold(p.Class, q.class) {
+			return i
+		}
+	}
+	if p.Background {
+		return len(mlqQueues) - 1
+	}
+
+	return 1
+}
+
+// parseShares parses the comma-separated CPU shares of the multilevel
+// queues, one per queue from the highest.
+func parseShares(s string) ([]int64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != len(mlqQueues) {
+		return nil, fmt.Errorf("%w: shares %q must be one per queue, e.g. 60,30,10", ErrInvalidArgs, s)
+	}
+	shares := make([]int64, len(parts))
+	var total int64
+	for i, part := range parts {
+		v, err := parseInt(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%w: shares %q: %w", ErrInvalidArgs, s, err)
+		}
+		if v < 0 {
+			return nil, fmt.Errorf("%w: share %d is negative", ErrInvalidArgs, v)
+		}
+		shares[i] = v
+		total += v
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("%w: shares %q are all 0", ErrInvalidArgs, s)
+	}
+
+	return shares, nil
+}
+
+func (p *multilevelQueue) reset() {
+	*p = multilevelQueue{shares: p.shares, tasks: make(map[int64]*mlqTask), used: make([]int64, len(mlqQueues))}
+}
+
+// charge accounts for what the last task dispatched ran, ending its turn if
+// it is in a round-robin queue and its slice ran out.
+func (p *multilevelQueue) charge() {
+	t := p.last
+	if t == nil {
+		return
+	}
+	s := p.tasks[t.ProcessID]
+	d := t.ran - p.lastRan
+	if p.contended {
+		p.used[s.queue] += d
+	}
+	if slice := mlqQueues[s.queue].slice; slice > 0 {
+		if s.slice -= d; s.slice <= 0 {
+			s.slice = slice
+			p.seq++
+			s.seq = p.seq
+		}
+	}
+}
+
+func (p *multilevelQueue) pick(ready []*task) int {
+	p.charge()
+	first := make([]int, len(mlqQueues))
+	for q := range first {
+		first[q] = -1
+	}
+	for i, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		if !ok {
+			p.seq++
+			s = &mlqTask{queue: mlqQueue(t.Process), seq: p.seq}
+			s.slice = mlqQueues[s.queue].slice
+			p.tasks[t.ProcessID] = s
+		}
+		if f := first[s.queue]; f < 0 || s.seq < p.tasks[ready[f].ProcessID].seq {
+			first[s.queue] = i
+		}
+	}
+
+	// The highest queue with work runs, unless shares say another is owed
+	// more of the CPU
+	best, busy := -1, 0
+	for q, f := range first {
+		if f < 0 {
+			continue
+		}
+		busy++
+		if best < 0 {
+			best = q
+			continue
+		}
+		if p.shares != nil && p.used[q]*p.shares[best] < p.used[best]*p.shares[q] {
+			best = q
+		}
+	}
+	p.contended = busy > 1
+	i := first[best]
+	p.last, p.lastRan = ready[i], ready[i].ran
+
+	return i
+}
+
+// quantum is a single unit, so that the queues can share the CPU finely and
+// a higher queue preempts a lower one as soon as it has work.
+func (*multilevelQueue) quantum(*task) int64 { return 1 }
+
+// Multilevel queue, with strict priority between the queues or the CPU
+// shares in opts.mlqShares
+func mlq(processes []Process, opts options) Result {
+	return simulate(processes, &multilevelQueue{shares: opts.mlqShares}, opts)
+}
+
+//endregion
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseShares(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    []int64
+		wantErr error
+	}{
+		{"shares", "60, 30,10", []int64{60, 30, 10}, nil},
+		{"too few", "60,40", nil, ErrInvalidArgs},
+		{"negative", "60,-30,10", nil, ErrInvalidArgs},
+		{"all zero", "0,0,0", nil, ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseShares(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseShares() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseShares() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mlq(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		csv    string
+		shares []int64
+		want   []TimeSlice
+	}{
+		{
+			// The system process preempts the interactive one, and the batch
+			// one waits until both are done
+			name: "strict",
+			csv:  "1,6,0,0,class=batch\n2,4,1,0,class=system\n3,6,0\n",
+			want: []TimeSlice{{PID: 3, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 5}, {PID: 3, Start: 5, Stop: 10}, {PID: 1, Start: 10, Stop: 16}},
+		},
+		{
+			// Equal shares have the system and batch queues alternate
+			name:   "shares",
+			csv:    "1,3,0,0,class=system\n2,3,0,0,class=batch\n",
+			shares: []int64{1, 0, 1},
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2}, {PID: 1, Start: 2, Stop: 3},
+				{PID: 2, Start: 3, Stop: 4}, {PID: 1, Start: 4, Stop: 5}, {PID: 2, Start: 5, Stop: 6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mlq(processes, options{mlqShares: tt.shares}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mlq() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
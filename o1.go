@@ -0,0 +1,158 @@
+package main
+
+//region Linux O(1) scheduler
+
+// Tunables of the Linux 2.6 O(1) scheduler, taking one time unit as 20 ms.
+const (
+	// o1MaxSleepAvg caps the sleep average, 1 s.
+	o1MaxSleepAvg = 50
+	// o1MaxBonus is the span of the dynamic priority bonus, -5 to +5.
+	o1MaxBonus = 10
+	// o1StarvationLimit is how long, per runnable task, the expired array
+	// may wait before interactive tasks stop going back to the active one.
+	o1StarvationLimit = o1MaxSleepAvg
+)
+
+type (
+	// linuxO1 emulates the O(1) scheduler Linux used before CFS. Runnable
+	// tasks sit in an active or an expired array. It dispatches the active
+	// task with the best dynamic priority, first come first served within a
+	// priority, and preempts it as soon as a better one is ready. A task that
+	// uses up its timeslice gets a new one and moves to the expired array,
+	// unless it is interactive and the expired array is not starving; once
+	// the active array is empty the two swap. The process priority is the
+	// nice value, which sets the static priority and the timeslice. The
+	// dynamic priority adds a bonus from the sleep average, which grows while
+	// the task is blocked and shrinks while it runs, favoring I/O-bound tasks.
+	linuxO1 struct {
+		tasks map[int64]*o1Task
+		seq   int
+		// elapsed is the CPU time used so far, and expiredAt when the first
+		// task of this epoch expired, or -1.
+		elapsed   int64
+		expiredAt int64
+	}
+
+	o1Task struct {
+		expired  bool
+		slice    int64
+		sleepAvg int64
+		// ran and blocked are how long the task had run and been blocked
+		// when last seen, to charge what happened since.
+		ran, blocked int64
+		// seq orders tasks of the same priority by when they joined their
+		// array.
+		seq int
+	}
+)
+
+func (p *linuxO1) reset() {
+	*p = linuxO1{tasks: make(map[int64]*o1Task), expiredAt: -1}
+}
+
+// o1Timeslice is 100 ms at nice 0, from 800 ms at nice -20 down to 5 ms at
+// nice 19, rounded down to whole units but at least one.
+func o1Timeslice(t *task) int64 {
+	static := 120 + t.nice()
+	if static < 120 {
+		return 140 - static
+	}
+
+	return max((140-static)/4, 1)
+}
+
+// prio is the dynamic priority of t, from 100 (best) to 139.
+func (p *linuxO1) prio(t *task) int64 {
+	bonus := p.tasks[t.ProcessID].sleepAvg*o1MaxBonus/o1MaxSleepAvg - o1MaxBonus/2
+
+	return min(max(120+t.nice()-bonus, 100), 139)
+}
+
+// interactive reports whether the sleep bonus of t lifts its dynamic priority
+// far enough above its static one, the further the nicer the task.
+func (p *linuxO1) interactive(t *task) bool {
+	delta := t.nice()*o1MaxBonus/40 + 2
+
+	return p.prio(t) <= 120+t.nice()-delta
+}
+
+// account charges every ready task for what it ran and slept since it was
+// last seen, expiring tasks whose timeslice ran out.
+func (p *linuxO1) account(ready []*task) {
+	for _, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		if !ok {
+			p.seq++
+			p.tasks[t.ProcessID] = &o1Task{slice: o1Timeslice(t), seq: p.seq}
+			continue
+		}
+		if d := t.ran - s.ran; d > 0 {
+			s.ran = t.ran
+			p.elapsed += d
+			s.sleepAvg = max(s.sleepAvg-d, 0)
+			if s.slice -= d; s.slice <= 0 {
+				s.slice = o1Timeslice(t)
+				p.seq++
+				s.seq = p.seq
+				starving := p.expiredAt >= 0 && p.elapsed-p.expiredAt >= o1StarvationLimit*int64(len(ready))
+				if !p.interactive(t) || starving {
+					s.expired = true
+					if p.expiredAt < 0 {
+						p.expiredAt = p.elapsed
+					}
+				}
+			}
+		}
+		// A task that woke up rejoins the active array
+		if d := t.blockedFor - s.blocked; d > 0 {
+			s.blocked = t.blockedFor
+			s.sleepAvg = min(s.sleepAvg+d, o1MaxSleepAvg)
+			s.expired = false
+			p.seq++
+			s.seq = p.seq
+		}
+	}
+}
+
+func (p *linuxO1) pick(ready []*task) int {
+	p.account(ready)
+	active := false
+	for _, t := range ready {
+		active = active || !p.tasks[t.ProcessID].expired
+	}
+	if !active {
+		for _, t := range ready {
+			p.tasks[t.ProcessID].expired = false
+		}
+		p.expiredAt = -1
+	}
+
+	best := -1
+	for i, t := range ready {
+		s := p.tasks[t.ProcessID]
+		if s.expired {
+			continue
+		}
+		if best < 0 {
+			best = i
+			continue
+		}
+		b := ready[best]
+		if pr, bp := p.prio(t), p.prio(b); pr < bp || pr == bp && s.seq < p.tasks[b.ProcessID].seq {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// quantum is a single unit, so that a better task preempts the running one
+// as soon as it is ready; timeslices are kept by account.
+func (*linuxO1) quantum(*task) int64 { return 1 }
+
+// Linux O(1)
+func o1(processes []Process, opts options) Result {
+	return simulate(processes, &linuxO1{}, opts)
+}
+
+//endregion
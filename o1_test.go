@@ -0,0 +1,63 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_o1(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		opts options
+		want []TimeSlice
+	}{
+		{
+			name: "timeslices expire and the arrays swap",
+			csv:  "1,10,0\n2,10,0\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10},
+				{PID: 1, Start: 10, Stop: 15}, {PID: 2, Start: 15, Stop: 20},
+			},
+		},
+		{
+			name: "nice sets the timeslice",
+			csv:  "1,30,0,-5\n2,30,0,5\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 25}, {PID: 2, Start: 25, Stop: 28},
+				{PID: 1, Start: 28, Stop: 33}, {PID: 2, Start: 33, Stop: 60},
+			},
+		},
+		{
+			name: "a better priority preempts",
+			csv:  "1,6,0\n2,2,2,-10\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 8},
+			},
+		},
+		{
+			name: "a sleeper's bonus preempts",
+			csv:  "1,4,0,0,await=1\n2,50,0\n",
+			opts: options{events: []Event{{Time: 38, Kind: eventSignal, PID: 1}}},
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 38},
+				{PID: 1, Start: 38, Stop: 41}, {PID: 2, Start: 41, Stop: 54},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := o1(processes, tt.opts).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("o1() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
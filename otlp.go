@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//region OpenTelemetry export
+
+// otlpScope names the instrumentation that produced exported spans.
+const otlpScope = "github.com/SamFisher0208/CSCE4600"
+
+type (
+	// The OTLP/JSON encoding of an ExportTraceServiceRequest, as much of it
+	// as schedules need. 64-bit integers are strings, as the encoding says.
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              int             `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string `json:"stringValue,omitempty"`
+		IntValue    *string `json:"intValue,omitempty"`
+	}
+)
+
+func otlpString(key, v string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &v}}
+}
+
+func otlpInt(key string, v int64) otlpAttribute {
+	s := strconv.FormatInt(v, 10)
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &s}}
+}
+
+// writeOTLP writes every algorithm's schedule as OpenTelemetry spans in the
+// OTLP/JSON encoding: one trace per algorithm, whose root span covers the
+// whole simulation, with a child span for every Gantt slice. Time 0 is start
+// and a time unit lasts unit. Trace IDs are derived from the algorithm and
+// start, so exporting the same run twice gives the same traces.
+func writeOTLP(w io.Writer, processes []Process, opts options, start time.Time, unit time.Duration) error {
+	at := func(t int64) string {
+		return strconv.FormatInt(start.Add(time.Duration(t)*unit).UnixNano(), 10)
+	}
+	var scope otlpScopeSpans
+	scope.Scope.Name = otlpScope
+	for _, alg := range algorithms {
+		r := alg.run(processes, opts)
+		sum := sha256.Sum256([]byte(alg.key + "@" + start.UTC().Format(time.RFC3339Nano)))
+		traceID := hex.EncodeToString(sum[:16])
+		spanID := func(n int) string {
+			var id [8]byte
+			binary.BigEndian.PutUint64(id[:], uint64(n))
+			return hex.EncodeToString(id[:])
+		}
+
+		var end int64
+		for _, s := range r.Gantt {
+			end = max(end, s.Stop)
+		}
+		root := spanID(1)
+		scope.Spans = append(scope.Spans, otlpSpan{
+			TraceID:           traceID,
+			SpanID:            root,
+			Name:              alg.name,
+			Kind:              1,
+			StartTimeUnixNano: at(0),
+			EndTimeUnixNano:   at(end),
+			Attributes: []otlpAttribute{
+				otlpString("scheduler.algorithm", alg.key),
+				otlpInt("scheduler.processes", int64(len(r.processes))),
+			},
+		})
+		for i, s := range r.Gantt {
+			scope.Spans = append(scope.Spans, otlpSpan{
+				TraceID:           traceID,
+				SpanID:            spanID(i + 2),
+				ParentSpanID:      root,
+				Name:              fmt.Sprintf("P%d", s.PID),
+				Kind:              1,
+				StartTimeUnixNano: at(s.Start),
+				EndTimeUnixNano:   at(s.Stop),
+				Attributes: []otlpAttribute{
+					otlpInt("process.pid", s.PID),
+					otlpInt("cpu", 0),
+					otlpString("scheduler.reason", r.reasons[i]),
+				},
+			})
+		}
+	}
+
+	req := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpAttribute{otlpString("service.name", "process-scheduler")}},
+		ScopeSpans: []otlpScopeSpans{scope},
+	}}}
+
+	return json.NewEncoder(w).Encode(req)
+}
+
+// exportOTLP sends the spans to an OTLP/HTTP collector when dest is an
+// http:// or https:// URL, e.g. http://localhost:4318/v1/traces, and writes
+// them to the file dest otherwise.
+func exportOTLP(dest string, processes []Process, opts options, start time.Time, unit time.Duration) error {
+	var b bytes.Buffer
+	if err := writeOTLP(&b, processes, opts, start, unit); err != nil {
+		return fmt.Errorf("%w: error encoding spans", err)
+	}
+	if !strings.HasPrefix(dest, "http://") && !strings.HasPrefix(dest, "https://") {
+		if err := os.WriteFile(dest, b.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("%w: error writing spans", err)
+		}
+		return nil
+	}
+
+	resp, err := http.DefaultClient.Post(dest, "application/json", &b)
+	if err != nil {
+		return fmt.Errorf("%w: error exporting spans", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: exporting spans to %s: %s", ErrInvalidArgs, dest, resp.Status)
+	}
+
+	return nil
+}
+
+//endregion
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_writeOTLP(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,2,0\n2,1,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	if err := writeOTLP(&out, processes, options{}, start, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	var req otlpRequest
+	if err := json.Unmarshal(out.Bytes(), &req); err != nil {
+		t.Fatal(err)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+
+	// FCFS comes first: its root span and one span per slice
+	root, first, second := spans[0], spans[1], spans[2]
+	if root.Name != "First-come, first-serve" || root.ParentSpanID != "" || root.EndTimeUnixNano != "1767603603000000000" {
+		t.Errorf("writeOTLP() root span = %+v", root)
+	}
+	if first.Name != "P1" || first.ParentSpanID != root.SpanID || first.TraceID != root.TraceID ||
+		first.StartTimeUnixNano != "1767603600000000000" || first.EndTimeUnixNano != "1767603602000000000" {
+		t.Errorf("writeOTLP() first slice span = %+v", first)
+	}
+	if second.Name != "P2" || *second.Attributes[0].Value.IntValue != "2" || *second.Attributes[2].Value.StringValue != sliceExit {
+		t.Errorf("writeOTLP() second slice span = %+v", second)
+	}
+
+	// Every algorithm has a trace of its own, the same every export
+	traces := make(map[string]bool)
+	for _, s := range spans {
+		traces[s.TraceID] = true
+	}
+	if len(traces) != len(algorithms) {
+		t.Errorf("writeOTLP() wrote %d traces, want %d", len(traces), len(algorithms))
+	}
+	var again bytes.Buffer
+	_ = writeOTLP(&again, processes, options{}, start, time.Second)
+	if again.String() != out.String() {
+		t.Errorf("writeOTLP() differs between exports")
+	}
+}
+
+func Test_exportOTLP(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,2,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contentType string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+	if err := exportOTLP(srv.URL+"/v1/traces", processes, options{}, time.Unix(0, 0), time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/json" || !bytes.Contains(body, []byte(`"resourceSpans"`)) {
+		t.Errorf("exportOTLP() posted %q as %s", body, contentType)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import "fmt"
+
+//region Reserved process IDs
+
+// Process IDs of 0 and below are reserved for Gantt slices that belong to no
+// process, so they never clash with the IDs of a workload.
+const (
+	// idlePID stands for time the CPU is idle, like Unix's idle task.
+	idlePID int64 = 0
+	// switchPID stands for time spent switching between processes.
+	switchPID int64 = -1
+)
+
+// checkPID rejects the process IDs reserved for idle and overhead slices.
+func checkPID(pid int64) error {
+	if pid <= idlePID {
+		return fmt.Errorf("%w: process ID %d is reserved, IDs must be positive", ErrInvalidWorkload, pid)
+	}
+
+	return nil
+}
+
+// checkUniquePIDs rejects a workload in which two processes share an ID,
+// which would make its parents, signals and results ambiguous.
+func checkUniquePIDs(processes []Process) error {
+	seen := make(map[int64]bool, len(processes))
+	for _, p := range processes {
+		if seen[p.ProcessID] {
+			return fmt.Errorf("%w: process ID %d is used more than once", ErrInvalidWorkload, p.ProcessID)
+		}
+		seen[p.ProcessID] = true
+	}
+
+	return nil
+}
+
+// pidLabel names whatever a Gantt slice belongs to in charts and tables:
+// the process ID, or idle or switch for the reserved ones.
+func pidLabel(pid int64) string {
+	switch {
+	case pid == idlePID:
+		return "idle"
+	case pid == switchPID:
+		return "switch"
+	case pid < idlePID:
+		return "reserved"
+	}
+
+	return fmt.Sprint(pid)
+}
+
+//endregion
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_checkPID(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr error
+	}{
+		{name: "positive", csv: "1,5,0\n2,3,1\n"},
+		{name: "idle", csv: "1,5,0\n0,5,0\n", wantErr: ErrInvalidWorkload},
+		{name: "switch", csv: "-1,5,0\n", wantErr: ErrInvalidWorkload},
+		{name: "negative", csv: "-7,5,0\n", wantErr: ErrInvalidWorkload},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := loadProcesses(strings.NewReader(tt.csv))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadProcesses() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "reserved") {
+				t.Errorf("loadProcesses() error = %v, want it to say the ID is reserved", err)
+			}
+		})
+	}
+}
+
+func Test_checkPID_swf(t *testing.T) {
+	t.Parallel()
+	_, err := loadSWF(strings.NewReader("0 0 0 5 1 -1 -1 1 -1 -1 1 -1 -1 -1 -1 -1 -1 -1\n"))
+	if !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("loadSWF() error = %v, want %v", err, ErrInvalidWorkload)
+	}
+}
+
+func Test_pidLabel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pid  int64
+		want string
+	}{
+		{pid: 1, want: "1"},
+		{pid: 42, want: "42"},
+		{pid: idlePID, want: "idle"},
+		{pid: switchPID, want: "switch"},
+		{pid: -5, want: "reserved"},
+	}
+	for _, tt := range tests {
+		if got := pidLabel(tt.pid); got != tt.want {
+			t.Errorf("pidLabel(%d) = %q, want %q", tt.pid, got, tt.want)
+		}
+	}
+}
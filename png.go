@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//region PNG charts
+
+const (
+	// pngDot is the size in pixels of one dot of the built-in font, whose
+	// glyphs are 3 by 5 dots.
+	pngDot     = 2
+	pngLabel   = 220
+	pngWidth   = 700
+	pngRow     = 36
+	pngMargin  = 16
+	pngBarSize = 24
+)
+
+var (
+	pngBackground = color.RGBA{255, 255, 255, 255}
+	pngInk        = color.RGBA{40, 40, 40, 255}
+	pngGrid       = color.RGBA{200, 200, 200, 255}
+	// pngPalette colours processes by PID in the Gantt chart, and the
+	// metrics in the bar chart.
+	pngPalette = []color.RGBA{
+		{141, 211, 199, 255}, {255, 255, 179, 255}, {190, 186, 218, 255}, {251, 128, 114, 255},
+		{128, 177, 211, 255}, {253, 180, 98, 255}, {179, 222, 105, 255}, {252, 205, 229, 255},
+		{188, 128, 189, 255}, {204, 235, 197, 255}, {255, 237, 111, 255}, {217, 217, 217, 255},
+	}
+)
+
+// pngGlyphs is a tiny 3 by 5 dot font, so charts can be labelled without a
+// font file. Each glyph is 15 dots, row by row, '#' for ink.
+var pngGlyphs = map[rune]string{
+	'0': "####.##.##.####", '1': ".#.##..#..#.###", '2': "###..#####..###", '3': "###..####..####",
+	'4': "#.##.####..#..#", '5': "####..###..####", '6': "####..####.####", '7': "###..#..#..#..#",
+	'8': "####.#####.####", '9': "####.####..####", 'A': ".#.#.#####.##.#", 'B': "##.#.###.#.###.",
+	'C': "####..#..#..###", 'D': "##.#.##.##.###.", 'E': "####..##.#..###", 'F': "####..##.#..#..",
+	'G': "####..#.##.####", 'H': "#.##.#####.##.#", 'I': "###.#..#..#.###", 'J': "..#..#..##.####",
+	'K': "#.##.###.#.##.#", 'L': "#..#..#..#..###", 'M': "#.########.##.#", 'N': "##.#.##.##.##.#",
+	'O': "####.##.##.####", 'P': "####.#####..#..", 'Q': "####.##.####..#", 'R': "##.#.###.#.##.#",
+	'S': "####..###..####", 'T': "###.#..#..#..#.", 'U': "#.##.##.##.####", 'V': "#.##.##.##.#.#.",
+	'W': "#.##.########.#", 'X': "#.##.#.#.#.##.#", 'Y': "#.##.#.#..#..#.", 'Z': "###..#.#.#..###",
+	'-': "......###......", ',': "..........#.#..", '.': ".............#.", '/': "..#..#.#.#..#..",
+	':': "....#.....#....", ' ': "...............", '(': ".#.#..#..#...#.", ')': ".#...#..#..#.#.",
+	'?': "###..#.##....#.",
+}
+
+// writePNGDir renders every algorithm's Gantt chart and a bar chart of their
+// average wait and turnaround as gantt.png and metrics.png in dir, which is
+// created if needed.
+func writePNGDir(dir string, processes []Process, opts options) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("%w: error creating PNG directory", err)
+	}
+	names := make([]string, len(algorithms))
+	results := make([]Result, len(algorithms))
+	for i, alg := range algorithms {
+		names[i], results[i] = alg.name, alg.run(processes, opts)
+	}
+
+	charts := []struct {
+		file string
+		draw func(io.Writer, []string, []Result) error
+	}{
+		{"gantt.png", writeGanttPNG},
+		{"metrics.png", writeMetricsPNG},
+	}
+	for _, c := range charts {
+		f, err := os.Create(filepath.Join(dir, c.file))
+		if err != nil {
+			return fmt.Errorf("%w: error creating %s", err, c.file)
+		}
+		if err := c.draw(f, names, results); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("%w: error writing %s", err, c.file)
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeGanttPNG draws one Gantt row per result on a shared time axis.
+func writeGanttPNG(w io.Writer, names []string, results []Result) error {
+	end := int64(0)
+	for _, r := range results {
+		if n := len(r.Gantt); n > 0 && r.Gantt[n-1].Stop > end {
+			end = r.Gantt[n-1].Stop
+		}
+	}
+	height := 2*pngMargin + pngRow*len(results) + 20
+	img := newPNGCanvas(pngLabel+pngWidth+2*pngMargin, height)
+	x := func(t int64) int {
+		if end == 0 {
+			return pngLabel
+		}
+		return pngLabel + int(t*pngWidth/end)
+	}
+
+	for i, r := range results {
+		y := pngMargin + i*pngRow
+		drawPNGText(img, pngMargin, y+pngRow/2-5*pngDot/2, names[i], pngInk)
+		for _, s := range r.Gantt {
+			rect := image.Rect(x(s.Start), y, x(s.Stop), y+pngRow-6)
+			fill := pngGrid
+			if s.PID > 0 {
+				fill = pngPalette[int(s.PID)%len(pngPalette)]
+			}
+			fillPNGRect(img, rect, fill)
+			outlinePNGRect(img, rect, pngInk)
+			label := pidLabel(s.PID)
+			if textWidth(label) < rect.Dx()-4 {
+				drawPNGText(img, (rect.Min.X+rect.Max.X-textWidth(label))/2, y+(pngRow-6)/2-5*pngDot/2, label, pngInk)
+			}
+		}
+	}
+
+	// Time axis with five ticks
+	axis := pngMargin + len(results)*pngRow
+	fillPNGRect(img, image.Rect(pngLabel, axis, pngLabel+pngWidth+1, axis+1), pngInk)
+	for i := int64(0); i <= 4; i++ {
+		t := end * i / 4
+		fillPNGRect(img, image.Rect(x(t), axis, x(t)+1, axis+5), pngInk)
+		label := fmt.Sprint(t)
+		drawPNGText(img, min(x(t)-textWidth(label)/2, pngLabel+pngWidth-textWidth(label)), axis+8, label, pngInk)
+	}
+
+	return png.Encode(w, img)
+}
+
+// writeMetricsPNG draws a horizontal bar chart of each result's average wait
+// and average turnaround, with the values written beside the bars.
+func writeMetricsPNG(w io.Writer, names []string, results []Result) error {
+	metrics := []struct {
+		name  string
+		value func(Result) float64
+	}{
+		{"Average wait", func(r Result) float64 { return r.AveWait }},
+		{"Average turnaround", func(r Result) float64 { return r.AveTurnaround }},
+	}
+	peak := 0.0
+	for _, r := range results {
+		for _, m := range metrics {
+			peak = max(peak, m.value(r))
+		}
+	}
+	group := pngBarSize*len(metrics) + 12
+	height := 2*pngMargin + group*len(results) + 20
+	img := newPNGCanvas(pngLabel+pngWidth+2*pngMargin, height)
+
+	for i, r := range results {
+		y := pngMargin + i*group
+		drawPNGText(img, pngMargin, y+pngBarSize-5*pngDot/2, names[i], pngInk)
+		for j, m := range metrics {
+			v := m.value(r)
+			length := 0
+			if peak > 0 {
+				length = int(v / peak * float64(pngWidth-80))
+			}
+			top := y + j*pngBarSize
+			fillPNGRect(img, image.Rect(pngLabel, top, pngLabel+length, top+pngBarSize-4), pngPalette[j*4%len(pngPalette)])
+			drawPNGText(img, pngLabel+length+6, top+(pngBarSize-4)/2-5*pngDot/2, fmt.Sprintf("%.2f", v), pngInk)
+		}
+	}
+
+	// Legend
+	y := height - pngMargin - 5*pngDot
+	x := pngLabel
+	for j, m := range metrics {
+		fillPNGRect(img, image.Rect(x, y, x+5*pngDot, y+5*pngDot), pngPalette[j*4%len(pngPalette)])
+		drawPNGText(img, x+8*pngDot, y, m.name, pngInk)
+		x += 8*pngDot + textWidth(m.name) + 24
+	}
+
+	return png.Encode(w, img)
+}
+
+func newPNGCanvas(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: pngBackground}, image.Point{}, draw.Src)
+
+	return img
+}
+
+func fillPNGRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func outlinePNGRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	fillPNGRect(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1), c)
+	fillPNGRect(img, image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), c)
+	fillPNGRect(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y), c)
+	fillPNGRect(img, image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y), c)
+}
+
+// textWidth is the width in pixels of s in the built-in font.
+func textWidth(s string) int {
+	return len([]rune(s)) * 4 * pngDot
+}
+
+// drawPNGText writes s in the built-in font with its top left corner at x, y.
+// Letters are drawn in upper case; characters without a glyph show as '?'.
+func drawPNGText(img *image.RGBA, x, y int, s string, c color.Color) {
+	for _, ch := range strings.ToUpper(s) {
+		glyph, ok := pngGlyphs[ch]
+		if !ok {
+			glyph = pngGlyphs['?']
+		}
+		for i, dot := range glyph {
+			if dot != '#' {
+				continue
+			}
+			px, py := x+(i%3)*pngDot, y+(i/3)*pngDot
+			fillPNGRect(img, image.Rect(px, py, px+pngDot, py+pngDot), c)
+		}
+		x += 4 * pngDot
+	}
+}
+
+//endregion
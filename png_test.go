@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_writeGanttPNG(t *testing.T) {
+	t.Parallel()
+	r := fcfs([]Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5},
+	}, options{})
+	var b bytes.Buffer
+	if err := writeGanttPNG(&b, []string{"FCFS"}, []Result{r}); err != nil {
+		t.Fatalf("writeGanttPNG() error = %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("writeGanttPNG() wrote an invalid PNG: %v", err)
+	}
+	if got, want := img.Bounds().Dx(), pngLabel+pngWidth+2*pngMargin; got != want {
+		t.Errorf("width = %v, want %v", got, want)
+	}
+	// Each process fills its half of the timeline in its own colour
+	y := pngMargin + 4
+	for _, p := range []struct {
+		x   int
+		pid int
+	}{{pngLabel + 2, 1}, {pngLabel + pngWidth - 3, 2}} {
+		if got, want := img.At(p.x, y), pngPalette[p.pid]; got != want {
+			t.Errorf("pixel at %d = %v, want the colour of %d %v", p.x, got, p.pid, want)
+		}
+	}
+}
+
+func Test_writePNGDir(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "charts")
+	processes := []Process{{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5}}
+	if err := writePNGDir(dir, processes, options{}); err != nil {
+		t.Fatalf("writePNGDir() error = %v", err)
+	}
+	for _, name := range []string{"gantt.png", "metrics.png"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("writePNGDir() did not write %s: %v", name, err)
+		}
+		if _, err := png.Decode(f); err != nil {
+			t.Errorf("%s is not a valid PNG: %v", name, err)
+		}
+		_ = f.Close()
+	}
+}
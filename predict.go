@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Burst prediction
+
+const (
+	// defaultPredictAlpha is the weight of the last CPU burst in the
+	// prediction of the next unless set.
+	defaultPredictAlpha = 0.5
+	// predictTau0 is the prediction of a process's first CPU burst, before
+	// anything is known about it, as in the textbook example.
+	predictTau0 = 10
+)
+
+type (
+	// burstPredictor is shortest-job-first, or shortest-remaining-time when
+	// preemptive, as a real kernel could run it: without knowing how long
+	// CPU bursts are. It predicts each task's next CPU burst, the time until
+	// it next blocks, yields, ends a replayed burst or completes, by the
+	// exponential average τ(n+1) = αt(n) + (1-α)τ(n) of the bursts it has
+	// seen the task run, starting from predictTau0. The ready task with the
+	// shortest predicted burst runs until it gives up the CPU or, when
+	// preemptive, with the least of its prediction left, preempting the
+	// running task for one with less. Ties go to the task that arrived
+	// first, then the lower ID.
+	burstPredictor struct {
+		alpha      float64
+		preemptive bool
+		tasks      map[int64]*burstPrediction
+		// order has every task in the order it was first ready.
+		order []*burstPrediction
+	}
+
+	// burstPrediction is the prediction tau of a task's current or next CPU
+	// burst, and the error of those made for the seen bursts it has run.
+	burstPrediction struct {
+		t    *task
+		tau  float64
+		seen int
+		// absError and squared sum the absolute and squared errors of the
+		// predictions of every burst seen.
+		absError, squared float64
+	}
+)
+
+func (p *burstPredictor) reset() {
+	p.tasks = make(map[int64]*burstPrediction)
+	p.order = nil
+}
+
+// learn updates the prediction of every task that has ended a CPU burst
+// since the last pick.
+func (p *burstPredictor) learn() {
+	for _, b := range p.order {
+		if b.seen < b.t.bursts {
+			e := float64(b.t.lastBurst) - b.tau
+			b.absError += math.Abs(e)
+			b.squared += e * e
+			b.tau = p.alpha*float64(b.t.lastBurst) + (1-p.alpha)*b.tau
+			b.seen = b.t.bursts
+		}
+	}
+}
+
+// predicted returns how long t is predicted to run before it gives up the
+// CPU: the whole of its burst, or when preemptive what is left of it, at
+// least 0 once the burst has run longer than predicted.
+func (p *burstPredictor) predicted(t *task) float64 {
+	tau := p.tasks[t.ProcessID].tau
+	if p.preemptive {
+		return max(tau-float64(t.cpuBurst), 0)
+	}
+
+	return tau
+}
+
+func (p *burstPredictor) pick(ready []*task) int {
+	for _, t := range ready {
+		if _, ok := p.tasks[t.ProcessID]; !ok {
+			b := &burstPrediction{t: t, tau: predictTau0, seen: t.bursts}
+			p.tasks[t.ProcessID] = b
+			p.order = append(p.order, b)
+		}
+	}
+	p.learn()
+
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		switch d, bd := p.predicted(t), p.predicted(b); {
+		case d != bd:
+			if d < bd {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	return best
+}
+
+// quantum is a unit when preemptive, so that a task predicted to finish its
+// burst sooner may preempt, and otherwise the rest of the burst.
+func (p *burstPredictor) quantum(*task) int64 {
+	if p.preemptive {
+		return 1
+	}
+
+	return 0
+}
+
+// Shortest-job-first with predicted bursts
+func PredictedSJFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, psjf(processes, options{}), display{})
+}
+
+func psjf(processes []Process, opts options) Result {
+	r, _ := predictBursts(processes, opts, false)
+	return r
+}
+
+func psrtf(processes []Process, opts options) Result {
+	r, _ := predictBursts(processes, opts, true)
+	return r
+}
+
+// predictBursts runs shortest-job-first, or shortest-remaining-time when
+// preemptive, on bursts predicted with the weight opts.predictAlpha, or
+// defaultPredictAlpha, and also returns the predictor, with the error of
+// its predictions.
+func predictBursts(processes []Process, opts options, preemptive bool) (Result, *burstPredictor) {
+	p := &burstPredictor{alpha: opts.predictAlpha, preemptive: preemptive}
+	if p.alpha == 0 {
+		p.alpha = defaultPredictAlpha
+	}
+	r := simulate(processes, p, opts)
+	// Bursts that ended the run have had no pick since
+	p.learn()
+
+	return r, p
+}
+
+// outputPrediction shows how far off the predictions of every process's CPU
+// bursts were. They depend only on the bursts, which are the same under any
+// scheduler, so the preemptive and non-preemptive predictors agree.
+func outputPrediction(w io.Writer, processes []Process, opts options, d display) {
+	_, p := predictBursts(append([]Process(nil), processes...), opts, false)
+	order := append([]*burstPrediction(nil), p.order...)
+	sort.Slice(order, func(i, j int) bool { return order[i].t.ProcessID < order[j].t.ProcessID })
+
+	outputTitle(w, fmt.Sprintf("Burst prediction (α = %g)", p.alpha))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "CPU Bursts", "Mean Abs Error", "RMS Error", "Next Prediction"})
+	for _, b := range order {
+		mae, rms := "-", "-"
+		if b.seen > 0 {
+			mae = d.format(b.absError / float64(b.seen))
+			rms = d.format(math.Sqrt(b.squared / float64(b.seen)))
+		}
+		table.Append([]string{
+			fmt.Sprint(b.t.ProcessID),
+			fmt.Sprint(b.seen),
+			mae,
+			rms,
+			d.format(b.tau),
+		})
+	}
+	table.Render()
+}
+
+//endregion
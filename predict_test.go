@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_predictBursts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		csv        string
+		preemptive bool
+		want       []TimeSlice
+	}{
+		{
+			// P1's second burst is predicted to take 15 after its first of
+			// 20, so P2, predicted to take 10, preempts it at 22
+			name:       "preemptive",
+			csv:        "1,40,0,0,bursts=20;20\n2,2,22\n",
+			preemptive: true,
+			want:       []TimeSlice{{PID: 1, Start: 0, Stop: 22}, {PID: 2, Start: 22, Stop: 24}, {PID: 1, Start: 24, Stop: 42}},
+		},
+		{
+			name: "non-preemptive",
+			csv:  "1,40,0,0,bursts=20;20\n2,2,22\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 40}, {PID: 2, Start: 40, Stop: 42}},
+		},
+		{
+			// Both are predicted to take 10 at first. P1's short bursts
+			// then keep it ahead of P2 until it completes
+			name: "short bursts",
+			csv:  "1,4,0,0,bursts=1;1;1;1\n2,12,0\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 16}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := predictBursts(processes, options{}, tt.preemptive); !reflect.DeepEqual(got.Gantt, tt.want) {
+				t.Errorf("predictBursts() gantt = %v, want %v", got.Gantt, tt.want)
+			}
+		})
+	}
+}
+
+func Test_burstPredictor_learn(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,12,0,0,bursts=2;8;2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// By halves, τ goes from 10 to 6, 7 and 4.5, off by 8, 2 and 5
+	for _, tt := range []struct {
+		alpha                  float64
+		tau, absError, squared float64
+	}{
+		{alpha: 0.5, tau: 4.5, absError: 15, squared: 93},
+		// Only the last burst counts, so each prediction after the first is
+		// the burst before
+		{alpha: 1, tau: 2, absError: 8 + 6 + 6, squared: 64 + 36 + 36},
+	} {
+		_, p := predictBursts(processes, options{predictAlpha: tt.alpha}, false)
+		b := p.tasks[1]
+		if b.seen != 3 || b.tau != tt.tau || b.absError != tt.absError || b.squared != tt.squared {
+			t.Errorf("predictBursts() with α = %v predicted %+v, want τ %v and errors %v, %v over 3 bursts",
+				tt.alpha, *b, tt.tau, tt.absError, tt.squared)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "io"
+
+//region Preemptive priority
+
+// preemptivePriority dispatches the ready task with the lowest rank, the
+// highest priority, breaking ties by arrival and then ID. It reconsiders its choice
+// every unit so that a newly arrived task of higher priority preempts the
+// running one, which runs on later where it left off.
+type preemptivePriority struct{}
+
+func (preemptivePriority) pick(ready []*task) int {
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		switch {
+		case t.rank != b.rank:
+			if t.rank < b.rank {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (preemptivePriority) quantum(*task) int64 { return 1 }
+
+// Preemptive priority
+func PreemptivePrioritySchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, priorityPreemptive(processes, options{}), display{})
+}
+
+func priorityPreemptive(processes []Process, opts options) Result {
+	if opts.aging != nil {
+		r, _ := priorityAging(processes, opts, true)
+		return r
+	}
+
+	return simulate(processes, preemptivePriority{}, opts)
+}
+
+//endregion
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_priorityPreemptive(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			"higher priority arrival preempts",
+			"1,6,0,3\n2,2,2,1\n3,3,3,2\n",
+			[]TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 3, Start: 4, Stop: 7}, {PID: 1, Start: 7, Stop: 11}},
+		},
+		{
+			"equal priority runs on",
+			"1,4,0,2\n2,2,1,2\n",
+			[]TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := priorityPreemptive(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("priorityPreemptive() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreemptivePrioritySchedule(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,6,0,3\n2,2,2,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	PreemptivePrioritySchedule(&out, "Preemptive priority", processes)
+	if want := "|   1   |   2   |   1   |"; !strings.Contains(out.String(), want) {
+		t.Errorf("PreemptivePrioritySchedule() missing %q:\n%s", want, out.String())
+	}
+}
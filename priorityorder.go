@@ -0,0 +1,39 @@
+package main
+
+import "fmt"
+
+//region Priority order
+
+// Orders of the priority column accepted by -priority-order. Textbooks
+// disagree: some give the highest priority the lowest number, as Unix nice
+// values do, and some the highest.
+const (
+	lowFirst  = "low-first"
+	highFirst = "high-first"
+)
+
+// parsePriorityOrder parses a -priority-order value and reports whether
+// higher priority numbers come first.
+func parsePriorityOrder(s string) (bool, error) {
+	switch s {
+	case lowFirst:
+		return false, nil
+	case highFirst:
+		return true, nil
+	}
+
+	return false, fmt.Errorf("%w: priority order %q is neither %s nor %s", ErrInvalidArgs, s, lowFirst, highFirst)
+}
+
+// rank returns p's priority in the order every scheduler compares
+// priorities in, where lower ranks come first: the priority itself, or its
+// negation when higher priorities come first.
+func (o options) rank(p Process) int64 {
+	if o.highPriorityFirst {
+		return -p.Priority
+	}
+
+	return p.Priority
+}
+
+//endregion
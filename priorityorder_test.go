@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parsePriorityOrder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    bool
+		wantErr error
+	}{
+		{"low-first", false, nil},
+		{"high-first", true, nil},
+		{"highest", false, ErrInvalidArgs},
+		{"", false, ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := parsePriorityOrder(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parsePriorityOrder() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parsePriorityOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_highPriorityFirst(t *testing.T) {
+	t.Parallel()
+	in := "1,6,0,1,await=2\n2,8,1,3,signal=1@4\n3,4,2,-2\n4,7,3,5,yield=3\n5,3,9,0\n"
+	processes, err := loadProcesses(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	negated, err := loadProcesses(strings.NewReader(
+		"1,6,0,-1,await=2\n2,8,1,-3,signal=1@4\n3,4,2,2\n4,7,3,-5,yield=3\n5,3,9,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every scheduler that reads priorities, including as nice values and
+	// tickets, schedules high-first priorities as it does the negated ones
+	// low-first. SVR4 maps priorities onto its levels instead.
+	for _, alg := range algorithms {
+		alg := alg
+		if alg.key == "svr4" {
+			continue
+		}
+		t.Run(alg.key, func(t *testing.T) {
+			t.Parallel()
+			got := alg.run(append([]Process(nil), processes...), options{highPriorityFirst: true})
+			want := alg.run(append([]Process(nil), negated...), options{})
+			if !reflect.DeepEqual(got.Gantt, want.Gantt) {
+				t.Errorf("%s high-first gantt = %v, want %v", alg.key, got.Gantt, want.Gantt)
+			}
+			// The table still shows the priorities as given
+			if got.Schedule[0][1] != "1" {
+				t.Errorf("%s high-first shows priority %s for P1, want 1", alg.key, got.Schedule[0][1])
+			}
+		})
+	}
+
+	// Priority runs P2 before P1 when higher numbers come first
+	pair, err := loadProcesses(strings.NewReader("1,5,0,1\n2,5,0,3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := sjfPriority(pair, options{highPriorityFirst: true}).Gantt
+	if want := []TimeSlice{{PID: 2, Start: 0, Stop: 5}, {PID: 1, Start: 5, Stop: 10}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sjfPriority() high-first gantt = %v, want %v", got, want)
+	}
+}
+
+func Test_svr4PriorityOrder(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 5, Priority: 10}}
+	tests := []struct {
+		name string
+		opts options
+		want int
+	}{
+		{"low-first starts at the top less the priority", options{}, 49},
+		{"high-first starts at the priority", options{highPriorityFirst: true}, 10},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := &timeSharing{table: defaultDispatchTable(), highFirst: tt.opts.highPriorityFirst}
+			p.reset()
+			e := newEngine(processes, p, tt.opts)
+			if got := p.task(e.tasks[0]).level; got != tt.want {
+				t.Errorf("timeSharing.task() level = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
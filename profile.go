@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+//region Profiling
+
+// startProfiling starts a CPU profile and an execution trace written to the
+// given files, when they are set. The returned stop function ends them and
+// writes a heap profile to memFile, when it is set.
+func startProfiling(cpuFile, memFile, traceFile string) (stop func() error, err error) {
+	var stops []func() error
+	stop = func() error {
+		var first error
+		for i := len(stops) - 1; i >= 0; i-- {
+			if err := stops[i](); err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+
+	if cpuFile != "" {
+		f, err := os.Create(cpuFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error creating CPU profile", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("%w: error starting CPU profile", err)
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			_ = stop()
+			return nil, fmt.Errorf("%w: error creating trace", err)
+		}
+		if err := trace.Start(f); err != nil {
+			_ = f.Close()
+			_ = stop()
+			return nil, fmt.Errorf("%w: error starting trace", err)
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+	if memFile != "" {
+		stops = append(stops, func() error {
+			f, err := os.Create(memFile)
+			if err != nil {
+				return fmt.Errorf("%w: error creating memory profile", err)
+			}
+			runtime.GC() // up-to-date statistics
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("%w: error writing memory profile", err)
+			}
+			return f.Close()
+		})
+	}
+
+	return stop, nil
+}
+
+//endregion
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_startProfiling(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "cpu.prof"), filepath.Join(dir, "mem.prof"), filepath.Join(dir, "trace.out")}
+	stop, err := startProfiling(files[0], files[1], files[2])
+	if err != nil {
+		t.Fatalf("startProfiling() error = %v", err)
+	}
+	for _, alg := range algorithms {
+		alg.run([]Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 3}}, options{})
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+	for _, name := range files {
+		if info, err := os.Stat(name); err != nil || info.Size() == 0 {
+			t.Errorf("%s was not written: %v", filepath.Base(name), err)
+		}
+	}
+
+	if _, err := startProfiling(filepath.Join(dir, "missing", "cpu.prof"), "", ""); err == nil {
+		t.Errorf("startProfiling() into a missing directory, want an error")
+	}
+	stop, err = startProfiling("", "", "")
+	if err != nil || stop() != nil {
+		t.Errorf("startProfiling() with no files = %v", err)
+	}
+}
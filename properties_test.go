@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// workload is a random CSV workload for property tests, generated by
+// testing/quick.
+type workload struct {
+	csv string
+	// together says whether every process arrives at 0.
+	together bool
+}
+
+func (workload) Generate(r *rand.Rand, size int) reflect.Value {
+	var b strings.Builder
+	together := r.Intn(2) == 0
+	for i := range 1 + r.Intn(max(size/4, 1)) {
+		arrival := 0
+		if !together {
+			arrival = r.Intn(50)
+		}
+		_, _ = fmt.Fprintf(&b, "%d,%d,%d,%d\n", i+1, 1+r.Intn(20), arrival, r.Intn(10))
+	}
+
+	return reflect.ValueOf(workload{b.String(), together})
+}
+
+func (w workload) processes(t *testing.T) []Process {
+	processes, err := loadProcesses(strings.NewReader(w.csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return processes
+}
+
+func TestProperties(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		// holds says whether the property holds of the workload, or why not.
+		holds func(t *testing.T, w workload) error
+	}{
+		{
+			// SJF minimizes the average wait of processes arriving together
+			name: "sjf waits no longer than fcfs",
+			holds: func(t *testing.T, w workload) error {
+				if !w.together {
+					return nil
+				}
+				processes := w.processes(t)
+				if f, s := fcfs(processes, options{}).AveWait, sjf(processes, options{}).AveWait; f < s {
+					return fmt.Errorf("fcfs average wait %v, sjf %v", f, s)
+				}
+				return nil
+			},
+		},
+		{
+			name: "no negative metrics",
+			holds: func(t *testing.T, w workload) error {
+				processes := w.processes(t)
+				for _, alg := range algorithms {
+					r := alg.run(processes, options{})
+					if r.AveWait < 0 || r.AveTurnaround < 0 || r.AveThroughput < 0 {
+						return fmt.Errorf("%s: wait %v, turnaround %v, throughput %v", alg.key, r.AveWait, r.AveTurnaround, r.AveThroughput)
+					}
+					for _, s := range r.Gantt {
+						if s.Start < 0 || s.Stop <= s.Start {
+							return fmt.Errorf("%s: slice %v", alg.key, s)
+						}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// Every burst runs in full, so the last process to arrive cannot
+			// finish before its arrival plus the shortest burst
+			name: "makespan bound",
+			holds: func(t *testing.T, w workload) error {
+				processes := w.processes(t)
+				var last, shortest, total int64 = 0, processes[0].BurstDuration, 0
+				for _, p := range processes {
+					last = max(last, p.ArrivalTime)
+					shortest = min(shortest, p.BurstDuration)
+					total += p.BurstDuration
+				}
+				for _, alg := range algorithms {
+					r := alg.run(processes, options{})
+					var ran int64
+					for _, s := range r.Gantt {
+						ran += s.Stop - s.Start
+					}
+					if ran != total {
+						return fmt.Errorf("%s: ran %d of %d units", alg.key, ran, total)
+					}
+					if makespan := r.Gantt[len(r.Gantt)-1].Stop; makespan < last+shortest {
+						return fmt.Errorf("%s: makespan %d, want at least %d", alg.key, makespan, last+shortest)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// Round robin dispatches each of n processes arriving together
+			// within (n-1) slices of 5 units
+			name: "round robin fairness",
+			holds: func(t *testing.T, w workload) error {
+				if !w.together {
+					return nil
+				}
+				processes := w.processes(t)
+				bound := int64(len(processes)-1) * 5
+				seen := make(map[int64]bool)
+				for _, s := range rr(processes, options{}).Gantt {
+					if !seen[s.PID] && s.Start > bound {
+						return fmt.Errorf("P%d first runs at %d, want at most %d", s.PID, s.Start, bound)
+					}
+					seen[s.PID] = true
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			property := func(w workload) bool {
+				if err := tt.holds(t, w); err != nil {
+					t.Errorf("%v for workload:\n%s", err, w.csv)
+					return false
+				}
+				return true
+			}
+			if err := quick.Check(property, &quick.Config{Rand: rand.New(rand.NewSource(1))}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import "io"
+
+//region Priority round-robin
+
+type (
+	// priorityRoundRobin keeps a ready queue for every priority and runs the
+	// highest non-empty one round-robin, slice units at a time, so that
+	// processes of equal priority take turns instead of running in the order
+	// they happened to arrive. A process of higher priority preempts as soon
+	// as it is ready; the one it preempts keeps its place and the rest of
+	// its slice.
+	priorityRoundRobin struct {
+		slice int64
+		tasks map[int64]*prrTask
+		seq   int
+		// last is the task dispatched by the last pick, which had run lastRan
+		// then.
+		last    *task
+		lastRan int64
+	}
+
+	prrTask struct {
+		// slice is what is left of the task's time slice.
+		slice int64
+		// seq orders tasks of the same priority by their turn.
+		seq int
+		// blocked is how long the task had been blocked when last seen, so
+		// that one that woke goes to the back of its queue.
+		blocked int64
+	}
+)
+
+func (p *priorityRoundRobin) reset() {
+	*p = priorityRoundRobin{slice: p.slice, tasks: make(map[int64]*prrTask)}
+}
+
+// charge accounts for what the last task dispatched ran, sending it to the
+// back of its queue if its slice ran out.
+func (p *priorityRoundRobin) charge() {
+	t := p.last
+	if t == nil {
+		return
+	}
+	s := p.tasks[t.ProcessID]
+	if s.slice -= t.ran - p.lastRan; s.slice <= 0 {
+		p.seq++
+		s.slice, s.seq = p.slice, p.seq
+	}
+}
+
+func (p *priorityRoundRobin) pick(ready []*task) int {
+	p.charge()
+	best := 0
+	for i, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		switch {
+		case !ok:
+			p.seq++
+			s = &prrTask{slice: p.slice, seq: p.seq, blocked: t.blockedFor}
+			p.tasks[t.ProcessID] = s
+		case t.blockedFor > s.blocked:
+			p.seq++
+			s.slice, s.seq, s.blocked = p.slice, p.seq, t.blockedFor
+		}
+		b := ready[best]
+		if t.rank < b.rank || t.rank == b.rank && s.seq < p.tasks[b.ProcessID].seq {
+			best = i
+		}
+	}
+	p.last, p.lastRan = ready[best], ready[best].ran
+
+	return best
+}
+
+// quantum is a single unit, so that a task of higher priority preempts the
+// running one as soon as it is ready; slices are kept by charge.
+func (*priorityRoundRobin) quantum(*task) int64 { return 1 }
+
+// Priority with round-robin among equal priorities
+func PriorityRRSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, priorityRR(processes, options{}), display{})
+}
+
+func priorityRR(processes []Process, opts options) Result {
+	return simulate(processes, &priorityRoundRobin{slice: 5}, opts)
+}
+
+//endregion
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_priorityRR(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		csv   string
+		slice int64
+		want  []TimeSlice
+	}{
+		{
+			// Silberschatz, Galvin and Gagne, Operating System Concepts,
+			// 10th ed., 5.3.4, with a time quantum of 2
+			name:  "textbook",
+			csv:   "1,4,0,3\n2,5,0,2\n3,8,0,2\n4,7,0,1\n5,3,0,3\n",
+			slice: 2,
+			want: []TimeSlice{
+				{PID: 4, Start: 0, Stop: 7},
+				{PID: 2, Start: 7, Stop: 9}, {PID: 3, Start: 9, Stop: 11},
+				{PID: 2, Start: 11, Stop: 13}, {PID: 3, Start: 13, Stop: 15},
+				{PID: 2, Start: 15, Stop: 16}, {PID: 3, Start: 16, Stop: 20},
+				{PID: 1, Start: 20, Stop: 22}, {PID: 5, Start: 22, Stop: 24},
+				{PID: 1, Start: 24, Stop: 26}, {PID: 5, Start: 26, Stop: 27},
+			},
+		},
+		{
+			// P1 keeps its turn and the rest of its slice when P3 preempts it
+			name:  "preempted",
+			csv:   "1,6,0,2\n2,6,0,2\n3,1,2,1\n",
+			slice: 5,
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 3, Start: 2, Stop: 3},
+				{PID: 1, Start: 3, Stop: 6}, {PID: 2, Start: 6, Stop: 11},
+				{PID: 1, Start: 11, Stop: 12}, {PID: 2, Start: 12, Stop: 13},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := simulate(processes, &priorityRoundRobin{slice: tt.slice}, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("simulate() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+//region Ready queues
+
+type (
+	// readyQueue holds the tasks ready to run. The queue discipline decides
+	// which tasks are offered to the policy, and in what order; the policy
+	// picks one of them. The same policy can so be tried over different
+	// queue disciplines.
+	readyQueue interface {
+		push(t *task)
+		// candidates returns the tasks the policy may pick from, in queue
+		// order. It is never empty unless the queue is.
+		candidates() []*task
+		// remove takes t, one of the candidates, off the queue.
+		remove(t *task)
+		// filter removes every task for which keep returns false.
+		filter(keep func(t *task) bool)
+		len() int
+	}
+
+	// fifoQueue offers every ready task, in the order they became ready.
+	fifoQueue struct {
+		tasks []*task
+	}
+
+	// heapQueue is a min-heap by key that offers only the task with the
+	// smallest key, the earliest ready of those tied.
+	heapQueue struct {
+		key   func(t *task) int64
+		items heapItems
+		seq   int64
+	}
+	heapItems []heapItem
+	heapItem  struct {
+		t   *task
+		key int64
+		seq int64
+	}
+
+	// levelQueue keeps a FIFO level per priority and offers the tasks in the
+	// level with the lowest rank, the highest priority, like a multi-level queue
+	// without feedback.
+	levelQueue struct {
+		levels map[int64]*fifoQueue
+		n      int
+	}
+)
+
+// queues are the ready-queue disciplines that can be chosen with -queue.
+var queues = []struct {
+	key, name string
+	make      func() readyQueue
+}{
+	{"fifo", "FIFO in order of becoming ready", func() readyQueue { return &fifoQueue{} }},
+	{"burst-heap", "Min-heap by burst", func() readyQueue {
+		return &heapQueue{key: func(t *task) int64 { return t.BurstDuration }}
+	}},
+	{"priority-heap", "Min-heap by priority", func() readyQueue {
+		return &heapQueue{key: func(t *task) int64 { return t.rank }}
+	}},
+	{"levels", "Multi-level by priority", func() readyQueue { return &levelQueue{levels: map[int64]*fifoQueue{}} }},
+}
+
+// queueByKey returns the constructor of the ready queue registered under key.
+func queueByKey(key string) (func() readyQueue, error) {
+	for _, q := range queues {
+		if q.key == key {
+			return q.make, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown ready queue %q", ErrInvalidArgs, key)
+}
+
+func (q *fifoQueue) push(t *task)        { q.tasks = append(q.tasks, t) }
+func (q *fifoQueue) candidates() []*task { return q.tasks }
+func (q *fifoQueue) len() int            { return len(q.tasks) }
+
+func (q *fifoQueue) remove(t *task) {
+	for i := range q.tasks {
+		if q.tasks[i] == t {
+			q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *fifoQueue) filter(keep func(t *task) bool) {
+	kept := q.tasks[:0]
+	for _, t := range q.tasks {
+		if keep(t) {
+			kept = append(kept, t)
+		}
+	}
+	q.tasks = kept
+}
+
+func (q *heapQueue) push(t *task) {
+	heap.Push(&q.items, heapItem{t: t, key: q.key(t), seq: q.seq})
+	q.seq++
+}
+
+func (q *heapQueue) candidates() []*task {
+	if len(q.items) == 0 {
+		return nil
+	}
+
+	return []*task{q.items[0].t}
+}
+
+func (q *heapQueue) remove(t *task) {
+	for i := range q.items {
+		if q.items[i].t == t {
+			heap.Remove(&q.items, i)
+			return
+		}
+	}
+}
+
+func (q *heapQueue) filter(keep func(t *task) bool) {
+	kept := q.items[:0]
+	for _, item := range q.items {
+		if keep(item.t) {
+			kept = append(kept, item)
+		}
+	}
+	q.items = kept
+	heap.Init(&q.items)
+}
+
+func (q *heapQueue) len() int { return len(q.items) }
+
+func (h heapItems) Len() int { return len(h) }
+func (h heapItems) Less(i, j int) bool {
+	if h[i].key != h[j].key {
+		return h[i].key < h[j].key
+	}
+
+	return h[i].seq < h[j].seq
+}
+func (h heapItems) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *heapItems) Push(x any)   { *h = append(*h, x.(heapItem)) }
+func (h *heapItems) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return item
+}
+
+func (q *levelQueue) push(t *task) {
+	level, ok := q.levels[t.rank]
+	if !ok {
+		level = &fifoQueue{}
+		q.levels[t.rank] = level
+	}
+	level.push(t)
+	q.n++
+}
+
+func (q *levelQueue) candidates() []*task {
+	var top *fifoQueue
+	for rank, level := range q.levels {
+		if level.len() > 0 && (top == nil || rank < top.tasks[0].rank) {
+			top = level
+		}
+	}
+	if top == nil {
+		return nil
+	}
+
+	return top.candidates()
+}
+
+func (q *levelQueue) remove(t *task) {
+	if level, ok := q.levels[t.rank]; ok {
+		before := level.len()
+		level.remove(t)
+		q.n -= before - level.len()
+	}
+}
+
+func (q *levelQueue) filter(keep func(t *task) bool) {
+	q.n = 0
+	for _, level := range q.levels {
+		level.filter(keep)
+		q.n += level.len()
+	}
+}
+
+func (q *levelQueue) len() int { return q.n }
+
+//endregion
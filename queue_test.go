@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_readyQueues(t *testing.T) {
+	t.Parallel()
+	tasks := []*task{
+		{Process: Process{ProcessID: 1, BurstDuration: 9, Priority: 2}, rank: 2},
+		{Process: Process{ProcessID: 2, BurstDuration: 3, Priority: 1}, rank: 1},
+		{Process: Process{ProcessID: 3, BurstDuration: 5, Priority: 2}, rank: 2},
+		{Process: Process{ProcessID: 4, BurstDuration: 3, Priority: 3}, rank: 3},
+	}
+	tests := []struct {
+		key string
+		// want is the order the tasks leave the queue when the first
+		// candidate is always taken.
+		want []int64
+	}{
+		{"fifo", []int64{1, 2, 3, 4}},
+		{"burst-heap", []int64{2, 4, 3, 1}},
+		{"priority-heap", []int64{2, 1, 3, 4}},
+		{"levels", []int64{2, 1, 3, 4}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+			newQueue, err := queueByKey(tt.key)
+			if err != nil {
+				t.Fatalf("queueByKey() error = %v", err)
+			}
+			q := newQueue()
+			for _, task := range tasks {
+				q.push(task)
+			}
+			var got []int64
+			for q.len() > 0 {
+				next := q.candidates()[0]
+				q.remove(next)
+				got = append(got, next.ProcessID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queue order = %v, want %v", got, tt.want)
+			}
+
+			// Filtering drops tasks wherever they are queued
+			for _, task := range tasks {
+				q.push(task)
+			}
+			q.filter(func(t *task) bool { return t.ProcessID != 2 })
+			if q.len() != 3 {
+				t.Errorf("len() after filter = %v, want 3", q.len())
+			}
+			for _, c := range q.candidates() {
+				if c.ProcessID == 2 {
+					t.Errorf("candidates() after filter = %v, still has 2", c)
+				}
+			}
+		})
+	}
+
+	if _, err := queueByKey("stack"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("queueByKey() error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func Test_simulateQueue(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 6, Priority: 2},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2, Priority: 1},
+	}
+	heap, err := queueByKey("burst-heap")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// First-come over a heap by burst runs the shortest job first
+	got := fcfs(processes, options{queue: heap})
+	want := sjf(processes, options{})
+	if !reflect.DeepEqual(got.Gantt, want.Gantt) {
+		t.Errorf("fcfs() over burst-heap = %v, want %v", got.Gantt, want.Gantt)
+	}
+}
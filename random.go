@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+	"math/rand"
+)
+
+//region Random dispatch
+
+// randomDispatch runs a ready task drawn uniformly at random for a quantum at
+// a time, as a baseline that knows nothing about the tasks: an algorithm
+// worth its keep should beat it. The draws are seeded, so a run is
+// reproducible.
+type randomDispatch struct {
+	seed   int64
+	random *rand.Rand
+}
+
+func (p *randomDispatch) reset() {
+	p.random = rand.New(rand.NewSource(p.seed))
+}
+
+func (p *randomDispatch) pick(ready []*task) int {
+	return p.random.Intn(len(ready))
+}
+
+func (*randomDispatch) quantum(*task) int64 { return shareQuantum }
+
+// RandomSchedule outputs the schedule of random dispatch seeded with 1.
+func RandomSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, randomScheduling(processes, options{randomSeed: 1}), display{})
+}
+
+// Random dispatch, seeded with opts.randomSeed
+func randomScheduling(processes []Process, opts options) Result {
+	return simulate(processes, &randomDispatch{seed: opts.randomSeed}, opts)
+}
+
+//endregion
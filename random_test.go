@@ -0,0 +1,45 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_randomScheduling(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,5000,0,0\n2,5000,0,0\n3,5000,0,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := randomScheduling(processes, options{randomSeed: 1})
+
+	// The same seed gives the same schedule
+	if again := randomScheduling(processes, options{randomSeed: 1}); !reflect.DeepEqual(again.Gantt, r.Gantt) {
+		t.Error("randomScheduling() differs between runs with the same seed")
+	}
+	if other := randomScheduling(processes, options{randomSeed: 2}); reflect.DeepEqual(other.Gantt, r.Gantt) {
+		t.Error("randomScheduling() is the same with seeds 1 and 2")
+	}
+
+	// While all three contend, each gets about a third of the CPU, a quantum
+	// at a time
+	ran := make(map[int64]int64)
+	for _, s := range r.Gantt {
+		if s.Start >= 6000 {
+			break
+		}
+		if (s.Stop-s.Start)%shareQuantum != 0 {
+			t.Errorf("slice %v is not a whole number of quanta", s)
+		}
+		ran[s.PID] += min(s.Stop, 6000) - s.Start
+	}
+	if len(ran) != 3 {
+		t.Errorf("%d processes ran in the first 6000 units, want 3", len(ran))
+	}
+	for pid, d := range ran {
+		if d < 1800 || d > 2200 {
+			t.Errorf("P%d ran %d of the first 6000 units, want about 2000", pid, d)
+		}
+	}
+}
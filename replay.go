@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+//region Replay
+
+// replayRun is the event log of one algorithm's run.
+type replayRun struct {
+	key    string
+	events []engineEvent
+}
+
+// replayCommand reconstructs every run in an event log written by
+// -event-log, or by any tool writing the same format, and renders the
+// results as text or as an HTML report.
+func replayCommand(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	format := fs.String("format", "text", "how to render the results: text or html")
+	precision := fs.Int("precision", defaultPrecision, "decimal places of averages and throughput")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: must give an event log to replay", ErrInvalidArgs)
+	}
+	if *format != "text" && *format != "html" {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidArgs, *format)
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%w: error opening event log", err)
+	}
+	defer func() { _ = f.Close() }()
+	runs, err := readEventLog(f)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(runs))
+	results := make([]Result, len(runs))
+	for i, run := range runs {
+		names[i] = run.key
+		for _, alg := range algorithms {
+			if alg.key == run.key {
+				names[i] = alg.name
+			}
+		}
+		if names[i] == "" {
+			names[i] = "Replay"
+		}
+		results[i] = replayResult(run.events)
+	}
+	d := display{precision: *precision}
+	if *format == "html" {
+		return writeReportResults(w, "Scheduling report", names, results, d)
+	}
+	for i, r := range results {
+		outputResult(w, names[i], r, d)
+	}
+
+	return nil
+}
+
+// readEventLog reads an event log, one JSON event per line, into the runs
+// of each algorithm in order of their first event.
+func readEventLog(r io.Reader) ([]replayRun, error) {
+	var runs []replayRun
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ev engineEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("%w: event log line %d: %w", ErrParse, line, err)
+		}
+		i, ok := index[ev.Algorithm]
+		if !ok {
+			i = len(runs)
+			index[ev.Algorithm] = i
+			runs = append(runs, replayRun{key: ev.Algorithm})
+		}
+		runs[i].events = append(runs[i].events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading event log: %w", ErrParse, err)
+	}
+
+	return runs, nil
+}
+
+// replayResult reconstructs the result of a run from its events alone: the
+// Gantt chart from dispatches, interrupts and stops, the schedule table from
+// arrivals, blocking and exits, and the ready queue depth from all of them.
+// The log does not record priorities, so they show as 0, nor the burst of a
+// process killed before it ever ran.
+func replayResult(events []engineEvent) Result {
+	clock := &instantClock{}
+	e := &engine{clock: clock, ready: &fifoQueue{}, gantt: make([]TimeSlice, 0), byID: make(map[int64]*task)}
+	get := func(pid int64) *task {
+		t, ok := e.byID[pid]
+		if !ok {
+			t = &task{Process: Process{ProcessID: pid}, firstRun: -1}
+			e.byID[pid] = t
+		}
+		return t
+	}
+	var running *task
+	var since int64
+	for _, ev := range events {
+		t := get(ev.PID)
+		clock.advance(max(clock.now(), ev.Clock))
+		switch ev.Type {
+		case "arrive":
+			t.ArrivalTime, t.readyAt = ev.Clock, ev.Clock
+			e.tasks = append(e.tasks, t)
+			e.enqueue(t, ev.Clock)
+		case "dispatch":
+			e.ready.remove(t)
+			e.sampleDepth(ev.Clock)
+			if t.firstRun < 0 {
+				t.firstRun = ev.Clock
+			}
+			t.BurstDuration = max(t.BurstDuration, t.ran+ev.Details.Remaining)
+			running, since = t, ev.Clock
+		case "interrupt":
+			// The task runs on once the handler has taken its unit
+			running, since = t, ev.Clock+1
+		case "stop":
+			if running == t {
+				e.record(t.ProcessID, since, ev.Clock)
+				t.ran += ev.Clock - since
+				running = nil
+			}
+			clock.advance(ev.Clock)
+			e.ended(t, ev.Details.Reason)
+			switch ev.Details.Reason {
+			case slicePreempted, sliceYield, sliceBurst:
+				e.enqueue(t, ev.Clock)
+			}
+		case "block":
+			t.blocked = waitingChildren
+			if ev.Details.Reason == "signal" {
+				t.blocked = awaitingSignal
+			}
+			t.blockedSince = ev.Clock
+		case "wake":
+			t.blocked = notBlocked
+			t.blockedFor += ev.Clock - t.blockedSince
+			e.enqueue(t, ev.Clock)
+		case "exit", "killed", "dropped":
+			if t.blocked != notBlocked {
+				t.blockedFor += ev.Clock - t.blockedSince
+				t.blocked = notBlocked
+			}
+			e.ready.remove(t)
+			e.sampleDepth(ev.Clock)
+			t.completion, t.finished = ev.Clock, true
+			t.killed = ev.Type != "exit"
+			t.dropped = ev.Type == "dropped"
+			t.BurstDuration = max(t.BurstDuration, t.ran)
+		}
+	}
+
+	// Whatever never finished was blocked for good
+	for _, t := range e.tasks {
+		if !t.finished {
+			t.stuck = true
+			t.blockedFor += clock.now() - t.blockedSince
+			t.completion = clock.now()
+		}
+		t.remaining = t.BurstDuration - t.ran
+	}
+	sort.SliceStable(e.tasks, func(i, j int) bool {
+		return e.tasks[i].ArrivalTime < e.tasks[j].ArrivalTime
+	})
+
+	return e.result()
+}
+
+//endregion
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_replayResult(t *testing.T) {
+	t.Parallel()
+	// Preemption, blocking on a signal and a spawned child waited on
+	processes, err := loadProcesses(strings.NewReader("1,6,0,0,await=2\n2,3,1,0,signal=1@2\n3,4,2,0,wait=1\n4,2,0,0,parent=3,spawn=1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log bytes.Buffer
+	if err := writeEventLog(&log, processes, options{}); err != nil {
+		t.Fatal(err)
+	}
+	runs, err := readEventLog(&log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != len(algorithms) {
+		t.Fatalf("readEventLog() read %d runs, want %d", len(runs), len(algorithms))
+	}
+	for i, alg := range algorithms {
+		want := alg.run(processes, options{})
+		got := replayResult(runs[i].events)
+		if runs[i].key != alg.key {
+			t.Errorf("run %d is %q, want %q", i, runs[i].key, alg.key)
+		}
+		if !reflect.DeepEqual(got.Gantt, want.Gantt) || !reflect.DeepEqual(got.reasons, want.reasons) {
+			t.Errorf("%s: replayed gantt = %v %v, want %v %v", alg.key, got.Gantt, got.reasons, want.Gantt, want.reasons)
+		}
+		if !reflect.DeepEqual(got.Schedule, want.Schedule) {
+			t.Errorf("%s: replayed schedule = %v, want %v", alg.key, got.Schedule, want.Schedule)
+		}
+		if got.AveWait != want.AveWait || got.AveTurnaround != want.AveTurnaround || got.AveThroughput != want.AveThroughput {
+			t.Errorf("%s: replayed averages differ", alg.key)
+		}
+		if !reflect.DeepEqual(got.depth, want.depth) {
+			t.Errorf("%s: replayed depth = %v, want %v", alg.key, got.depth, want.depth)
+		}
+	}
+}
+
+func Test_replayCommand(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,2,0\n2,1,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := writeEventLogFile(name, processes, options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var text, want bytes.Buffer
+	if err := replayCommand(&text, []string{name}); err != nil {
+		t.Fatal(err)
+	}
+	FCFSSchedule(&want, "First-come, first-serve", processes)
+	if !strings.HasPrefix(text.String(), want.String()) {
+		t.Errorf("replay =\n%s\nwant it to start with\n%s", text.String(), want.String())
+	}
+
+	var report bytes.Buffer
+	if err := replayCommand(&report, []string{"-format", "html", name}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report.String(), "<h2>Shortest-job-first</h2>") {
+		t.Errorf("replay -format html =\n%s", report.String())
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.jsonl")
+	if err := os.WriteFile(bad, []byte("{\"type\":\"arrive\"}\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := replayCommand(&text, []string{bad}); !errors.Is(err, ErrParse) {
+		t.Errorf("replay of a malformed log error = %v, want %v", err, ErrParse)
+	}
+	if err := replayCommand(&text, []string{"-format", "pdf", name}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("replay -format pdf error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
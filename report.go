@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+)
+
+//region HTML report
+
+const (
+	reportWidth = 800
+	reportBand  = 40
+)
+
+// reportOverlays are the series that can be toggled on over every Gantt
+// timeline in the HTML report, by CSS class.
+var reportOverlays = []struct {
+	class, label, color string
+}{
+	{"depth", "Ready queue depth", "#b22222"},
+}
+
+// writeReport writes a self-contained HTML report with every algorithm's
+// Gantt timeline and averages. Overlays, such as the ready queue depth, are
+// drawn over the timelines and toggled with the checkboxes at the top.
+func writeReport(w io.Writer, title string, processes []Process, opts options, d display) error {
+	names := make([]string, len(algorithms))
+	results := make([]Result, len(algorithms))
+	for i, alg := range algorithms {
+		names[i] = alg.name
+		results[i] = alg.run(processes, opts)
+	}
+
+	return writeReportResults(w, title, names, results, d)
+}
+
+// writeReportResults writes the HTML report of results, each under the name
+// of the algorithm that produced it.
+func writeReportResults(w io.Writer, title string, names []string, results []Result, d display) error {
+	end := int64(0)
+	peak := 0
+	for _, r := range results {
+		if n := len(r.Gantt); n > 0 && r.Gantt[n-1].Stop > end {
+			end = r.Gantt[n-1].Stop
+		}
+		for _, s := range r.depth {
+			peak = max(peak, s.depth)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+svg text { font-size: 11px; }
+`, html.EscapeString(title))
+	for _, o := range reportOverlays {
+		_, _ = fmt.Fprintf(bw, "body.hide-%s .%s { display: none; }\n", o.class, o.class)
+	}
+	_, _ = fmt.Fprintf(bw, "</style>\n</head>\n<body>\n<h1>%s</h1>\n<p>Overlays:", html.EscapeString(title))
+	for _, o := range reportOverlays {
+		_, _ = fmt.Fprintf(bw, `
+<label><input type="checkbox" checked onchange="document.body.classList.toggle('hide-%s', !this.checked)"> <span style="color: %s">%s</span></label>`,
+			o.class, o.color, html.EscapeString(o.label))
+	}
+	_, _ = fmt.Fprintln(bw, "</p>")
+
+	for i, r := range results {
+		_, _ = fmt.Fprintf(bw, "<h2>%s</h2>\n<p>Average wait %s, average turnaround %s, throughput %s</p>\n",
+			html.EscapeString(names[i]), d.format(r.AveWait), d.format(r.AveTurnaround), d.throughput(r.AveThroughput))
+		writeReportTimeline(bw, r, end, peak)
+	}
+	_, _ = fmt.Fprintln(bw, "</body>\n</html>")
+
+	return bw.Flush()
+}
+
+// writeReportTimeline draws one Gantt timeline as inline SVG, scaled so that
+// every timeline in the report shares the time axis [0, end].
+func writeReportTimeline(w io.Writer, r Result, end int64, peak int) {
+	scale := 0.0
+	if end > 0 {
+		scale = float64(reportWidth) / float64(end)
+	}
+	x := func(t int64) float64 { return float64(t) * scale }
+	_, _ = fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`+"\n", reportWidth+40, reportBand+20)
+	for _, s := range r.Gantt {
+		// Reserved IDs are gray, processes a hue of their own
+		fill := "#ddd"
+		if s.PID > 0 {
+			fill = fmt.Sprintf("hsl(%d,60%%,75%%)", (s.PID*47)%360)
+		}
+		_, _ = fmt.Fprintf(w, `<rect x="%.2f" y="0" width="%.2f" height="%d" fill="%s" stroke="#555"><title>%s: %d-%d</title></rect>`+"\n",
+			x(s.Start), x(s.Stop)-x(s.Start), reportBand, fill, pidLabel(s.PID), s.Start, s.Stop)
+		if x(s.Stop)-x(s.Start) >= 14 {
+			_, _ = fmt.Fprintf(w, `<text x="%.2f" y="%d" text-anchor="middle">%s</text>`+"\n",
+				(x(s.Start)+x(s.Stop))/2, reportBand/2+4, pidLabel(s.PID))
+		}
+	}
+
+	// Ready queue depth as a step line, full height being the deepest queue
+	if peak > 0 && len(r.depth) > 0 {
+		y := func(depth int) float64 { return float64(reportBand) * (1 - float64(depth)/float64(peak)) }
+		var points strings.Builder
+		for i, s := range r.depth {
+			if i > 0 {
+				_, _ = fmt.Fprintf(&points, "%.2f,%.2f ", x(s.time), y(r.depth[i-1].depth))
+			}
+			_, _ = fmt.Fprintf(&points, "%.2f,%.2f ", x(s.time), y(s.depth))
+		}
+		_, _ = fmt.Fprintf(&points, "%.2f,%.2f", x(end), y(r.depth[len(r.depth)-1].depth))
+		_, _ = fmt.Fprintf(w, `<polyline class="depth" points="%s" fill="none" stroke="%s" stroke-width="2"><title>ready queue depth, at most %d</title></polyline>`+"\n",
+			points.String(), reportOverlays[0].color, peak)
+	}
+
+	_, _ = fmt.Fprintf(w, `<text x="0" y="%d">0</text>`+"\n", reportBand+14)
+	_, _ = fmt.Fprintf(w, `<text x="%d" y="%d" text-anchor="end">%d</text>`+"\n", reportWidth, reportBand+14, end)
+	_, _ = fmt.Fprintln(w, "</svg>")
+}
+
+func writeReportFile(name string, processes []Process, opts options, d display) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating report", err)
+	}
+	if err := writeReport(f, "Scheduling report", processes, opts, d); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: error writing report", err)
+	}
+
+	return f.Close()
+}
+
+//endregion
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_writeReport(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	var b bytes.Buffer
+	if err := writeReport(&b, "Convoy <test>", processes, options{}, display{}); err != nil {
+		t.Fatalf("writeReport() error = %v", err)
+	}
+	report := b.String()
+	for _, want := range []string{
+		"<title>Convoy &lt;test&gt;</title>",
+		"toggle('hide-depth', !this.checked)",
+		"body.hide-depth .depth { display: none; }",
+		`<title>1: 0-10</title>`,
+		`<title>2: 10-12</title>`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("writeReport() missing %q", want)
+		}
+	}
+	if got := strings.Count(report, `<polyline class="depth"`); got != len(algorithms) {
+		t.Errorf("writeReport() has %d depth overlays, want %d", got, len(algorithms))
+	}
+	if got := strings.Count(report, "<h2>"); got != len(algorithms) {
+		t.Errorf("writeReport() has %d timelines, want %d", got, len(algorithms))
+	}
+}
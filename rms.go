@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Rate-monotonic
+
+// expandPeriodic expands every periodic process into its jobs: one released
+// every period from its arrival, jobs of them or as many as are released
+// before horizon. The first job keeps the process's ID and later ones are
+// numbered after the highest ID. Every job is due by its next release unless
+// it has a deadline of its own, and the jobs are grouped as a task named
+// after the process unless it names one.
+func expandPeriodic(processes []Process, horizon int64) ([]Process, error) {
+	var next int64
+	for _, p := range processes {
+		next = max(next, p.ProcessID)
+	}
+
+	var expanded []Process
+	for _, p := range processes {
+		if p.Period == 0 {
+			expanded = append(expanded, p)
+			continue
+		}
+		jobs := p.Jobs
+		if jobs == 0 {
+			if horizon <= 0 {
+				return nil, fmt.Errorf("%w: periodic process %d needs jobs= or -horizon", ErrInvalidArgs, p.ProcessID)
+			}
+			jobs = max((horizon-p.ArrivalTime+p.Period-1)/p.Period, 0)
+		}
+		if p.Task == "" {
+			p.Task = fmt.Sprint(p.ProcessID)
+		}
+		if p.Deadline == 0 {
+			p.Deadline = p.Period
+		}
+		p.Jobs = 0
+		for k := range jobs {
+			job := p
+			job.ArrivalTime += k * p.Period
+			if k > 0 {
+				next++
+				job.ProcessID = next
+			}
+			expanded = append(expanded, job)
+		}
+	}
+
+	return expanded, nil
+}
+
+// rmsTask is a periodic task as the admission test sees it.
+type rmsTask struct {
+	name              string
+	execution, period int64
+	admitted          bool
+}
+
+// utilization is the share of the CPU the task needs.
+func (t rmsTask) utilization() float64 {
+	return float64(t.execution) / float64(t.period)
+}
+
+// rmsBound is Liu and Layland's utilization bound for n tasks, below which
+// rate-monotonic scheduling meets every deadline.
+func rmsBound(n int) float64 {
+	return float64(n) * (math.Pow(2, 1/float64(n)) - 1)
+}
+
+// rmsAdmission runs the utilization-bound admission test over the periodic
+// tasks of processes in order of appearance, admitting each that keeps the
+// utilization of the admitted tasks within the bound for their number.
+func rmsAdmission(processes []Process) []rmsTask {
+	var tasks []rmsTask
+	seen := make(map[string]bool)
+	var used float64
+	for _, p := range processes {
+		if p.Period == 0 || seen[p.Task] {
+			continue
+		}
+		seen[p.Task] = true
+		t := rmsTask{name: p.Task, execution: p.BurstDuration, period: p.Period}
+		admitted := 0
+		for _, a := range tasks {
+			if a.admitted {
+				admitted++
+			}
+		}
+		if used+t.utilization() <= rmsBound(admitted+1) {
+			t.admitted = true
+			used += t.utilization()
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks
+}
+
+// rateMonotonic dispatches the ready job of the task with the shortest
+// period, jobs of no periodic task last, breaking ties by arrival and then
+// ID. It reconsiders its choice every unit so that a job of a task with a
+// shorter period preempts the running one. Jobs of tasks that failed the
+// admission test are dropped as they arrive.
+type rateMonotonic struct {
+	rejected map[string]bool
+}
+
+func (rateMonotonic) pick(ready []*task) int {
+	period := func(t *task) int64 {
+		if t.Period == 0 {
+			return math.MaxInt64
+		}
+		return t.Period
+	}
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		switch p, bp := period(t), period(b); {
+		case p != bp:
+			if p < bp {
+				best = i
+			}
+		case t.ArrivalTime != b.ArrivalTime:
+			if t.ArrivalTime < b.ArrivalTime {
+				best = i
+			}
+		case t.ProcessID < b.ProcessID:
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (rateMonotonic) quantum(*task) int64 { return 1 }
+
+func (p rateMonotonic) dropAt(t *task) (int64, bool) {
+	if t.Period == 0 || !p.rejected[t.Task] {
+		return 0, false
+	}
+
+	return t.ArrivalTime, true
+}
+
+// Rate-monotonic
+func RateMonotonicSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, rms(processes, options{}), display{})
+}
+
+func rms(processes []Process, opts options) Result {
+	rejected := make(map[string]bool)
+	for _, t := range rmsAdmission(processes) {
+		if !t.admitted {
+			rejected[t.name] = true
+		}
+	}
+
+	return simulate(processes, rateMonotonic{rejected: rejected}, opts)
+}
+
+// outputAdmission shows the utilization-bound admission test of the periodic
+// tasks.
+func outputAdmission(w io.Writer, processes []Process) {
+	tasks := rmsAdmission(processes)
+
+	outputTitle(w, "Rate-monotonic admission")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Task", "Execution", "Period", "Utilization", "Admitted"})
+	var used float64
+	admitted := 0
+	for _, t := range tasks {
+		verdict := "no"
+		if t.admitted {
+			verdict = "yes"
+			used += t.utilization()
+			admitted++
+		}
+		table.Append([]string{
+			t.name,
+			fmt.Sprint(t.execution),
+			fmt.Sprint(t.period),
+			fmt.Sprintf("%.3f", t.utilization()),
+			verdict,
+		})
+	}
+	table.Render()
+	if admitted > 0 {
+		_, _ = fmt.Fprintf(w, "Admitted %d of %d tasks, using %.3f of the CPU against a bound of %.3f.\n", admitted, len(tasks), used, rmsBound(admitted))
+	}
+}
+
+//endregion
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_expandPeriodic(t *testing.T) {
+	t.Parallel()
+	type job struct {
+		id, arrival, deadline int64
+		task                  string
+	}
+	tests := []struct {
+		name    string
+		csv     string
+		horizon int64
+		want    []job
+		wantErr error
+	}{
+		{
+			name: "jobs",
+			csv:  "1,2,0,0,period=5,jobs=3\n2,1,1\n",
+			want: []job{{1, 0, 5, "1"}, {3, 5, 5, "1"}, {4, 10, 5, "1"}, {2, 1, 0, ""}},
+		},
+		{
+			name:    "horizon",
+			csv:     "1,2,1,0,period=5,deadline=3,task=sensor\n",
+			horizon: 12,
+			want:    []job{{1, 1, 3, "sensor"}, {2, 6, 3, "sensor"}, {3, 11, 3, "sensor"}},
+		},
+		{
+			name:    "no jobs or horizon",
+			csv:     "1,2,0,0,period=5\n",
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expanded, err := expandPeriodic(processes, tt.horizon)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expandPeriodic() error = %v, want %v", err, tt.wantErr)
+			}
+			var got []job
+			for _, p := range expanded {
+				got = append(got, job{p.ProcessID, p.ArrivalTime, p.Deadline, p.Task})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandPeriodic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_rms(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		csv          string
+		horizon      int64
+		want         []TimeSlice
+		wantDropped  map[int64]bool
+		wantAdmitted []bool
+	}{
+		{
+			// 1/4 + 2/6 is within the bound for two tasks; the shorter
+			// period runs first
+			name:         "admitted",
+			csv:          "1,1,0,0,period=4\n2,2,0,0,period=6\n",
+			horizon:      12,
+			want:         []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 3}, {PID: 3, Start: 4, Stop: 5}, {PID: 5, Start: 6, Stop: 8}, {PID: 4, Start: 8, Stop: 9}},
+			wantAdmitted: []bool{true, true},
+		},
+		{
+			// 3/4 + 2/5 is over the bound, so the second task's jobs are
+			// dropped as they arrive
+			name:         "rejected",
+			csv:          "1,3,0,0,period=4,jobs=2\n2,2,0,0,period=5,jobs=2\n",
+			want:         []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 3, Start: 4, Stop: 7}},
+			wantDropped:  map[int64]bool{2: true, 4: true},
+			wantAdmitted: []bool{true, false},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if processes, err = expandPeriodic(processes, tt.horizon); err != nil {
+				t.Fatal(err)
+			}
+			var admitted []bool
+			for _, task := range rmsAdmission(processes) {
+				admitted = append(admitted, task.admitted)
+			}
+			if !reflect.DeepEqual(admitted, tt.wantAdmitted) {
+				t.Errorf("rmsAdmission() admitted = %v, want %v", admitted, tt.wantAdmitted)
+			}
+			r := rms(processes, options{})
+			if !reflect.DeepEqual(r.Gantt, tt.want) {
+				t.Errorf("rms() gantt = %v, want %v", r.Gantt, tt.want)
+			}
+			if !reflect.DeepEqual(r.dropped, tt.wantDropped) {
+				t.Errorf("rms() dropped = %v, want %v", r.dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func Test_outputAdmission(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,3,0,0,period=4,jobs=2\n2,2,0,0,period=5,jobs=2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processes, err = expandPeriodic(processes, 0); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outputAdmission(&out, processes)
+	for _, want := range []string{
+		"|    2 |         2 |      5 |       0.400 | no       |",
+		"Admitted 1 of 2 tasks, using 0.750 of the CPU against a bound of 1.000.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputAdmission() is missing %q in\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Saved runs
+
+type (
+	// runRecord is a run saved with -json: how it was produced and the
+	// summary metrics of every algorithm.
+	runRecord struct {
+		Manifest manifest    `json:"manifest"`
+		Results  []runResult `json:"results"`
+		// Precision and Rounding say how the formatted metrics were rounded
+		// from the raw ones.
+		Precision int    `json:"precision"`
+		Rounding  string `json:"rounding"`
+		// PeakMemoryMiB is the memory the simulator had obtained from the OS
+		// once every algorithm ran, an upper bound on its peak RSS.
+		PeakMemoryMiB float64 `json:"peak_memory_mib"`
+	}
+	runResult struct {
+		Key     string             `json:"key"`
+		Name    string             `json:"name"`
+		Metrics map[string]float64 `json:"metrics"`
+		// Formatted holds the metrics as shown, rounded to the precision.
+		Formatted map[string]string `json:"formatted"`
+	}
+)
+
+// runMetrics are the metrics saved for every algorithm, by name as in
+// assertions. Each algorithm's run also saves allocMetric.
+var runMetrics = []string{
+	"avg_wait", "avg_turnaround", "throughput", "switches",
+	"p50_response", "p99_response", "max_response", "p99_turnaround", "max_turnaround",
+	"events",
+}
+
+// allocMetric is the memory the simulator allocated for an algorithm's run.
+const allocMetric = "alloc_mib"
+
+func newRunRecord(m manifest, processes []Process, opts options, d display) runRecord {
+	rec := runRecord{Manifest: m, Precision: d.places(), Rounding: "nearest, ties to even"}
+	var mem runtime.MemStats
+	for _, alg := range algorithms {
+		runtime.ReadMemStats(&mem)
+		allocated := mem.TotalAlloc
+		r := alg.run(processes, opts)
+		runtime.ReadMemStats(&mem)
+		res := runResult{Key: alg.key, Name: alg.name, Metrics: make(map[string]float64, len(runMetrics)+1)}
+		res.Metrics[allocMetric] = float64(mem.TotalAlloc-allocated) / (1 << 20)
+		for _, metric := range runMetrics {
+			res.Metrics[metric], _ = metricValue(metric, r)
+		}
+		res.Formatted = make(map[string]string, len(res.Metrics))
+		for metric, v := range res.Metrics {
+			res.Formatted[metric] = d.format(v)
+		}
+		rec.Results = append(rec.Results, res)
+	}
+	runtime.ReadMemStats(&mem)
+	rec.PeakMemoryMiB = float64(mem.Sys) / (1 << 20)
+
+	return rec
+}
+
+func writeRunFile(name string, rec runRecord) error {
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(name, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("%w: error writing run", err)
+	}
+
+	return nil
+}
+
+// aggregateCommand merges runs saved with -json, from different workloads,
+// seeds or options, into per-algorithm statistics and, with -csv, a tidy
+// dataset with one row per run, algorithm and metric.
+func aggregateCommand(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("aggregate", flag.ContinueOnError)
+	csvFile := fs.String("csv", "", "also write every run's metrics as tidy CSV to this file")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	var names []string
+	for _, arg := range fs.Args() {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		if len(matches) == 0 {
+			matches = []string{arg}
+		}
+		names = append(names, matches...)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%w: aggregate takes saved run files", ErrInvalidArgs)
+	}
+
+	runs := make([]runRecord, len(names))
+	for i, name := range names {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("%w: error reading run", err)
+		}
+		if err := json.Unmarshal(b, &runs[i]); err != nil {
+			return fmt.Errorf("%w: run %s: %w", ErrParse, name, err)
+		}
+	}
+
+	if *csvFile != "" {
+		f, err := os.Create(*csvFile)
+		if err != nil {
+			return fmt.Errorf("%w: error creating dataset", err)
+		}
+		if err := writeTidyRuns(f, names, runs); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("%w: error writing dataset", err)
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	outputAggregate(w, runs)
+
+	return nil
+}
+
+// writeTidyRuns writes one file,input,options,algorithm,metric,value row per
+// run, algorithm and metric. Options are the run's flags as sorted key=value
+// pairs separated by spaces.
+func writeTidyRuns(w io.Writer, names []string, runs []runRecord) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"file", "input", "options", "algorithm", "metric", "value"})
+	for i, rec := range runs {
+		input := ""
+		if len(rec.Manifest.Inputs) > 0 {
+			input = rec.Manifest.Inputs[0].Path
+		}
+		keys := make([]string, 0, len(rec.Manifest.Options))
+		for k := range rec.Manifest.Options {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for j, k := range keys {
+			pairs[j] = k + "=" + rec.Manifest.Options[k]
+		}
+		for _, res := range rec.Results {
+			for _, metric := range sortedMetrics(res.Metrics) {
+				_ = cw.Write([]string{names[i], input, strings.Join(pairs, " "), res.Key, metric, fmt.Sprint(res.Metrics[metric])})
+			}
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// outputAggregate prints the mean, standard deviation and range of every
+// metric of every algorithm across runs.
+func outputAggregate(w io.Writer, runs []runRecord) {
+	type series struct {
+		name   string
+		values map[string][]float64
+	}
+	var order []string
+	byKey := make(map[string]*series)
+	for _, rec := range runs {
+		for _, res := range rec.Results {
+			s, ok := byKey[res.Key]
+			if !ok {
+				s = &series{name: res.Name, values: make(map[string][]float64)}
+				byKey[res.Key] = s
+				order = append(order, res.Key)
+			}
+			for metric, v := range res.Metrics {
+				s.values[metric] = append(s.values[metric], v)
+			}
+		}
+	}
+
+	outputTitle(w, fmt.Sprintf("Aggregate of %d runs", len(runs)))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Metric", "Runs", "Mean", "SD", "Min", "Max"})
+	for _, key := range order {
+		s := byKey[key]
+		for _, metric := range sortedMetrics(s.values) {
+			values := s.values[metric]
+			mean, sd := meanSD(values)
+			lo, hi := values[0], values[0]
+			for _, v := range values {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+			table.Append([]string{s.name, metric, fmt.Sprint(len(values)),
+				fmt.Sprintf("%.2f", mean), fmt.Sprintf("%.2f", sd), fmt.Sprintf("%.2f", lo), fmt.Sprintf("%.2f", hi)})
+		}
+	}
+	table.Render()
+}
+
+// sortedMetrics returns the metrics of m in the order of runMetrics, then
+// any others alphabetically.
+func sortedMetrics[V any](m map[string]V) []string {
+	rank := make(map[string]int, len(runMetrics))
+	for i, metric := range runMetrics {
+		rank[metric] = i
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iok := rank[keys[i]]
+		rj, jok := rank[keys[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+
+	return keys
+}
+
+// meanSD returns the mean and sample standard deviation of values.
+func meanSD(values []float64) (mean, sd float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	for _, v := range values {
+		sd += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(sd / float64(len(values)-1))
+}
+
+//endregion
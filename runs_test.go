@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_aggregateCommand(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workloads := [][]Process{
+		{{ProcessID: 1, BurstDuration: 4}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 2}},
+		{{ProcessID: 1, BurstDuration: 8}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 2}},
+	}
+	for i, processes := range workloads {
+		m := manifest{Inputs: []manifestInput{{Path: "w.csv"}}, Options: map[string]string{"day": "2400", "queue": "fifo"}}
+		name := filepath.Join(dir, "run"+string(rune('a'+i))+".json")
+		if err := writeRunFile(name, newRunRecord(m, processes, options{}, display{})); err != nil {
+			t.Fatalf("writeRunFile() error = %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	dataset := filepath.Join(dir, "tidy.csv")
+	if err := aggregateCommand(&out, []string{"-csv", dataset, filepath.Join(dir, "*.json")}); err != nil {
+		t.Fatalf("aggregateCommand() error = %v", err)
+	}
+	// FCFS waits are 0 and 3, then 0 and 7
+	for _, want := range []string{"Aggregate of 2 runs", "avg_wait", "First-come, first-serve"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("aggregateCommand() output missing %q:\n%s", want, out.String())
+		}
+	}
+	var row []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.Contains(line, "First-come") && strings.Contains(line, "avg_wait") {
+			row = strings.Fields(strings.ReplaceAll(line, "|", " "))
+		}
+	}
+	if want := "2 2.50 1.41 1.50 3.50"; len(row) < 5 || strings.Join(row[len(row)-5:], " ") != want {
+		t.Errorf("aggregateCommand() FCFS avg_wait statistics = %v, want %s", row, want)
+	}
+
+	b, err := os.ReadFile(dataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if want := "file,input,options,algorithm,metric,value"; lines[0] != want {
+		t.Errorf("dataset header = %q, want %q", lines[0], want)
+	}
+	if want := 1 + 2*len(algorithms)*(len(runMetrics)+1); len(lines) != want {
+		t.Errorf("dataset has %d lines, want %d", len(lines), want)
+	}
+	if !strings.Contains(lines[1], ",w.csv,day=2400 queue=fifo,fcfs,avg_wait,1.5") {
+		t.Errorf("dataset first row = %q", lines[1])
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{"no runs", nil, ErrInvalidArgs},
+		{"not a run", []string{"-csv", "", filepath.Join(dir, "tidy.csv")}, ErrParse},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := aggregateCommand(&bytes.Buffer{}, tt.args); !errors.Is(err, tt.want) {
+				t.Errorf("aggregateCommand() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newRunRecord(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 4}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 2}}
+	rec := newRunRecord(manifest{}, processes, options{}, display{precision: 3})
+	if rec.PeakMemoryMiB <= 0 {
+		t.Errorf("newRunRecord() peak memory = %v, want more than 0", rec.PeakMemoryMiB)
+	}
+	// Two arrivals and two dispatches
+	if got := rec.Results[0].Metrics["events"]; got != 4 {
+		t.Errorf("newRunRecord() fcfs events = %v, want 4", got)
+	}
+	if got := rec.Results[0].Formatted["avg_wait"]; got != "1.500" || rec.Precision != 3 {
+		t.Errorf("newRunRecord() fcfs avg_wait formatted = %q at precision %d, want \"1.500\" at 3", got, rec.Precision)
+	}
+	// Side tables take the precision too
+	var out bytes.Buffer
+	outputSRPT(&out, processes, options{}, display{precision: 3})
+	if !strings.Contains(out.String(), "4.500") || !strings.Contains(out.String(), "+0.500") {
+		t.Errorf("outputSRPT() at precision 3 =\n%s", out.String())
+	}
+	if _, ok := rec.Results[0].Metrics[allocMetric]; !ok {
+		t.Errorf("newRunRecord() fcfs metrics = %v, want %s in them", rec.Results[0].Metrics, allocMetric)
+	}
+}
+
+func Test_meanSD(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		values   []float64
+		mean, sd float64
+	}{
+		{[]float64{3}, 3, 0},
+		{[]float64{1, 2, 3, 4}, 2.5, math.Sqrt(5.0 / 3)},
+	}
+	for _, tt := range tests {
+		if mean, sd := meanSD(tt.values); mean != tt.mean || math.Abs(sd-tt.sd) > 1e-9 {
+			t.Errorf("meanSD(%v) = %v, %v, want %v, %v", tt.values, mean, sd, tt.mean, tt.sd)
+		}
+	}
+}
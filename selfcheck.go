@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Self-check
+
+// ErrSelfCheck is returned when the simulated results differ from the
+// analytic ones.
+var ErrSelfCheck = errors.New("simulation differs from the analytic results")
+
+// analyticWait returns the average wait of processes that all arrive at 0
+// and run to completion one after another in the order of bursts: each
+// waits for the bursts of those before it.
+func analyticWait(bursts []int64) float64 {
+	var wait, elapsed int64
+	for _, b := range bursts {
+		wait += elapsed
+		elapsed += b
+	}
+
+	return float64(wait) / float64(len(bursts))
+}
+
+// selfCheckable says why the average waits of processes under FCFS and SJF
+// have no closed form, or "" if they do: every process arrives at 0 and runs
+// in one piece, and nothing else takes the CPU or kills a process.
+func selfCheckable(processes []Process, opts options) string {
+	switch {
+	case len(processes) == 0:
+		return "there are no processes"
+	case len(opts.events) > 0:
+		return "events kill or signal processes"
+	case opts.interrupts != nil:
+		return "interrupts steal the CPU"
+	}
+	for _, p := range processes {
+		switch {
+		case p.ArrivalTime != 0:
+			return "not every process arrives at 0"
+		case p.Window != nil:
+			return "processes wait for their time-of-day window"
+		case p.Spawn != nil:
+			return "processes are spawned"
+		case p.WaitOffset > 0 || len(p.Awaits) > 0:
+			return "processes block"
+		case len(p.Bursts) > 0:
+			return "processes replay bursts"
+		case p.Tolerance != nil:
+			return "processes may be dropped"
+		}
+	}
+
+	return ""
+}
+
+// selfCheck recomputes the average wait of FCFS, which runs processes in the
+// order given, and SJF, in order of burst, from the closed form for
+// processes that all arrive at 0, and compares it with the simulated one.
+func selfCheck(w io.Writer, processes []Process, opts options, d display) error {
+	outputTitle(w, "Analytic check")
+	if reason := selfCheckable(processes, opts); reason != "" {
+		_, _ = fmt.Fprintf(w, "Skipped: the average waits have no closed form when %s.\n", reason)
+		return nil
+	}
+
+	bursts := make([]int64, len(processes))
+	for i, p := range processes {
+		bursts[i] = p.BurstDuration
+	}
+	shortest := slices.Clone(bursts)
+	slices.Sort(shortest)
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Simulated Wait", "Analytic Wait", "Result"})
+	failed := 0
+	for _, c := range []struct {
+		key    string
+		bursts []int64
+	}{
+		{"fcfs", bursts},
+		{"sjf", shortest},
+	} {
+		simulated := algorithmByKey(c.key)(processes, opts).AveWait
+		analytic := analyticWait(c.bursts)
+		result := "ok"
+		if math.Abs(simulated-analytic) > 1e-9 {
+			result = "MISMATCH"
+			failed++
+		}
+		table.Append([]string{c.key, d.format(simulated), d.format(analytic), result})
+	}
+	table.Render()
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of 2 algorithms", ErrSelfCheck, failed)
+	}
+
+	return nil
+}
+
+//endregion
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_analyticWait(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		bursts []int64
+		want   float64
+	}{
+		{"one", []int64{5}, 0},
+		// Silberschatz's convoy: waits 0, 24 and 27
+		{"convoy", []int64{24, 3, 3}, 17},
+		{"shortest first", []int64{3, 3, 24}, 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := analyticWait(tt.bursts); got != tt.want {
+				t.Errorf("analyticWait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_selfCheck(t *testing.T) {
+	t.Parallel()
+	priorityHeap, err := queueByKey("priority-heap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		csv     string
+		opts    options
+		want    string
+		wantErr error
+	}{
+		// FCFS runs processes in the order given, not by ID
+		{"ok", "3,5,0\n1,2,0,1\n2,8,0,2\n", options{}, "| fcfs      |           4.00 |          4.00 | ok     |", nil},
+		// Offered only the process of the lowest priority number, SJF cannot
+		// run the shortest first
+		{"mismatch", "3,5,0\n1,2,0,1\n2,8,0,2\n", options{queue: priorityHeap}, "| sjf       |           4.00 |          3.00 | MISMATCH |", ErrSelfCheck},
+		{"skipped", "1,5,0\n2,2,3\n", options{}, "Skipped: the average waits have no closed form when not every process arrives at 0.", nil},
+		{"blocking", "1,5,0,0,await=2\n2,2,0,0,signal=1@1\n", options{}, "when processes block", nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if err := selfCheck(&out, processes, tt.opts, display{}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("selfCheck() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("selfCheck() missing %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+//region Shortest expected processing time
+
+// shortestExpected dispatches the ready task whose class has the shortest
+// average burst among the tasks of that class completed so far, to
+// completion, like an admission system that only knows the type of each job.
+// Classes with no completed task yet are expected to take the average burst
+// of every completed task, and ties go to the task that became ready first.
+type shortestExpected struct {
+	// total and count sum the bursts of completed tasks per class, and all
+	// across classes. running are the tasks dispatched but not yet seen to
+	// complete.
+	total, count map[string]int64
+	all          [2]int64
+	running      []*task
+}
+
+func (p *shortestExpected) reset() {
+	p.total = make(map[string]int64)
+	p.count = make(map[string]int64)
+	p.all = [2]int64{}
+	p.running = nil
+}
+
+// learn adds the bursts of the tasks that have completed since the last pick
+// to the history of their class.
+func (p *shortestExpected) learn() {
+	running := p.running[:0]
+	for _, t := range p.running {
+		switch {
+		case !t.finished:
+			running = append(running, t)
+		case !t.killed && !t.stuck:
+			p.total[t.Class] += t.BurstDuration
+			p.count[t.Class]++
+			p.all[0] += t.BurstDuration
+			p.all[1]++
+		}
+	}
+	p.running = running
+}
+
+// estimate is the expected burst of a task of the given class, or -1 when
+// nothing has completed yet.
+func (p *shortestExpected) estimate(class string) float64 {
+	if n := p.count[class]; n > 0 {
+		return float64(p.total[class]) / float64(n)
+	}
+	if p.all[1] > 0 {
+		return float64(p.all[0]) / float64(p.all[1])
+	}
+
+	return -1
+}
+
+func (p *shortestExpected) pick(ready []*task) int {
+	p.learn()
+	best := 0
+	for i := range ready {
+		if p.estimate(ready[i].Class) < p.estimate(ready[best].Class) {
+			best = i
+		}
+	}
+	if t := ready[best]; t.firstRun < 0 {
+		p.running = append(p.running, t)
+	}
+
+	return best
+}
+
+func (*shortestExpected) quantum(*task) int64 { return 0 }
+
+// Shortest expected processing time, by class
+func sept(processes []Process, opts options) Result {
+	return simulate(processes, &shortestExpected{}, opts)
+}
+
+//endregion
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_sept(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want []TimeSlice
+	}{
+		{
+			name: "no history runs in arrival order",
+			csv:  "1,9,0,0,class=a\n2,1,0,0,class=b\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 9}, {PID: 2, Start: 9, Stop: 10}},
+		},
+		{
+			// Once a short job of class a and a long one of class b have
+			// completed, the next job of class a goes first however long it is
+			name: "estimates by class",
+			csv:  "1,2,0,0,class=a\n2,10,0,0,class=b\n3,1,1,0,class=b\n4,8,1,0,class=a\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 12},
+				{PID: 4, Start: 12, Stop: 20}, {PID: 3, Start: 20, Stop: 21},
+			},
+		},
+		{
+			name: "unknown class expected to take the average",
+			csv:  "1,2,0,0,class=a\n2,10,0,0,class=b\n3,3,1,0,class=b\n4,3,1,0,class=c\n5,3,1,0,class=a\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 12},
+				{PID: 5, Start: 12, Stop: 15}, {PID: 4, Start: 15, Stop: 18}, {PID: 3, Start: 18, Stop: 21},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sept(processes, options{}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sept() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
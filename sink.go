@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//region Output sinks
+
+// sinkDefaultName is the name given to output sent to a directory-like
+// destination, one ending in a slash.
+const sinkDefaultName = "results.txt"
+
+type (
+	// sink is a destination for output. Everything written is delivered by
+	// the time Close returns.
+	sink interface {
+		io.Writer
+		Close() error
+	}
+
+	// stdoutSink writes to standard output and closing it does nothing.
+	stdoutSink struct{ io.Writer }
+
+	// uploadSink buffers everything written and sends it on Close.
+	uploadSink struct {
+		bytes.Buffer
+		upload func(body []byte) error
+	}
+)
+
+// openSink opens the destination named by uri:
+//   - "" or "-" is standard output
+//   - a path, or a file:// URI, is a local file, created with its directory
+//   - an http:// or https:// URL receives the output in a POST request
+//   - s3://bucket/key is stored in an S3-compatible object store
+//
+// Paths, file URIs and s3 keys ending in a slash are directories, and the
+// output is named results.txt within them.
+func openSink(uri string) (sink, error) {
+	if uri == "" || uri == "-" {
+		return stdoutSink{os.Stdout}, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// A plain path, or a Windows one with a drive letter
+		return openFileSink(uri)
+	}
+	switch u.Scheme {
+	case "file":
+		return openFileSink(u.Path)
+	case "http", "https":
+		return &uploadSink{upload: func(body []byte) error {
+			return postOutput(http.DefaultClient, uri, body)
+		}}, nil
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("%w: %q has no bucket", ErrInvalidArgs, uri)
+		}
+		key := strings.TrimPrefix(u.Path, "/")
+		if key == "" || strings.HasSuffix(key, "/") {
+			key += sinkDefaultName
+		}
+		return &uploadSink{upload: func(body []byte) error {
+			return putS3Object(http.DefaultClient, s3ConfigFromEnv(), u.Host, key, body, time.Now())
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported output %q", ErrInvalidArgs, uri)
+}
+
+func openFileSink(name string) (sink, error) {
+	if strings.HasSuffix(name, "/") || strings.HasSuffix(name, string(filepath.Separator)) {
+		name = filepath.Join(name, sinkDefaultName)
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, fmt.Errorf("%w: error creating output directory", err)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating output file", err)
+	}
+
+	return f, nil
+}
+
+func (stdoutSink) Close() error { return nil }
+
+func (s *uploadSink) Close() error {
+	return s.upload(s.Bytes())
+}
+
+// postOutput sends body to url in a POST request.
+func postOutput(client *http.Client, url string, body []byte) error {
+	resp, err := client.Post(url, "text/plain; charset=utf-8", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: error posting output", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: posting output to %s: %s", ErrInvalidArgs, url, resp.Status)
+	}
+
+	return nil
+}
+
+// s3Config is where and as whom objects are stored.
+type s3Config struct {
+	// endpoint is the store's base URL; buckets are addressed path-style
+	// under it, which every S3-compatible store supports.
+	endpoint, region          string
+	accessKey, secretKey, tok string
+}
+
+// s3ConfigFromEnv reads the usual AWS environment variables. AWS_ENDPOINT_URL
+// points at S3-compatible stores such as MinIO; otherwise AWS S3 is used.
+func s3ConfigFromEnv() s3Config {
+	c := s3Config{
+		endpoint:  os.Getenv("AWS_ENDPOINT_URL"),
+		region:    os.Getenv("AWS_REGION"),
+		accessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		tok:       os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if c.region == "" {
+		c.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if c.region == "" {
+		c.region = "us-east-1"
+	}
+	if c.endpoint == "" {
+		c.endpoint = "https://s3." + c.region + ".amazonaws.com"
+	}
+
+	return c
+}
+
+// putS3Object stores body as bucket/key with a request signed with AWS
+// Signature Version 4.
+func putS3Object(client *http.Client, c s3Config, bucket, key string, body []byte, now time.Time) error {
+	target := strings.TrimSuffix(c.endpoint, "/") + "/" + s3Escape(bucket) + "/" + s3Escape(key)
+	req, err := http.NewRequest(http.MethodPut, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	sum := sha256.Sum256(body)
+	signS3(req, hex.EncodeToString(sum[:]), c, now)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: error storing output", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: storing s3://%s/%s: %s %s", ErrInvalidArgs, bucket, key, resp.Status, msg)
+	}
+
+	return nil
+}
+
+// signS3 adds the AWS Signature Version 4 headers to req, signing every
+// header already set on it along with the host.
+func signS3(req *http.Request, payloadHash string, c s3Config, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.tok != "" {
+		req.Header.Set("X-Amz-Security-Token", c.tok)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	scope := date + "/" + c.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + c.secretKey)
+	for _, part := range []string{date, c.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// s3Escape percent-encodes every byte of an object key except the unreserved
+// characters and slashes, as Signature Version 4 requires.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		_, _ = fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+//endregion
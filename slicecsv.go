@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+)
+
+//region Slice export
+
+// writeSlicesCSV writes every Gantt slice of every algorithm in long format,
+// one algorithm,cpu,pid,start,stop,reason row per slice, ready for pivot
+// tables and plotting tools. The simulator has a single CPU, numbered 0.
+// The reason is why the slice ended: exit, killed, dropped, blocked, yield,
+// burst, interrupted or preempted.
+func writeSlicesCSV(w io.Writer, processes []Process, opts options) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"algorithm", "cpu", "pid", "start", "stop", "reason"})
+	for _, alg := range algorithms {
+		r := alg.run(processes, opts)
+		for i, s := range r.Gantt {
+			_ = cw.Write([]string{
+				alg.key,
+				"0",
+				fmt.Sprint(s.PID),
+				fmt.Sprint(s.Start),
+				fmt.Sprint(s.Stop),
+				r.reasons[i],
+			})
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func writeSlicesCSVFile(name string, processes []Process, opts options) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating slices file", err)
+	}
+	if err := writeSlicesCSV(f, processes, opts); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: error writing slices file", err)
+	}
+
+	return f.Close()
+}
+
+//endregion
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_writeSlicesCSV(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 8, Yields: []int64{2}},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3, Awaits: []int64{1}},
+		{ProcessID: 3, ArrivalTime: 0, BurstDuration: 12},
+	}
+	var b bytes.Buffer
+	if err := writeSlicesCSV(&b, processes, options{events: []Event{{Time: 20, Kind: eventKill, PID: 3}}}); err != nil {
+		t.Fatalf("writeSlicesCSV() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"algorithm,cpu,pid,start,stop,reason\n",
+		"fcfs,0,1,0,8,exit\n",
+		"fcfs,0,2,8,9,blocked\n",
+		"rr,0,1,0,2,yield\n",
+		"rr,0,3,3,8,preempted\n",
+		"rr,0,3,19,20,killed\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeSlicesCSV() missing %q in\n%s", want, got)
+		}
+	}
+}
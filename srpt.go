@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Shortest remaining processing time
+
+// shortestRemaining dispatches the ready task with the least of its burst
+// left, reconsidering every unit so that a shorter arrival preempts the
+// running task (SRPT, also known as shortest-remaining-time-first). On one
+// CPU it minimizes the mean flow time, or turnaround, of any workload.
+type shortestRemaining struct{}
+
+func (shortestRemaining) pick(ready []*task) int {
+	best := 0
+	for i := range ready {
+		if ready[i].remaining < ready[best].remaining {
+			best = i
+		}
+	}
+
+	return best
+}
+
+func (shortestRemaining) quantum(*task) int64 { return 1 }
+
+// Shortest remaining time first, preemptive SJF
+func SRTFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, srpt(processes, options{}), display{})
+}
+
+// Shortest remaining processing time
+func srpt(processes []Process, opts options) Result {
+	return simulate(processes, shortestRemaining{}, opts)
+}
+
+// srptConditions lists the ways processes step outside the model in which
+// SRPT is optimal: jobs known in full on arrival that only need the CPU.
+func srptConditions(processes []Process) []string {
+	var blocking, spawns, regions, windows bool
+	for _, p := range processes {
+		blocking = blocking || p.WaitOffset > 0 || len(p.Awaits) > 0
+		spawns = spawns || p.Spawn != nil
+		regions = regions || len(p.NonPreemptible) > 0
+		windows = windows || p.Window != nil
+	}
+
+	var found []string
+	for _, c := range []struct {
+		name string
+		ok   bool
+	}{
+		{"blocking", blocking},
+		{"spawned children", spawns},
+		{"non-preemptible regions", regions},
+		{"time-of-day windows", windows},
+	} {
+		if c.ok {
+			found = append(found, c.name)
+		}
+	}
+
+	return found
+}
+
+// outputSRPT demonstrates SRPT's optimality: it compares the mean flow time
+// of every algorithm with SRPT's and reports the gap, which is never negative
+// unless the workload steps outside the conditions of the proof.
+func outputSRPT(w io.Writer, processes []Process, opts options, d display) {
+	best := srpt(append([]Process(nil), processes...), opts).AveTurnaround
+
+	outputTitle(w, "SRPT optimality")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Mean Flow Time", "Gap", "Gap %"})
+	var beaten []string
+	for _, alg := range algorithms {
+		r := alg.run(append([]Process(nil), processes...), opts)
+		gap := r.AveTurnaround - best
+		percent := "-"
+		if best > 0 {
+			percent = fmt.Sprintf("%.1f%%", 100*gap/best)
+		}
+		table.Append([]string{alg.name, d.format(r.AveTurnaround), fmt.Sprintf("%+.*f", d.places(), gap), percent})
+		if gap < -1e-9 {
+			beaten = append(beaten, alg.name)
+		}
+	}
+	table.Render()
+
+	conditions := srptConditions(processes)
+	if len(opts.events) > 0 || opts.minGranularity > 0 {
+		conditions = append(conditions, "events or a minimum granularity")
+	}
+	switch {
+	case len(beaten) > 0:
+		_, _ = fmt.Fprintf(w, "%s beat SRPT, which is only optimal when processes just need the CPU; this workload has %s.\n",
+			strings.Join(beaten, ", "), strings.Join(conditions, ", "))
+	case len(conditions) > 0:
+		_, _ = fmt.Fprintf(w, "No algorithm beats SRPT here, though with %s it is not guaranteed to be optimal.\n", strings.Join(conditions, ", "))
+	default:
+		_, _ = fmt.Fprintln(w, "No algorithm beats SRPT: it has the least mean flow time of any schedule of this workload on one CPU.")
+	}
+}
+
+//endregion
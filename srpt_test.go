@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_srpt(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,8,0\n2,4,1\n3,1,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2},
+		{PID: 3, Start: 2, Stop: 3}, {PID: 2, Start: 3, Stop: 6},
+		{PID: 1, Start: 6, Stop: 13},
+	}
+	if got := srpt(processes, options{}).Gantt; !reflect.DeepEqual(got, want) {
+		t.Errorf("srpt() gantt = %v, want %v", got, want)
+	}
+}
+
+func TestSRTFSchedule(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,8,0\n2,4,1\n3,1,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	SRTFSchedule(&out, "SRTF", processes)
+	// Each preemption splits the Gantt chart; waits are 5, 1 and 0
+	for _, want := range []string{"|   1   |   2   |   3   |   2   |   1   |", "0\t1\t2\t3\t6\t13", "|                                    2.00   |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("SRTFSchedule() missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func Test_outputSRPT(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		csv  string
+		want string
+	}{
+		{"optimal", "1,8,0\n2,4,1\n3,1,2\n", "No algorithm beats SRPT: it has the least mean flow time"},
+		{"outside the proof", "1,8,0,0,await=2\n2,4,1\n3,1,2,0,signal=1\n", "though with blocking it is not guaranteed to be optimal"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			outputSRPT(&out, processes, options{}, display{})
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("outputSRPT() missing %q:\n%s", tt.want, out.String())
+			}
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.Contains(line, "Shortest-remaining-time") && !strings.Contains(line, "+0.00") {
+					t.Errorf("SRPT has a gap to itself: %s", line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+//region Selfish round-robin
+
+// defaultSRRRates are the rates at which the priorities of new and accepted
+// processes rise under selfish round-robin unless set.
+var defaultSRRRates = srrRates{new: 2, accepted: 1}
+
+type (
+	// srrRates are how much the priority of a process rises per unit it
+	// spends on the ready queue of new processes, and per unit while there
+	// are accepted processes, under selfish round-robin.
+	srrRates struct {
+		new, accepted int64
+	}
+
+	// selfishRoundRobin keeps two queues. Tasks that arrive or wake join the
+	// queue of new tasks with a priority of 0, which rises by rates.new for
+	// every unit they wait there. The accepted tasks take turns round-robin
+	// for a quantum each and share one priority, which rises by
+	// rates.accepted for every unit while there are any. A new task is
+	// accepted once its priority catches up with theirs, or, when none are
+	// accepted, along with every new task of the highest priority. The
+	// accepted tasks are selfish: the slower new tasks catch up, the longer
+	// the accepted ones keep the CPU among themselves. With rates.accepted
+	// at 0 this comes close to round-robin, and with it at least rates.new
+	// the accepted tasks run out before anyone else is accepted.
+	selfishRoundRobin struct {
+		rates srrRates
+		now   int64
+		tasks map[int64]*srrTask
+		// level is the priority of the accepted tasks as of now.
+		level int64
+		seq   int
+		// last is the task dispatched by the last pick.
+		last *task
+	}
+
+	srrTask struct {
+		accepted bool
+		// joined is how long the task had waited on the ready queue when it
+		// last joined the new tasks, from which its priority rises, and
+		// waited how long it had when last dispatched.
+		joined, waited int64
+		// seq orders accepted tasks by their turn.
+		seq int
+		// blocked is how long the task had been blocked when last seen; one
+		// that woke is new again.
+		blocked int64
+	}
+)
+
+// parseSRRRates parses new:accepted, the rates at which the priorities of
+// new and accepted processes rise under selfish round-robin.
+func parseSRRRates(s string) (srrRates, error) {
+	n, a, ok := strings.Cut(s, ":")
+	if !ok {
+		return srrRates{}, fmt.Errorf("%w: selfish round-robin rates %q are not new:accepted", ErrInvalidArgs, s)
+	}
+	var r srrRates
+	var err error
+	if r.new, err = parseInt(n); err != nil {
+		return srrRates{}, fmt.Errorf("%w: selfish round-robin rates %q: %w", ErrInvalidArgs, s, err)
+	}
+	if r.accepted, err = parseInt(a); err != nil {
+		return srrRates{}, fmt.Errorf("%w: selfish round-robin rates %q: %w", ErrInvalidArgs, s, err)
+	}
+	if r.new <= 0 || r.accepted < 0 {
+		return srrRates{}, fmt.Errorf("%w: selfish round-robin rates %q must be positive for new processes and not negative for accepted ones", ErrInvalidArgs, s)
+	}
+
+	return r, nil
+}
+
+func (p *selfishRoundRobin) reset() {
+	*p = selfishRoundRobin{rates: p.rates, tasks: make(map[int64]*srrTask)}
+}
+
+// setNow raises the level of the accepted tasks. Were there none since the
+// last pick, pick sets it afresh.
+func (p *selfishRoundRobin) setNow(now int64) {
+	p.level += p.rates.accepted * (now - p.now)
+	p.now = now
+}
+
+// priority returns the priority of t, a new task, by now.
+func (p *selfishRoundRobin) priority(t *task) int64 {
+	return p.rates.new * (t.wait(p.now) - p.tasks[t.ProcessID].joined)
+}
+
+func (p *selfishRoundRobin) pick(ready []*task) int {
+	// Tasks that arrived or woke are new
+	var fresh []*task
+	for _, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		switch {
+		case !ok:
+			s = &srrTask{blocked: t.blockedFor}
+			p.tasks[t.ProcessID] = s
+		case t.blockedFor > s.blocked:
+			// It has waited no more since it last ran, then blocked
+			s.accepted, s.joined, s.blocked = false, s.waited, t.blockedFor
+		}
+		if !s.accepted {
+			fresh = append(fresh, t)
+		}
+	}
+
+	// With none accepted, the new tasks of the highest priority set the level
+	if len(fresh) == len(ready) {
+		p.level = p.priority(fresh[0])
+		for _, t := range fresh {
+			p.level = max(p.level, p.priority(t))
+		}
+	}
+	for _, t := range fresh {
+		if p.priority(t) >= p.level {
+			p.seq++
+			s := p.tasks[t.ProcessID]
+			s.accepted, s.seq = true, p.seq
+		}
+	}
+
+	// The task that ran goes to the back, behind any accepted meanwhile, and
+	// the accepted task whose turn it is runs
+	if p.last != nil {
+		p.seq++
+		p.tasks[p.last.ProcessID].seq = p.seq
+	}
+	next := -1
+	for i, t := range ready {
+		s := p.tasks[t.ProcessID]
+		if s.accepted && (next < 0 || s.seq < p.tasks[ready[next].ProcessID].seq) {
+			next = i
+		}
+	}
+	p.last = ready[next]
+	p.tasks[p.last.ProcessID].waited = p.last.wait(p.now)
+
+	return next
+}
+
+func (*selfishRoundRobin) quantum(*task) int64 { return 5 }
+
+// Selfish round-robin
+func SRRSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, srr(processes, options{}), display{})
+}
+
+// srr runs selfish round-robin with the rates opts.srrRates, or
+// defaultSRRRates.
+func srr(processes []Process, opts options) Result {
+	rates := defaultSRRRates
+	if opts.srrRates != nil {
+		rates = *opts.srrRates
+	}
+
+	return simulate(processes, &selfishRoundRobin{rates: rates}, opts)
+}
+
+//endregion
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_srr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		csv   string
+		rates *srrRates
+		want  []TimeSlice
+	}{
+		{
+			// P2 catches up with P1 at 4 and goes ahead of it once its slice
+			// ends; P3 only does at 8, and goes behind P1
+			name: "default rates",
+			csv:  "1,20,0\n2,10,2\n3,10,4\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10},
+				{PID: 1, Start: 10, Stop: 15}, {PID: 3, Start: 15, Stop: 20},
+				{PID: 2, Start: 20, Stop: 25}, {PID: 1, Start: 25, Stop: 30},
+				{PID: 3, Start: 30, Stop: 35}, {PID: 1, Start: 35, Stop: 40},
+			},
+		},
+		{
+			// Accepted processes that do not age let new ones in almost at
+			// once, much as under round-robin
+			name:  "accepted do not age",
+			csv:   "1,20,0\n2,10,2\n3,10,4\n",
+			rates: &srrRates{new: 1, accepted: 0},
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10},
+				{PID: 3, Start: 10, Stop: 15}, {PID: 1, Start: 15, Stop: 20},
+				{PID: 2, Start: 20, Stop: 25}, {PID: 3, Start: 25, Stop: 30},
+				{PID: 1, Start: 30, Stop: 40},
+			},
+		},
+		{
+			// New processes never catch up, so each runs to completion
+			name:  "equal rates",
+			csv:   "1,20,0\n2,10,2\n3,10,4\n",
+			rates: &srrRates{new: 1, accepted: 1},
+			want:  []TimeSlice{{PID: 1, Start: 0, Stop: 20}, {PID: 2, Start: 20, Stop: 30}, {PID: 3, Start: 30, Stop: 40}},
+		},
+		{
+			// P1 wakes at 3 as a new process, and by 8 has waited less
+			// than P3, which is accepted first
+			name:  "blocking",
+			csv:   "1,4,0,0,await=2\n2,6,0,0,signal=1@1\n3,3,1\n",
+			rates: &srrRates{new: 1, accepted: 1},
+			want:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 8}, {PID: 3, Start: 8, Stop: 11}, {PID: 1, Start: 11, Stop: 13}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := srr(processes, options{srrRates: tt.rates}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("srr() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseSRRRates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s       string
+		want    srrRates
+		wantErr error
+	}{
+		{s: "2:1", want: srrRates{new: 2, accepted: 1}},
+		{s: "3:0", want: srrRates{new: 3, accepted: 0}},
+		{s: "2", wantErr: ErrInvalidArgs},
+		{s: "0:1", wantErr: ErrInvalidArgs},
+		{s: "2:-1", wantErr: ErrInvalidArgs},
+		{s: "a:1", wantErr: ErrParse},
+	}
+	for _, tt := range tests {
+		got, err := parseSRRRates(tt.s)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("parseSRRRates(%q) error = %v, want %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSRRRates(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
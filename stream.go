@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+)
+
+//region Streaming results
+
+// StreamSchedule runs the scheduler registered under key, e.g. "rr", without
+// keeping the Gantt chart in memory. Statistics are available at once;
+// ranging over the result's Slices runs the simulation again and yields the
+// chart slice by slice, so multi-million-slice schedules can be consumed in
+// constant memory. Like everything in package main, it is for this
+// repository's own commands and tests.
+func StreamSchedule(key string, processes []Process) (Result, error) {
+	run := algorithmByKey(key)
+	if run == nil {
+		return Result{}, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, key)
+	}
+
+	return run(processes, options{lazy: true}), nil
+}
+
+// Slices yields the Gantt chart one slice at a time.
+func (r Result) Slices() iter.Seq[TimeSlice] {
+	if r.stream != nil {
+		return r.stream
+	}
+
+	return slices.Values(r.Gantt)
+}
+
+// Processes yields the scheduled processes in arrival order, with the arrival
+// of spawned children set to when they were spawned.
+func (r Result) Processes() iter.Seq[Process] {
+	return slices.Values(r.processes)
+}
+
+// streamColumns head the CSV written by streamResults.
+var streamColumns = []string{"algorithm", "kind", "pid", "start", "stop", "wait", "turnaround"}
+
+// streamResults runs every algorithm and writes its Gantt slices and
+// finished processes as CSV rows the moment they are final, so an
+// arbitrarily long run never holds its schedule in memory. Every row has
+// the streamColumns. Slice rows have a kind of slice and leave wait and
+// turnaround empty. Process rows have a kind of exit, killed, dropped or
+// blocked, with start the arrival and stop the completion.
+func streamResults(w io.Writer, processes []Process, opts options) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(streamColumns)
+	for _, alg := range algorithms {
+		o := opts
+		o.onSlice = func(s TimeSlice) bool {
+			err := cw.Write([]string{alg.key, "slice", fmt.Sprint(s.PID), fmt.Sprint(s.Start), fmt.Sprint(s.Stop), "", ""})
+			return err == nil
+		}
+		o.onExit = func(t *task) {
+			outcome := "exit"
+			switch {
+			case t.dropped:
+				outcome = "dropped"
+			case t.killed:
+				outcome = "killed"
+			case t.stuck:
+				outcome = "blocked"
+			}
+			turnaround := t.completion - t.ArrivalTime
+			_ = cw.Write([]string{
+				alg.key,
+				outcome,
+				strconv.FormatInt(t.ProcessID, 10),
+				strconv.FormatInt(t.ArrivalTime, 10),
+				strconv.FormatInt(t.completion, 10),
+				strconv.FormatInt(turnaround-t.ran-t.blockedFor, 10),
+				strconv.FormatInt(turnaround, 10),
+			})
+		}
+		alg.run(processes, o)
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//endregion
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStreamSchedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 12},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 7, Yields: []int64{3}},
+		{ProcessID: 3, ArrivalTime: 4, BurstDuration: 9},
+	}
+	for _, alg := range algorithms {
+		alg := alg
+		t.Run(alg.key, func(t *testing.T) {
+			t.Parallel()
+			want := alg.run(processes, options{})
+			got, err := StreamSchedule(alg.key, processes)
+			if err != nil {
+				t.Fatalf("StreamSchedule() error = %v", err)
+			}
+			if got.Gantt != nil {
+				t.Errorf("StreamSchedule() kept the Gantt chart %v", got.Gantt)
+			}
+			if gantt := slices.Collect(got.Slices()); !reflect.DeepEqual(gantt, want.Gantt) {
+				t.Errorf("StreamSchedule() slices = %v, want %v", gantt, want.Gantt)
+			}
+			if got.AveWait != want.AveWait || got.AveTurnaround != want.AveTurnaround {
+				t.Errorf("StreamSchedule() averages = %v, %v, want %v, %v",
+					got.AveWait, got.AveTurnaround, want.AveWait, want.AveTurnaround)
+			}
+			if ps := slices.Collect(got.Processes()); len(ps) != len(processes) {
+				t.Errorf("StreamSchedule() processes = %v, want %v", ps, processes)
+			}
+
+			// Stopping early ends the simulation
+			var first []TimeSlice
+			for s := range got.Slices() {
+				first = append(first, s)
+				if len(first) == 2 {
+					break
+				}
+			}
+			if !reflect.DeepEqual(first, want.Gantt[:2]) {
+				t.Errorf("first slices = %v, want %v", first, want.Gantt[:2])
+			}
+		})
+	}
+
+	if _, err := StreamSchedule("unknown", processes); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("StreamSchedule() error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func Test_streamResults(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: 2},
+	}
+	var out bytes.Buffer
+	if err := streamResults(&out, processes, options{}); err != nil {
+		t.Fatalf("streamResults() error = %v", err)
+	}
+	// A slice is final once the next one starts, after its process exits
+	want := "algorithm,kind,pid,start,stop,wait,turnaround\n" +
+		"fcfs,exit,1,0,5,0,5\n" +
+		"fcfs,slice,1,0,5,,\n" +
+		"fcfs,exit,2,0,7,5,7\n" +
+		"fcfs,slice,2,5,7,,\n" +
+		"sjf,exit,2,0,2,0,2\n"
+	if !strings.HasPrefix(out.String(), want) {
+		t.Errorf("streamResults() =\n%s\nwant it to start with\n%s", out.String(), want)
+	}
+
+	// The header names the columns of the process rows as documented
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := map[string]int{}
+	for i, name := range rows[0] {
+		header[name] = i
+	}
+	if want := []string{"algorithm", "kind", "pid", "start", "stop", "wait", "turnaround"}; !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("streamResults() header = %v, want %v", rows[0], want)
+	}
+	exit := rows[3]
+	got := []string{exit[header["kind"]], exit[header["pid"]], exit[header["start"]], exit[header["stop"]], exit[header["wait"]], exit[header["turnaround"]]}
+	if want := []string{"exit", "2", "0", "7", "5", "7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("streamResults() process row by header = %v, want kind, pid, arrival, completion, wait and turnaround %v", got, want)
+	}
+
+	// The Gantt chart is not kept while it is streamed
+	var streamed int
+	opts := options{onSlice: func(TimeSlice) bool { streamed++; return true }}
+	if r := rr(processes, opts); len(r.Gantt) > 0 || streamed != 2 {
+		t.Errorf("rr() kept %v and streamed %d slices, want none kept and 2 streamed", r.Gantt, streamed)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Stress test
+
+// stressLoad is the CPU utilization of the generated stress workload, high
+// enough for long ready queues without growing them without bound.
+const stressLoad = 0.8
+
+// stressCommand generates a large workload in memory, runs the chosen
+// algorithms on it and reports how fast the simulator itself is.
+func stressCommand(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("stress", flag.ContinueOnError)
+	n := fs.Float64("processes", 1e6, "number of processes to generate, e.g. 1e6")
+	keys := fs.String("algorithms", "all", "comma-separated algorithms to run, such as fcfs,rr, or all")
+	burst := fs.Float64("burst", 10, "mean burst of the processes")
+	seed := fs.Int64("seed", 1, "random seed")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *n < 1 || *burst <= 0 {
+		return fmt.Errorf("%w: -processes must be at least 1 and -burst greater than 0", ErrInvalidArgs)
+	}
+	selected := algorithms
+	if *keys != "all" {
+		selected = algorithms[:0:0]
+		for _, key := range strings.Split(*keys, ",") {
+			n := len(selected)
+			for _, alg := range algorithms {
+				if alg.key == key {
+					selected = append(selected, alg)
+				}
+			}
+			if len(selected) == n {
+				return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, key)
+			}
+		}
+	}
+
+	// Arrivals spread over a day long enough for the load to be stressLoad
+	day := max(int64(*n**burst/stressLoad), 1)
+	processes := generateProcesses(rand.New(rand.NewSource(*seed)), int(*n), 1, day, *burst, 5, nil)
+
+	outputTitle(w, "Simulator throughput")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Processes", "Events", "Time", "Events/sec", "Memory MiB"})
+	for _, alg := range selected {
+		start := time.Now()
+		r := alg.run(processes, options{lazy: true})
+		elapsed := time.Since(start)
+
+		events := simulatorEvents(r)
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(len(processes)),
+			fmt.Sprint(events),
+			elapsed.Round(time.Millisecond).String(),
+			fmt.Sprintf("%.0f", float64(events)/max(elapsed.Seconds(), 1e-9)),
+			fmt.Sprintf("%.1f", float64(mem.Sys)/(1<<20)),
+		})
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w, "Memory is what the simulator has obtained from the OS so far, an upper bound on its peak RSS.")
+
+	return nil
+}
+
+// simulatorEvents counts the events the simulator handled: each process
+// arrives once and is dispatched one or more times.
+func simulatorEvents(r Result) int {
+	return len(r.processes) + r.Dispatches
+}
+
+//endregion
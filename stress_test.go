@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_stressCommand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr error
+	}{
+		{"chosen algorithms", []string{"-processes", "1e3", "-algorithms", "fcfs,rr"}, []string{"First-come, first-serve", "Round-robin", "1000"}, nil},
+		{"unknown algorithm", []string{"-processes", "10", "-algorithms", "fcfs,unknown"}, nil, ErrInvalidArgs},
+		{"no processes", []string{"-processes", "0"}, nil, ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := stressCommand(&out, tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("stressCommand() error = %v, want %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("stressCommand() output missing %q:\n%s", want, out.String())
+				}
+			}
+			if strings.Contains(out.String(), "Shortest-job-first") {
+				t.Errorf("stressCommand() ran an algorithm that was not chosen:\n%s", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import "io"
+
+//region Stride
+
+// strideOne is the pass a task holding a single ticket advances by per unit
+// it runs; large, so that the strides of most ticket counts are exact.
+const strideOne = 1 << 20
+
+// stride is the deterministic counterpart of the lottery: each task advances
+// its pass by its stride, inversely proportional to its tickets, for every
+// unit it runs, and the ready task with the lowest pass runs for a quantum.
+// Tasks that arrive or wake join at the lowest pass of the tasks already
+// ready, so that they neither starve the others nor are owed time they
+// spent away.
+type stride struct {
+	pass map[int64]int64
+	// ready are the IDs of the tasks ready at the last pick.
+	ready []int64
+	shareLedger
+}
+
+func (p *stride) reset() {
+	*p = stride{pass: make(map[int64]int64), shareLedger: newShareLedger()}
+}
+
+func (p *stride) pick(ready []*task) int {
+	if t := p.last; t != nil {
+		p.pass[t.ProcessID] += (t.ran - p.lastRan) * (strideOne / t.tickets())
+	}
+	p.charge()
+
+	stayed := make(map[int64]bool, len(p.ready))
+	for _, pid := range p.ready {
+		stayed[pid] = true
+	}
+	low, known := int64(0), false
+	for _, t := range ready {
+		if pass := p.pass[t.ProcessID]; stayed[t.ProcessID] && (!known || pass < low) {
+			low, known = pass, true
+		}
+	}
+	best := 0
+	p.ready = p.ready[:0]
+	for i, t := range ready {
+		p.ready = append(p.ready, t.ProcessID)
+		if !stayed[t.ProcessID] && known {
+			p.pass[t.ProcessID] = max(p.pass[t.ProcessID], low)
+		}
+		b := ready[best]
+		if pass, bestPass := p.pass[t.ProcessID], p.pass[b.ProcessID]; pass < bestPass || pass == bestPass && t.ProcessID < b.ProcessID {
+			best = i
+		}
+	}
+	p.dispatch(ready, best)
+
+	return best
+}
+
+func (*stride) quantum(*task) int64 { return shareQuantum }
+
+// StrideSchedule outputs the schedule of stride scheduling and the share of
+// the CPU each process achieved against its share of the tickets.
+func StrideSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, strideScheduling(processes, options{}), display{})
+	outputStride(w, processes, options{})
+}
+
+// Stride
+func strideScheduling(processes []Process, opts options) Result {
+	r, _ := strideShares(processes, opts)
+	return r
+}
+
+// strideShares runs stride scheduling and returns what each process got out
+// of it as well.
+func strideShares(processes []Process, opts options) (Result, *stride) {
+	p := &stride{}
+	r := simulate(processes, p, opts)
+	// Charge the last task dispatched
+	p.charge()
+
+	return r, p
+}
+
+// outputStride shows the share of the CPU each process got under stride
+// scheduling.
+func outputStride(w io.Writer, processes []Process, opts options) {
+	_, p := strideShares(append([]Process(nil), processes...), opts)
+	outputShares(w, "Stride shares", processes, p.shareLedger)
+}
+
+//endregion
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_stride(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		csv        string
+		want       []TimeSlice
+		wantShares map[int64]cpuShare
+	}{
+		{
+			// Three tickets against one run P1 three quanta to P2's one
+			name: "tickets",
+			csv:  "1,20,0,0,tickets=3\n2,20,0,0,tickets=1\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10}, {PID: 1, Start: 10, Stop: 25}, {PID: 2, Start: 25, Stop: 40}},
+			wantShares: map[int64]cpuShare{
+				1: {contended: 25, ran: 20, expected: 18.75, tickets: 3},
+				2: {contended: 25, ran: 5, expected: 6.25, tickets: 1},
+			},
+		},
+		{
+			// P2 joins at P1's pass rather than running until it catches up
+			name: "join",
+			csv:  "1,20,0,0,tickets=1\n2,10,10,0,tickets=1\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 15}, {PID: 2, Start: 15, Stop: 20}, {PID: 1, Start: 20, Stop: 25}, {PID: 2, Start: 25, Stop: 30}},
+			wantShares: map[int64]cpuShare{
+				1: {contended: 15, ran: 10, expected: 7.5, tickets: 1},
+				2: {contended: 15, ran: 5, expected: 7.5, tickets: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, p := strideShares(processes, options{})
+			if !reflect.DeepEqual(r.Gantt, tt.want) {
+				t.Errorf("strideShares() gantt = %v, want %v", r.Gantt, tt.want)
+			}
+			shares := make(map[int64]cpuShare)
+			for pid, s := range p.shares {
+				shares[pid] = *s
+			}
+			if !reflect.DeepEqual(shares, tt.wantShares) {
+				t.Errorf("strideShares() shares = %v, want %v", shares, tt.wantShares)
+			}
+		})
+	}
+
+	processes, err := loadProcesses(strings.NewReader(tests[0].csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	StrideSchedule(&out, "Stride", processes)
+	if !strings.Contains(out.String(), "| 75.0%        | 80.0%          |") {
+		t.Errorf("StrideSchedule() =\n%s", out.String())
+	}
+}
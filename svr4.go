@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//region SVR4 time-sharing
+
+type (
+	// dispatchLevel is one row of an SVR4 time-sharing dispatch table, like
+	// Solaris ts_dptbl. Levels are numbered from 0, and higher levels run
+	// first.
+	dispatchLevel struct {
+		// quantum is how long a process at this level runs before it is
+		// preempted.
+		quantum int64
+		// tqexp is the level a process moves to when its quantum expires.
+		tqexp int
+		// slpret is the level a process moves to when it wakes from blocking.
+		slpret int
+		// maxwait is how long a process may wait on the ready queue before it
+		// is raised to lwait, so it does not starve.
+		maxwait int64
+		lwait   int
+	}
+
+	// timeSharing is a dispatch-table-driven time-sharing scheduler as in
+	// SVR4 and Solaris. It dispatches the process at the highest level, first
+	// come first served within a level, and preempts it as soon as one at a
+	// higher level is ready. The table decides how processes move between
+	// levels: down when they use up their quantum, up when they return from
+	// blocking or have waited too long. Processes start at the top level less
+	// their priority.
+	timeSharing struct {
+		table []dispatchLevel
+		tasks map[int64]*tsTask
+		seq   int
+		// elapsed is the CPU time used so far. While any process waits the
+		// CPU is busy, so it measures waits too.
+		elapsed int64
+		// highFirst starts processes at their priority rather than the top
+		// level less it, when higher priorities come first.
+		highFirst bool
+	}
+
+	tsTask struct {
+		level int
+		// slice is what is left of the quantum.
+		slice int64
+		// since is when the process was last dispatched or moved.
+		since int64
+		// ran and blocked are how long the process had run and been blocked
+		// when last seen, to charge what happened since.
+		ran, blocked int64
+		// seq orders processes of the same level by when they joined it.
+		seq int
+	}
+)
+
+// defaultDispatchTable returns a table of 60 levels modeled on the Solaris
+// default, taking one time unit as 10 ms: quanta from 20 units at the bottom
+// to 2 at the top, expiring quanta dropping processes 10 levels, and
+// returning from sleep or waiting over a second raising them to 50 and up.
+func defaultDispatchTable() []dispatchLevel {
+	table := make([]dispatchLevel, 60)
+	quanta := []int64{20, 16, 12, 8, 4, 4}
+	slpret := []int{50, 51, 52, 53, 55, 58}
+	for level := range table {
+		band := level / 10
+		l := dispatchLevel{
+			quantum: quanta[band],
+			tqexp:   max(level-10, 0),
+			slpret:  slpret[band],
+			maxwait: 100,
+			lwait:   slpret[band],
+		}
+		if band == 5 {
+			l.lwait = 59
+		}
+		table[level] = l
+	}
+	table[59] = dispatchLevel{quantum: 2, tqexp: 49, slpret: 59, maxwait: 32000, lwait: 59}
+
+	return table
+}
+
+// loadDispatchTable reads a dispatch table with one
+// quantum,tqexp,slpret,maxwait,lwait row per level, starting at level 0.
+// Lines starting with # are comments.
+func loadDispatchTable(r io.Reader) ([]dispatchLevel, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading dispatch table: %w", ErrParse, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: empty dispatch table", ErrParse)
+	}
+
+	table := make([]dispatchLevel, len(rows))
+	for i, row := range rows {
+		if len(row) != 5 {
+			return nil, fmt.Errorf("%w: dispatch table level %d must be quantum,tqexp,slpret,maxwait,lwait", ErrParse, i)
+		}
+		var v [5]int64
+		for j := range v {
+			if v[j], err = parseInt(strings.TrimSpace(row[j])); err != nil {
+				return nil, fmt.Errorf("%w: dispatch table level %d", err, i)
+			}
+		}
+		if v[0] <= 0 || v[3] < 0 {
+			return nil, fmt.Errorf("%w: dispatch table level %d needs a positive quantum and no negative maxwait", ErrInvalidArgs, i)
+		}
+		for _, to := range []int64{v[1], v[2], v[4]} {
+			if to < 0 || to >= int64(len(rows)) {
+				return nil, fmt.Errorf("%w: dispatch table level %d moves to missing level %d", ErrInvalidArgs, i, to)
+			}
+		}
+		table[i] = dispatchLevel{quantum: v[0], tqexp: int(v[1]), slpret: int(v[2]), maxwait: v[3], lwait: int(v[4])}
+	}
+
+	return table, nil
+}
+
+func loadDispatchTableFile(name string) ([]dispatchLevel, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening dispatch table", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return loadDispatchTable(f)
+}
+
+func (p *timeSharing) reset() {
+	p.tasks = make(map[int64]*tsTask)
+	p.seq = 0
+	p.elapsed = 0
+}
+
+// move puts s at the given level with a fresh quantum, behind the processes
+// already there.
+func (p *timeSharing) move(s *tsTask, level int) {
+	p.seq++
+	*s = tsTask{level: level, slice: p.table[level].quantum, since: p.elapsed, ran: s.ran, blocked: s.blocked, seq: p.seq}
+}
+
+// account charges every ready process for what it ran and slept since it was
+// last seen, moving it through the table when its quantum expired, it woke
+// or it waited too long.
+func (p *timeSharing) account(ready []*task) {
+	for _, t := range ready {
+		if d := t.ran - p.task(t).ran; d > 0 {
+			p.elapsed += d
+		}
+	}
+	for _, t := range ready {
+		s := p.task(t)
+		if d := t.ran - s.ran; d > 0 {
+			s.ran = t.ran
+			if s.slice -= d; s.slice <= 0 {
+				p.move(s, p.table[s.level].tqexp)
+			}
+		}
+		if t.blockedFor > s.blocked {
+			s.blocked = t.blockedFor
+			p.move(s, p.table[s.level].slpret)
+		}
+		if p.elapsed-s.since > p.table[s.level].maxwait {
+			p.move(s, p.table[s.level].lwait)
+		}
+	}
+}
+
+// task returns the state of t, starting it at the top level less its
+// priority, or at its priority when higher priorities come first.
+func (p *timeSharing) task(t *task) *tsTask {
+	s, ok := p.tasks[t.ProcessID]
+	if !ok {
+		s = &tsTask{}
+		top := int64(len(p.table) - 1)
+		level := top - min(max(t.Priority, 0), top)
+		if p.highFirst {
+			level = min(max(t.Priority, 0), top)
+		}
+		p.move(s, int(level))
+		p.tasks[t.ProcessID] = s
+	}
+
+	return s
+}
+
+func (p *timeSharing) pick(ready []*task) int {
+	p.account(ready)
+	best := 0
+	for i, t := range ready {
+		s, b := p.tasks[t.ProcessID], p.tasks[ready[best].ProcessID]
+		if s.level > b.level || s.level == b.level && s.seq < b.seq {
+			best = i
+		}
+	}
+	p.tasks[ready[best].ProcessID].since = p.elapsed
+
+	return best
+}
+
+// quantum is a single unit, so that a process at a higher level preempts the
+// running one as soon as it is ready; quanta are kept by account.
+func (*timeSharing) quantum(*task) int64 { return 1 }
+
+// SVR4 time-sharing, with the dispatch table in opts or the default one
+func svr4(processes []Process, opts options) Result {
+	table := opts.dispatchTable
+	if table == nil {
+		table = defaultDispatchTable()
+	}
+
+	return simulate(processes, &timeSharing{table: table, highFirst: opts.highPriorityFirst}, opts)
+}
+
+//endregion
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_svr4(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		table string
+		csv   string
+		want  []TimeSlice
+	}{
+		{
+			name:  "expired quanta drop a level",
+			table: "4,0,1,100,1\n2,0,1,100,1\n",
+			csv:   "1,6,0\n2,6,0\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4},
+				{PID: 1, Start: 4, Stop: 8}, {PID: 2, Start: 8, Stop: 12},
+			},
+		},
+		{
+			name:  "waiting too long raises a level",
+			table: "# quantum,tqexp,slpret,maxwait,lwait\n10,0,0,3,1\n1,0,1,100,1\n",
+			csv:   "1,10,0\n2,10,0\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2},
+				{PID: 1, Start: 2, Stop: 6}, {PID: 2, Start: 6, Stop: 7},
+				{PID: 1, Start: 7, Stop: 11}, {PID: 2, Start: 11, Stop: 12},
+				{PID: 1, Start: 12, Stop: 13}, {PID: 2, Start: 13, Stop: 20},
+			},
+		},
+		{
+			name:  "priority lowers the starting level",
+			table: "3,0,1,100,1\n3,0,1,100,1\n",
+			csv:   "1,3,0,1\n2,3,1\n",
+			want:  []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 4}, {PID: 1, Start: 4, Stop: 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			table, err := loadDispatchTable(strings.NewReader(tt.table))
+			if err != nil {
+				t.Fatal(err)
+			}
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := svr4(processes, options{dispatchTable: table}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("svr4() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadDispatchTable(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		table string
+		want  error
+	}{
+		{"empty", "# nothing\n", ErrParse},
+		{"short row", "4,0,0,100\n", ErrParse},
+		{"not a number", "4,0,0,x,0\n", ErrParse},
+		{"zero quantum", "0,0,0,100,0\n", ErrInvalidArgs},
+		{"missing level", "4,0,1,100,0\n", ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := loadDispatchTable(strings.NewReader(tt.table)); !errors.Is(err, tt.want) {
+				t.Errorf("loadDispatchTable() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// The default table only moves processes to levels it has
+	table := defaultDispatchTable()
+	for i, l := range table {
+		for _, to := range []int{l.tqexp, l.slpret, l.lwait} {
+			if to < 0 || to >= len(table) || l.quantum <= 0 {
+				t.Errorf("default dispatch table level %d = %+v", i, l)
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//region Standard Workload Format
+
+// swfFields is the number of fields in every SWF job line.
+const swfFields = 18
+
+// writeSWF writes processes in the Standard Workload Format used by the
+// Parallel Workloads Archive and academic queueing simulators. Each process
+// is a single-processor job: submit time is the arrival, run and requested
+// time the burst, and the queue number the priority. Fields with no
+// counterpart here are -1. Spawned children have no arrival until they are
+// simulated, so their arrival column is exported as given.
+func writeSWF(w io.Writer, processes []Process) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "; Version: 2.2")
+	_, _ = fmt.Fprintln(bw, "; Computer: Process_Scheduler single CPU")
+	_, _ = fmt.Fprintf(bw, "; MaxJobs: %d\n", len(processes))
+	_, _ = fmt.Fprintf(bw, "; MaxRecords: %d\n", len(processes))
+	_, _ = fmt.Fprintln(bw, "; MaxProcs: 1")
+	_, _ = fmt.Fprintln(bw, "; Note: Queue number is the process priority")
+	for _, p := range processes {
+		fields := [swfFields]int64{
+			p.ProcessID,
+			p.ArrivalTime,
+			-1, // wait time
+			p.BurstDuration,
+			1,  // allocated processors
+			-1, // average CPU time
+			-1, // used memory
+			1,  // requested processors
+			p.BurstDuration,
+			-1, // requested memory
+			-1, // status
+			-1, // user
+			-1, // group
+			-1, // executable
+			p.Priority,
+			-1, // partition
+			-1, // preceding job
+			-1, // think time
+		}
+		for i, f := range fields {
+			if i > 0 {
+				_ = bw.WriteByte(' ')
+			}
+			_, _ = fmt.Fprint(bw, f)
+		}
+		_ = bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+func writeSWFFile(name string, processes []Process) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating SWF file", err)
+	}
+	if err := writeSWF(f, processes); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: error writing SWF file", err)
+	}
+
+	return f.Close()
+}
+
+// loadSWF reads a Standard Workload Format trace. Every job becomes a
+// process arriving at its submit time whose burst is the CPU work the job
+// needs on this single CPU: its run time (or requested time when the run
+// time is unknown) times the processors it requested (or was allocated).
+// The queue number, when known, becomes the priority. Jobs with no known
+// duration, such as ones cancelled before starting, are skipped.
+func loadSWF(r io.Reader) ([]Process, error) {
+	return loadScaledSWF(r, 1)
+}
+
+// loadScaledSWF reads a Standard Workload Format trace whose times are in
+// input units, unit of which make one simulation time unit, so a trace in
+// seconds can keep its fractional times as milliseconds.
+func loadScaledSWF(r io.Reader, unit float64) ([]Process, error) {
+	var processes []Process
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < swfFields {
+			return nil, fmt.Errorf("%w: SWF line %d has %d fields, want %d", ErrParse, line, len(fields), swfFields)
+		}
+		var f [swfFields]int64
+		for i := range f {
+			// Some archives write fractional times, which -time-unit can keep
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: SWF line %d: %w", ErrParse, line, err)
+			}
+			switch i {
+			case 1, 2, 3, 8:
+				// Submit, wait, run and requested times round to whole units
+				// like those of a CSV workload, and a run never rounds down to
+				// nothing. Negative ones are unknown and stay so.
+				if v > 0 {
+					v = math.Round(v / unit)
+					if i == 3 || i == 8 {
+						v = max(v, 1)
+					}
+				}
+			}
+			if !(v >= -(1<<63) && v < 1<<63) {
+				return nil, fmt.Errorf("%w: SWF line %d: field %d is out of range", ErrParse, line, i+1)
+			}
+			f[i] = int64(math.Floor(v))
+		}
+
+		// Only a run time of -1 is unknown; one of 0 leaves nothing to run
+		run := f[3]
+		if run < 0 {
+			run = f[8]
+		}
+		procs := f[7]
+		if procs <= 0 {
+			procs = f[4]
+		}
+		if procs <= 0 {
+			procs = 1
+		}
+		if run <= 0 || f[1] < 0 {
+			continue
+		}
+		if err := checkPID(f[0]); err != nil {
+			return nil, fmt.Errorf("%w: SWF line %d", err, line)
+		}
+		p := Process{
+			ProcessID:     f[0],
+			ArrivalTime:   f[1],
+			BurstDuration: run * procs,
+		}
+		if f[14] >= 0 {
+			p.Priority = f[14]
+		}
+		processes = append(processes, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading SWF", err)
+	}
+	if err := checkUniquePIDs(processes); err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
+//endregion
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_writeSWF(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+	}
+	var w bytes.Buffer
+	if err := writeSWF(&w, processes); err != nil {
+		t.Fatal(err)
+	}
+	var jobs []string
+	for _, line := range strings.Split(strings.TrimSpace(w.String()), "\n") {
+		if !strings.HasPrefix(line, ";") {
+			jobs = append(jobs, line)
+		}
+	}
+	want := []string{
+		"1 0 -1 5 1 -1 -1 1 5 -1 -1 -1 -1 -1 2 -1 -1 -1",
+		"2 3 -1 9 1 -1 -1 1 9 -1 -1 -1 -1 -1 1 -1 -1 -1",
+	}
+	if strings.Join(jobs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("writeSWF() jobs = %q, want %q", jobs, want)
+	}
+}
+
+func Test_loadSWF(t *testing.T) {
+	t.Parallel()
+	trace := `; Version: 2.2
+; Computer: test
+
+1 0 -1 5 1 -1 -1 1 5 -1 1 -1 -1 -1 2 -1 -1 -1
+2 3.5 10 9 4 -1 -1 -1 20 -1 1 -1 -1 -1 -1 -1 -1 -1
+3 6 -1 -1 -1 -1 -1 2 30 -1 5 -1 -1 -1 1 -1 -1 -1
+4 8 -1 -1 -1 -1 -1 2 -1 -1 5 -1 -1 -1 1 -1 -1 -1
+`
+	got, err := loadSWF(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 4, BurstDuration: 36},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 60, Priority: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadSWF() = %v, want %v", got, want)
+	}
+
+	// A workload survives a round trip through SWF
+	var w bytes.Buffer
+	if err := writeSWF(&w, want); err != nil {
+		t.Fatal(err)
+	}
+	back, err := loadSWF(&w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, want) {
+		t.Errorf("loadSWF(writeSWF()) = %v, want %v", back, want)
+	}
+
+	if _, err := loadSWF(strings.NewReader("1 0 5\n")); !errors.Is(err, ErrParse) {
+		t.Errorf("short line error = %v, want %v", err, ErrParse)
+	}
+	// A run shorter than a unit still runs, rather than take the requested time
+	short, err := loadSWF(strings.NewReader("1 0 -1 0.4 1 -1 -1 1 50 -1 1 -1 -1 -1 -1 -1 -1 -1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Process{{ProcessID: 1, BurstDuration: 1}}; !reflect.DeepEqual(short, want) {
+		t.Errorf("loadSWF() short run = %v, want %v", short, want)
+	}
+	dup := "1 0 -1 5 1 -1 -1 1 5 -1 1 -1 -1 -1 2 -1 -1 -1\n1 3 -1 5 1 -1 -1 1 5 -1 1 -1 -1 -1 2 -1 -1 -1\n"
+	if _, err := loadSWF(strings.NewReader(dup)); !errors.Is(err, ErrInvalidWorkload) {
+		t.Errorf("duplicate ID error = %v, want %v", err, ErrInvalidWorkload)
+	}
+}
+
+func Test_loadScaledSWF(t *testing.T) {
+	t.Parallel()
+	trace := "1 0.25 -1 1.5 1 -1 -1 1 -1 -1 1 -1 -1 -1 -1 -1 -1 -1\n"
+	got, err := loadScaledSWF(strings.NewReader(trace), 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Process{{ProcessID: 1, ArrivalTime: 25, BurstDuration: 150}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("loadScaledSWF() = %v, want %v", got, want)
+	}
+
+	// Fractional times round as they do in a CSV workload
+	trace = "1 0.257 -1 1.496 1 -1 -1 1 -1 -1 1 -1 -1 -1 -1 -1 -1 -1\n"
+	if got, err = loadScaledSWF(strings.NewReader(trace), 0.01); err != nil {
+		t.Fatal(err)
+	}
+	fromCSV, err := loadScaledProcesses(strings.NewReader("1,1.496,0.257\n"), 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Process{{ProcessID: 1, ArrivalTime: 26, BurstDuration: 150}}; !reflect.DeepEqual(got, want) || !reflect.DeepEqual(fromCSV, want) {
+		t.Errorf("loadScaledSWF() = %v and loadScaledProcesses() = %v, want %v", got, fromCSV, want)
+	}
+}
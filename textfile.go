@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+//region Prometheus textfile
+
+// textfilePrefix namespaces the metrics in textfile snapshots.
+const textfilePrefix = "process_scheduler_"
+
+// textfileHelp describes each of runMetrics in textfile snapshots.
+var textfileHelp = map[string]string{
+	"avg_wait":       "Average waiting time of the processes.",
+	"avg_turnaround": "Average turnaround time of the processes.",
+	"throughput":     "Processes completed per time unit.",
+	"switches":       "Context switches between processes.",
+	"p50_response":   "Median response time of the processes.",
+	"p99_response":   "99th percentile response time of the processes.",
+	"max_response":   "Longest response time of the processes.",
+	"p99_turnaround": "99th percentile turnaround time of the processes.",
+	"max_turnaround": "Longest turnaround time of the processes.",
+	"events":         "Events the simulator handled.",
+}
+
+// writeMetricsText writes every algorithm's runMetrics in the Prometheus text
+// exposition format, one gauge per metric labelled by algorithm, as read by
+// node_exporter's textfile collector.
+func writeMetricsText(w io.Writer, processes []Process, opts options) error {
+	results := make([]Result, len(algorithms))
+	for i, alg := range algorithms {
+		results[i] = alg.run(processes, opts)
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, metric := range runMetrics {
+		name := textfilePrefix + metric
+		_, _ = fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n", name, textfileHelp[metric], name)
+		for i, alg := range algorithms {
+			v, err := metricValue(metric, results[i])
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(bw, "%s{algorithm=%q} %s\n", name, alg.key, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeMetricsTextFile writes the snapshot to a temporary file beside name
+// and renames it into place, so the textfile collector never reads half of
+// it.
+func writeMetricsTextFile(name string, processes []Process, opts options) error {
+	tmp := name + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("%w: error creating metrics textfile", err)
+	}
+	if err := writeMetricsText(f, processes, opts); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("%w: error writing metrics textfile", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("%w: error writing metrics textfile", err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("%w: error writing metrics textfile", err)
+	}
+
+	return nil
+}
+
+//endregion
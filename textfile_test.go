@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_writeMetricsText(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,4,0\n2,2,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := writeMetricsText(&out, processes, options{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# HELP process_scheduler_avg_wait Average waiting time of the processes.\n# TYPE process_scheduler_avg_wait gauge\n",
+		"process_scheduler_avg_wait{algorithm=\"fcfs\"} 1.5\n",
+		"process_scheduler_switches{algorithm=\"sjf\"} 1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeMetricsText() is missing %q in\n%s", want, out.String())
+		}
+	}
+	if got, want := strings.Count(out.String(), "\n"), len(runMetrics)*(len(algorithms)+2); got != want {
+		t.Errorf("writeMetricsText() wrote %d lines, want %d", got, want)
+	}
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "run.prom")
+	if err := writeMetricsTextFile(name, processes, options{}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(name); err != nil || string(b) != out.String() {
+		t.Errorf("writeMetricsTextFile() wrote %q, %v", b, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("writeMetricsTextFile() left %d files, want 1", len(entries))
+	}
+}
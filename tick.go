@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Timer tick
+
+// defaultTick is the timer tick resolution, one time unit.
+const defaultTick = 1
+
+// checkTick rejects a tick finer than a time unit. Time is counted in whole
+// units, so such a tick would be accepted but behave just like the default.
+func checkTick(tick float64) error {
+	if !(tick >= defaultTick) {
+		return fmt.Errorf("%w: -tick %g must be at least %d, a whole time unit", ErrInvalidArgs, tick, defaultTick)
+	}
+
+	return nil
+}
+
+// nextTick returns the first timer tick at or after at, rounded up to a whole
+// time unit. A tick of 0 leaves at as it is.
+func nextTick(at int64, tick float64) int64 {
+	if tick <= 0 {
+		return at
+	}
+
+	return int64(math.Ceil(math.Ceil(float64(at)/tick-1e-9) * tick))
+}
+
+// outputTick compares each preemptive scheduler under the default tick and
+// the chosen one, showing how a coarse timer stretches quanta and latency.
+func outputTick(w io.Writer, processes []Process, opts options, d display) {
+	fine := opts
+	fine.tick = defaultTick
+	tick := fmt.Sprintf("tick=%g", opts.tick)
+	outputTitle(w, "Timer tick")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Switches tick=1", "Switches " + tick,
+		"Ave Response tick=1", "Ave Response " + tick, "Max Turnaround tick=1", "Max Turnaround " + tick})
+	for _, alg := range algorithms {
+		if !alg.preemptive {
+			continue
+		}
+		base := alg.run(append([]Process(nil), processes...), fine)
+		coarse := alg.run(append([]Process(nil), processes...), opts)
+		lb, lc := latencies(base.processes, base.Gantt), latencies(coarse.processes, coarse.Gantt)
+		table.Append([]string{
+			alg.name,
+			fmt.Sprint(switches(base.Gantt)),
+			fmt.Sprint(switches(coarse.Gantt)),
+			d.format(mean(lb.response)),
+			d.format(mean(lc.response)),
+			fmt.Sprint(percentile(lb.turnaround, 100)),
+			fmt.Sprint(percentile(lc.turnaround, 100)),
+		})
+	}
+	table.Render()
+}
+
+//endregion
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_checkTick(t *testing.T) {
+	t.Parallel()
+	for _, tick := range []float64{0, -1, 0.5, 0.99} {
+		if err := checkTick(tick); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("checkTick(%g) error = %v, want %v", tick, err, ErrInvalidArgs)
+		}
+	}
+	for _, tick := range []float64{1, 2.5, 10} {
+		if err := checkTick(tick); err != nil {
+			t.Errorf("checkTick(%g) error = %v, want nil", tick, err)
+		}
+	}
+}
+
+func Test_nextTick(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		at   int64
+		tick float64
+		want int64
+	}{
+		{"none", 7, 0, 7},
+		{"unit", 7, 1, 7},
+		{"half", 7, 0.5, 7},
+		{"on a tick", 20, 10, 20},
+		{"between ticks", 21, 10, 30},
+		{"fractional", 4, 2.5, 5},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nextTick(tt.at, tt.tick); got != tt.want {
+				t.Errorf("nextTick() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tick(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,10,0\n2,10,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := options{tick: 3}
+	// The quanta of 5 end at 5 and 11, but the timer only notices at 6 and 12
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 6}, {PID: 2, Start: 6, Stop: 12}, {PID: 1, Start: 12, Stop: 16}, {PID: 2, Start: 16, Stop: 20}}
+	if got := rr(processes, opts).Gantt; !reflect.DeepEqual(got, want) {
+		t.Errorf("rr() gantt = %v, want %v", got, want)
+	}
+
+	var out bytes.Buffer
+	outputTick(&out, processes, opts, display{})
+	if !strings.Contains(out.String(), "SWITCHES TICK=3") {
+		t.Errorf("outputTick() =\n%s", out.String())
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+//region Foreground/background
+
+const (
+	// defaultForegroundShare is the percentage of the CPU the foreground
+	// queue gets while both queues have work, unless -fg-share says otherwise.
+	defaultForegroundShare = 80
+	// foregroundSlice is the round-robin time slice of the foreground queue,
+	// the same as round-robin's.
+	foregroundSlice = 5
+)
+
+type (
+	// foregroundBackground is the classic two-queue scheduler: foreground
+	// processes take turns round-robin and background processes run first
+	// come, first served. While both queues have work the CPU is split
+	// between them, share percent to the foreground; otherwise either queue
+	// may use all of it.
+	foregroundBackground struct {
+		share int
+		tasks map[int64]*fgTask
+		seq   int
+		// used is the CPU time each queue received while both had work.
+		fgUsed, bgUsed int64
+		// last is the task dispatched by the last pick, which had run lastRan
+		// then, and contended whether both queues had work.
+		last      *task
+		lastRan   int64
+		contended bool
+	}
+
+	fgTask struct {
+		// slice is what is left of the time slice of a foreground task.
+		slice int64
+		// seq orders tasks by their turn in the foreground queue, and by
+		// when they first became ready in the background one.
+		seq int
+	}
+)
+
+func (p *foregroundBackground) reset() {
+	*p = foregroundBackground{share: p.share, tasks: make(map[int64]*fgTask)}
+}
+
+// charge accounts for the unit the last task dispatched ran, ending its
+// turn if it was a foreground task whose slice ran out.
+func (p *foregroundBackground) charge() {
+	t := p.last
+	if t == nil {
+		return
+	}
+	d := t.ran - p.lastRan
+	if p.contended {
+		if t.Background {
+			p.bgUsed += d
+		} else {
+			p.fgUsed += d
+		}
+	}
+	if s := p.tasks[t.ProcessID]; !t.Background {
+		if s.slice -= d; s.slice <= 0 {
+			s.slice = foregroundSlice
+			p.seq++
+			s.seq = p.seq
+		}
+	}
+}
+
+func (p *foregroundBackground) pick(ready []*task) int {
+	p.charge()
+	fg, bg := -1, -1
+	for i, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		if !ok {
+			p.seq++
+			s = &fgTask{slice: foregroundSlice, seq: p.seq}
+			p.tasks[t.ProcessID] = s
+		}
+		first := &fg
+		if t.Background {
+			first = &bg
+		}
+		if *first < 0 || s.seq < p.tasks[ready[*first].ProcessID].seq {
+			*first = i
+		}
+	}
+
+	// The background runs when it is below its share, or alone
+	p.contended = fg >= 0 && bg >= 0
+	best := fg
+	if fg < 0 || p.contended && p.bgUsed*100 < (p.fgUsed+p.bgUsed)*int64(100-p.share) {
+		best = bg
+	}
+	p.last, p.lastRan = ready[best], ready[best].ran
+
+	return best
+}
+
+// quantum is a single unit, so that the queues can share the CPU finely and
+// a foreground process preempts the background as soon as it is due.
+func (*foregroundBackground) quantum(*task) int64 { return 1 }
+
+// Foreground round-robin and background first-come first-served, sharing
+// the CPU opts.foregroundShare percent to the foreground
+func fgbg(processes []Process, opts options) Result {
+	share := opts.foregroundShare
+	if share == 0 {
+		share = defaultForegroundShare
+	}
+
+	return simulate(processes, &foregroundBackground{share: share}, opts)
+}
+
+//endregion
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_fgbg(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		csv   string
+		share int
+		want  []TimeSlice
+	}{
+		{
+			name: "default split",
+			csv:  "1,8,0\n2,4,0,0,background=true\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2},
+				{PID: 1, Start: 2, Stop: 6}, {PID: 2, Start: 6, Stop: 7},
+				{PID: 1, Start: 7, Stop: 10}, {PID: 2, Start: 10, Stop: 12},
+			},
+		},
+		{
+			name:  "even split",
+			csv:   "1,8,0\n2,4,0,0,background=true\n",
+			share: 50,
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2},
+				{PID: 1, Start: 2, Stop: 3}, {PID: 2, Start: 3, Stop: 4},
+				{PID: 1, Start: 4, Stop: 5}, {PID: 2, Start: 5, Stop: 6},
+				{PID: 1, Start: 6, Stop: 7}, {PID: 2, Start: 7, Stop: 8},
+				{PID: 1, Start: 8, Stop: 12},
+			},
+		},
+		{
+			name: "foreground round-robin",
+			csv:  "1,6,0\n2,6,0\n",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10},
+				{PID: 1, Start: 10, Stop: 11}, {PID: 2, Start: 11, Stop: 12},
+			},
+		},
+		{
+			name: "background first come, first served",
+			csv:  "1,3,0,0,background=true\n2,3,0,0,background=true\n",
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fgbg(processes, options{foregroundShare: tt.share}).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fgbg() gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region FreeBSD ULE
+
+// Tunables of the FreeBSD ULE scheduler, taking one time unit as 10 ms.
+const (
+	// uleInteractMax is the highest interactivity score, half of it
+	// meaning a process has run as long as it has slept.
+	uleInteractMax = 100
+	// uleInteractThresh is the score below which a process is interactive.
+	uleInteractThresh = 30
+	// uleHistoryMax caps the sleep and run history, 5 s.
+	uleHistoryMax = 500
+	// uleSlice is how long a batch process runs before the next one's turn.
+	uleSlice = 10
+)
+
+type (
+	// freebsdULE emulates the interactivity heuristic of FreeBSD's ULE
+	// scheduler. Every process has a score from 0 to 100 computed from how
+	// long it recently slept (was blocked) against how long it ran, plus its
+	// priority as the nice value. Processes scoring under 30 are interactive:
+	// they run before any batch process, preempting it, lowest score first.
+	// Batch processes take turns for a slice each. The history is decayed
+	// once it passes 5 s, so a process that changes behavior is reclassified.
+	freebsdULE struct {
+		tasks map[int64]*uleTask
+		seq   int
+	}
+
+	uleTask struct {
+		// runTime and sleepTime are the decayed history.
+		runTime, sleepTime int64
+		slice              int64
+		// ran and blocked are how long the process had run and been blocked
+		// when last seen, to charge what happened since.
+		ran, blocked int64
+		// seq orders batch processes by when they joined the queue.
+		seq int
+		// nice is the process's nice value.
+		nice int64
+	}
+)
+
+func (p *freebsdULE) reset() {
+	p.tasks = make(map[int64]*uleTask)
+	p.seq = 0
+}
+
+// score is the interactivity score of s, 0 for a process that only slept
+// up to 100 for one that only ran, adjusted by nice.
+func (s *uleTask) score(nice int64) int64 {
+	var score int64
+	switch {
+	case s.sleepTime > s.runTime:
+		score = uleInteractMax / 2 * s.runTime / s.sleepTime
+	case s.runTime > s.sleepTime:
+		score = uleInteractMax - uleInteractMax/2*s.sleepTime/s.runTime
+	case s.runTime > 0:
+		score = uleInteractMax / 2
+	}
+
+	return max(score+nice, 0)
+}
+
+// decay scales the history down once it passes uleHistoryMax, keeping the
+// ratio of sleep to run, as ULE's sched_interact_update does.
+func (s *uleTask) decay() {
+	sum := s.runTime + s.sleepTime
+	switch {
+	case sum < uleHistoryMax:
+	case sum > uleHistoryMax*2:
+		if s.runTime > s.sleepTime {
+			s.runTime, s.sleepTime = uleHistoryMax, 1
+		} else {
+			s.runTime, s.sleepTime = 1, uleHistoryMax
+		}
+	case sum > uleHistoryMax/5*6:
+		s.runTime /= 2
+		s.sleepTime /= 2
+	default:
+		s.runTime = s.runTime / 5 * 4
+		s.sleepTime = s.sleepTime / 5 * 4
+	}
+}
+
+func (p *freebsdULE) interactive(t *task) bool {
+	return p.tasks[t.ProcessID].score(t.nice()) < uleInteractThresh
+}
+
+// account adds what every ready process ran and slept since it was last seen
+// to its history, sending batch processes whose slice ran out to the back of
+// the queue.
+func (p *freebsdULE) account(ready []*task) {
+	for _, t := range ready {
+		s, ok := p.tasks[t.ProcessID]
+		if !ok {
+			p.seq++
+			p.tasks[t.ProcessID] = &uleTask{slice: uleSlice, seq: p.seq, nice: t.nice()}
+			continue
+		}
+		if d := t.ran - s.ran; d > 0 {
+			s.ran = t.ran
+			s.runTime += d
+			if s.slice -= d; s.slice <= 0 {
+				s.slice = uleSlice
+				p.seq++
+				s.seq = p.seq
+			}
+		}
+		if d := t.blockedFor - s.blocked; d > 0 {
+			s.blocked = t.blockedFor
+			s.sleepTime += d
+			s.slice = uleSlice
+			p.seq++
+			s.seq = p.seq
+		}
+		s.decay()
+	}
+}
+
+func (p *freebsdULE) pick(ready []*task) int {
+	p.account(ready)
+	best := 0
+	for i, t := range ready {
+		b := ready[best]
+		ti, bi := p.interactive(t), p.interactive(b)
+		if ti != bi {
+			if ti {
+				best = i
+			}
+			continue
+		}
+		s, bs := p.tasks[t.ProcessID], p.tasks[b.ProcessID]
+		if ti {
+			sc, bsc := s.score(t.nice()), bs.score(b.nice())
+			if sc < bsc || sc == bsc && s.seq < bs.seq {
+				best = i
+			}
+			continue
+		}
+		if s.seq < bs.seq {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// quantum is a single unit, so that an interactive process preempts a batch
+// one as soon as it is ready; slices are kept by account.
+func (*freebsdULE) quantum(*task) int64 { return 1 }
+
+// FreeBSD ULE
+func ule(processes []Process, opts options) Result {
+	r, _ := uleScores(processes, opts)
+
+	return r
+}
+
+// uleScores runs ULE and also returns the interactivity score of every
+// process when it last became ready.
+func uleScores(processes []Process, opts options) (Result, map[int64]int64) {
+	p := &freebsdULE{}
+	r := simulate(processes, p, opts)
+	scores := make(map[int64]int64, len(p.tasks))
+	for _, q := range r.processes {
+		if s, ok := p.tasks[q.ProcessID]; ok {
+			scores[q.ProcessID] = s.score(s.nice)
+		}
+	}
+
+	return r, scores
+}
+
+// outputInteractivity shows the interactivity score ULE last gave every
+// process and whether that made it interactive or batch.
+func outputInteractivity(w io.Writer, processes []Process, opts options) {
+	r, scores := uleScores(append([]Process(nil), processes...), opts)
+
+	outputTitle(w, "ULE interactivity")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Score", "Class"})
+	for _, p := range r.processes {
+		score, ok := scores[p.ProcessID]
+		if !ok {
+			continue
+		}
+		class := "batch"
+		if score < uleInteractThresh {
+			class = "interactive"
+		}
+		table.Append([]string{fmt.Sprint(p.ProcessID), fmt.Sprint(score), class})
+	}
+	table.Render()
+}
+
+//endregion
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_uleScore(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		run, sleep     int64
+		nice           int64
+		want           int64
+		wantAfterDecay [2]int64
+	}{
+		{"new", 0, 0, 0, 0, [2]int64{0, 0}},
+		{"mostly sleeps", 10, 90, 0, 5, [2]int64{10, 90}},
+		{"mostly runs", 90, 10, 0, 95, [2]int64{90, 10}},
+		{"even", 40, 40, 0, 50, [2]int64{40, 40}},
+		{"niced", 10, 90, 10, 15, [2]int64{10, 90}},
+		{"history decayed", 300, 250, 0, 59, [2]int64{240, 200}},
+		{"history halved", 400, 300, 0, 63, [2]int64{200, 150}},
+		{"history reset", 900, 200, 0, 89, [2]int64{uleHistoryMax, 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := &uleTask{runTime: tt.run, sleepTime: tt.sleep}
+			if got := s.score(tt.nice); got != tt.want {
+				t.Errorf("score() = %v, want %v", got, tt.want)
+			}
+			s.decay()
+			if got := [2]int64{s.runTime, s.sleepTime}; got != tt.wantAfterDecay {
+				t.Errorf("decay() = %v, want %v", got, tt.wantAfterDecay)
+			}
+		})
+	}
+}
+
+func Test_ule(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,30,0\n2,4,0,0,await=1;2;3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := options{events: []Event{
+		{Time: 10, Kind: eventSignal, PID: 2},
+		{Time: 20, Kind: eventSignal, PID: 2},
+		{Time: 30, Kind: eventSignal, PID: 2},
+	}}
+	// The hog is batch once it has run, so the sleeper preempts it on waking
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2},
+		{PID: 1, Start: 2, Stop: 10}, {PID: 2, Start: 10, Stop: 11},
+		{PID: 1, Start: 11, Stop: 20}, {PID: 2, Start: 20, Stop: 21},
+		{PID: 1, Start: 21, Stop: 30}, {PID: 2, Start: 30, Stop: 31},
+		{PID: 1, Start: 31, Stop: 34},
+	}
+	if got := ule(processes, opts).Gantt; !reflect.DeepEqual(got, want) {
+		t.Errorf("ule() gantt = %v, want %v", got, want)
+	}
+
+	var out bytes.Buffer
+	outputInteractivity(&out, processes, opts)
+	for _, row := range []string{"|  1 |   100 | batch       |", "|  2 |     5 | interactive |"} {
+		if !strings.Contains(out.String(), row) {
+			t.Errorf("outputInteractivity() missing %q:\n%s", row, out.String())
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import "io"
+
+//region Weighted round-robin
+
+// weightedRoundRobin dispatches ready tasks in turn, like round-robin, but
+// lets each run for as many units as it holds tickets, so that over every
+// rotation the processes share the CPU in proportion to their tickets.
+type weightedRoundRobin struct {
+	shareLedger
+}
+
+func (p *weightedRoundRobin) reset() {
+	p.shareLedger = newShareLedger()
+}
+
+func (p *weightedRoundRobin) pick(ready []*task) int {
+	p.charge()
+	p.dispatch(ready, 0)
+
+	return 0
+}
+
+func (*weightedRoundRobin) quantum(t *task) int64 { return t.tickets() }
+
+// WRRSchedule outputs the schedule of weighted round-robin and the share of
+// the CPU each process achieved against its share of the tickets.
+func WRRSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, wrr(processes, options{}), display{})
+	outputWRR(w, processes, options{})
+}
+
+func wrr(processes []Process, opts options) Result {
+	r, _ := wrrShares(processes, opts)
+
+	return r
+}
+
+// wrrShares runs weighted round-robin and returns what each process got out
+// of it as well.
+func wrrShares(processes []Process, opts options) (Result, *weightedRoundRobin) {
+	p := &weightedRoundRobin{}
+	r := simulate(processes, p, opts)
+	// Charge the last task dispatched
+	p.charge()
+
+	return r, p
+}
+
+// outputWRR shows the share of the CPU each process got under weighted
+// round-robin.
+func outputWRR(w io.Writer, processes []Process, opts options) {
+	_, p := wrrShares(append([]Process(nil), processes...), opts)
+	outputShares(w, "Weighted round-robin shares", processes, p.shareLedger)
+}
+
+//endregion
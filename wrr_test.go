@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_wrr(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,8,0,0,tickets=3\n2,8,0,0,tickets=1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// P1 runs 3 units for every 1 of P2 until it is done
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 4},
+		{PID: 1, Start: 4, Stop: 7}, {PID: 2, Start: 7, Stop: 8},
+		{PID: 1, Start: 8, Stop: 10}, {PID: 2, Start: 10, Stop: 16},
+	}
+	if got := wrr(processes, options{}).Gantt; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrr() gantt = %v, want %v", got, want)
+	}
+
+	// Weights default to 10 less the priority
+	processes, err = loadProcesses(strings.NewReader("1,20,0,2\n2,20,0,5\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := wrr(processes, options{}).Gantt[:2]; !reflect.DeepEqual(got, []TimeSlice{{PID: 1, Start: 0, Stop: 8}, {PID: 2, Start: 8, Stop: 13}}) {
+		t.Errorf("wrr() gantt starts %v, want slices of 8 and 5", got)
+	}
+}
+
+func Test_outputWRR(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,30,0,0,tickets=1\n2,30,0,0,tickets=3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outputWRR(&out, processes, options{})
+	// While both ran, every rotation gave P2 3 units of 4
+	for _, want := range []string{"Weighted round-robin shares", "|   1 |       1 |        40 | 25.0%        | 25.0%          |", "|   2 |       3 |        40 | 75.0%        | 75.0%          |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("outputWRR() missing %q:\n%s", want, out.String())
+		}
+	}
+}